@@ -2,17 +2,23 @@ module github.com/bad33ndj3/mcp-md-index
 
 go 1.24.1
 
-require github.com/modelcontextprotocol/go-sdk v1.1.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/JohannesKaufmann/html-to-markdown/v2 v2.5.0
+	github.com/andybalholm/cascadia v1.3.3
+	github.com/modelcontextprotocol/go-sdk v1.1.0
+	github.com/ollama/ollama v0.13.5
+	github.com/yuin/goldmark v1.7.13
+	golang.org/x/net v0.47.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/JohannesKaufmann/dom v0.2.0 // indirect
-	github.com/JohannesKaufmann/html-to-markdown/v2 v2.5.0 // indirect
 	github.com/google/jsonschema-go v0.3.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
-	github.com/ollama/ollama v0.13.5 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
 	golang.org/x/crypto v0.44.0 // indirect
-	golang.org/x/net v0.47.0 // indirect
 	golang.org/x/oauth2 v0.30.0 // indirect
 	golang.org/x/sys v0.38.0 // indirect
 )
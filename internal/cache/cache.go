@@ -38,8 +38,13 @@ type Cache interface {
 	LoadFromDisk(docID string) (*domain.Index, error)
 
 	// SaveToDisk persists an index to disk for future sessions.
+	// Chunk embeddings go to a binary sidecar next to the JSON index.
 	SaveToDisk(idx *domain.Index) error
 
+	// LoadVectors fills chunk embeddings from the binary sidecar.
+	// Indexes from LoadFromDisk come without vectors until this is called.
+	LoadVectors(idx *domain.Index) error
+
 	// SaveMarkdown saves the raw markdown content to a file.
 	// Returns the path to the saved file.
 	SaveMarkdown(docID string, content string) (string, error)
@@ -57,18 +62,34 @@ type FileCache struct {
 	cacheDir string                   // Directory where .index.json files are stored
 	mem      map[string]*domain.Index // In-memory cache for current session
 	mu       sync.RWMutex             // Protects concurrent access to mem
+	encoding VectorEncoding           // How embeddings are written to .vec sidecars
+	vecMu    sync.Mutex               // Serializes lazy vector loading
+}
+
+// FileCacheOption configures a FileCache.
+type FileCacheOption func(*FileCache)
+
+// WithVectorEncoding sets how embeddings are stored on disk (default: float32).
+func WithVectorEncoding(enc VectorEncoding) FileCacheOption {
+	return func(c *FileCache) {
+		c.encoding = enc
+	}
 }
 
 // NewFileCache creates a new FileCache that stores files in the given directory.
 // The directory is created if it doesn't exist.
-func NewFileCache(cacheDir string) (*FileCache, error) {
+func NewFileCache(cacheDir string, opts ...FileCacheOption) (*FileCache, error) {
 	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
 		return nil, fmt.Errorf("create cache dir: %w", err)
 	}
-	return &FileCache{
+	c := &FileCache{
 		cacheDir: cacheDir,
 		mem:      make(map[string]*domain.Index),
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
 }
 
 // Get retrieves an index from the in-memory cache.
@@ -120,7 +141,8 @@ func (c *FileCache) LoadFromDisk(docID string) (*domain.Index, error) {
 	return &idx, nil
 }
 
-// SaveToDisk saves an index to the cache directory as a JSON file.
+// SaveToDisk saves an index to the cache directory as a JSON file,
+// with embeddings in a binary <docID>.vec sidecar.
 func (c *FileCache) SaveToDisk(idx *domain.Index) error {
 	path := c.indexPath(idx.DocID)
 
@@ -133,6 +155,12 @@ func (c *FileCache) SaveToDisk(idx *domain.Index) error {
 		return fmt.Errorf("write cache file: %w", err)
 	}
 
+	if err := c.saveVectors(idx); err != nil {
+		return err
+	}
+	// The in-memory vectors are now the source of truth
+	idx.VectorsLoaded = true
+
 	return nil
 }
 
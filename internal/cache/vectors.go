@@ -0,0 +1,387 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// VectorEncoding selects how embeddings are stored in the binary sidecar.
+type VectorEncoding uint8
+
+const (
+	// EncodingFloat32 stores vectors losslessly (4 bytes per dimension).
+	EncodingFloat32 VectorEncoding = iota
+	// EncodingFloat16 stores IEEE half-precision floats (2 bytes per dimension).
+	EncodingFloat16
+	// EncodingInt8 stores scalar-quantized bytes plus one float32 scale per
+	// vector (1 byte per dimension). Loaded vectors stay quantized in memory.
+	EncodingInt8
+)
+
+// ParseVectorEncoding converts a flag value ("float32", "float16", "int8").
+func ParseVectorEncoding(s string) (VectorEncoding, error) {
+	switch s {
+	case "float32", "":
+		return EncodingFloat32, nil
+	case "float16":
+		return EncodingFloat16, nil
+	case "int8":
+		return EncodingInt8, nil
+	default:
+		return 0, fmt.Errorf("unknown vector encoding %q (want float32, float16 or int8)", s)
+	}
+}
+
+// String returns the flag name of the encoding.
+func (e VectorEncoding) String() string {
+	switch e {
+	case EncodingFloat16:
+		return "float16"
+	case EncodingInt8:
+		return "int8"
+	default:
+		return "float32"
+	}
+}
+
+// vectorMagic identifies sidecar files; the byte after it is the format version.
+const (
+	vectorMagic   = "MDXV"
+	vectorVersion = 1
+)
+
+// vectorHeader follows the magic and version bytes.
+type vectorHeader struct {
+	Encoding uint8
+	Dims     uint32
+	Count    uint32 // Number of chunk records; must equal len(Index.Chunks)
+}
+
+// vectorPath returns the sidecar path for a docID's embeddings.
+func (c *FileCache) vectorPath(docID string) string {
+	return filepath.Join(c.cacheDir, fmt.Sprintf("%s.vec", docID))
+}
+
+// chunkVector returns a chunk's embedding as float32, dequantizing if needed.
+func chunkVector(ch domain.Chunk) []float32 {
+	if len(ch.Embedding) > 0 {
+		return ch.Embedding
+	}
+	if len(ch.Quantized) > 0 {
+		return Dequantize(ch.Quantized, ch.QuantScale)
+	}
+	return nil
+}
+
+// saveVectors writes chunk embeddings to the binary sidecar.
+// The sidecar is removed when no chunk has an embedding.
+func (c *FileCache) saveVectors(idx *domain.Index) error {
+	path := c.vectorPath(idx.DocID)
+
+	dims := 0
+	for _, ch := range idx.Chunks {
+		if v := chunkVector(ch); len(v) > 0 {
+			dims = len(v)
+			break
+		}
+	}
+	if dims == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove vector file: %w", err)
+		}
+		return nil
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create vector file: %w", err)
+	}
+	w := bufio.NewWriter(f)
+
+	err = writeVectors(w, idx.Chunks, c.encoding, dims)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write vector file: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// writeVectors encodes one presence byte per chunk, followed by its vector.
+func writeVectors(w io.Writer, chunks []domain.Chunk, enc VectorEncoding, dims int) error {
+	if _, err := io.WriteString(w, vectorMagic); err != nil {
+		return err
+	}
+	hdr := vectorHeader{Encoding: uint8(enc), Dims: uint32(dims), Count: uint32(len(chunks))}
+	if err := binary.Write(w, binary.LittleEndian, uint8(vectorVersion)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, hdr); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 4*dims)
+	for _, ch := range chunks {
+		// Already-quantized chunks are written as-is to avoid re-rounding
+		if enc == EncodingInt8 && len(ch.Embedding) == 0 && len(ch.Quantized) == dims {
+			if err := writeInt8(w, buf, ch.Quantized, ch.QuantScale); err != nil {
+				return err
+			}
+			continue
+		}
+
+		v := chunkVector(ch)
+		if len(v) != dims {
+			if _, err := w.Write([]byte{0}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var err error
+		switch enc {
+		case EncodingFloat16:
+			b := buf[:1+2*dims]
+			b[0] = 1
+			for i, x := range v {
+				binary.LittleEndian.PutUint16(b[1+2*i:], float32ToHalf(x))
+			}
+			_, err = w.Write(b)
+		case EncodingInt8:
+			q, scale := Quantize(v)
+			err = writeInt8(w, buf, q, scale)
+		default:
+			b := buf[:0]
+			b = append(b, 1)
+			for _, x := range v {
+				b = binary.LittleEndian.AppendUint32(b, math.Float32bits(x))
+			}
+			_, err = w.Write(b)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeInt8 writes a present quantized vector: flag, scale, bytes.
+func writeInt8(w io.Writer, buf []byte, q []int8, scale float32) error {
+	b := buf[:0]
+	b = append(b, 1)
+	b = binary.LittleEndian.AppendUint32(b, math.Float32bits(scale))
+	for _, x := range q {
+		b = append(b, byte(x))
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// LoadVectors fills chunk embeddings from the binary sidecar.
+// It runs at most once per in-memory index (later calls are no-ops) and never
+// overwrites a chunk that already has a vector. A missing sidecar is not an error.
+func (c *FileCache) LoadVectors(idx *domain.Index) error {
+	c.vecMu.Lock()
+	defer c.vecMu.Unlock()
+
+	if idx.VectorsLoaded {
+		return nil
+	}
+
+	f, err := os.Open(c.vectorPath(idx.DocID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			idx.VectorsLoaded = true
+			return nil
+		}
+		return fmt.Errorf("open vector file: %w", err)
+	}
+	defer f.Close()
+
+	if err := readVectors(bufio.NewReader(f), idx.Chunks); err != nil {
+		return fmt.Errorf("read vector file: %w", err)
+	}
+	idx.VectorsLoaded = true
+	return nil
+}
+
+// errVectorFormat is returned for sidecars that don't match the index.
+var errVectorFormat = errors.New("invalid or mismatched vector file")
+
+// readVectors decodes a sidecar into chunks (in order).
+func readVectors(r io.Reader, chunks []domain.Chunk) error {
+	magic := make([]byte, len(vectorMagic)+1)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return err
+	}
+	if string(magic[:len(vectorMagic)]) != vectorMagic || magic[len(vectorMagic)] != vectorVersion {
+		return errVectorFormat
+	}
+
+	var hdr vectorHeader
+	if err := binary.Read(r, binary.LittleEndian, &hdr); err != nil {
+		return err
+	}
+	if int(hdr.Count) != len(chunks) {
+		return errVectorFormat
+	}
+	dims := int(hdr.Dims)
+	enc := VectorEncoding(hdr.Encoding)
+
+	var size int
+	switch enc {
+	case EncodingFloat32:
+		size = 4 * dims
+	case EncodingFloat16:
+		size = 2 * dims
+	case EncodingInt8:
+		size = 4 + dims
+	default:
+		return errVectorFormat
+	}
+
+	flag := make([]byte, 1)
+	buf := make([]byte, size)
+	for i := range chunks {
+		if _, err := io.ReadFull(r, flag); err != nil {
+			return err
+		}
+		if flag[0] == 0 {
+			continue
+		}
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return err
+		}
+
+		ch := &chunks[i]
+		if len(ch.Embedding) > 0 || len(ch.Quantized) > 0 {
+			continue // Newer in-memory vector wins
+		}
+
+		switch enc {
+		case EncodingFloat32:
+			v := make([]float32, dims)
+			for j := range v {
+				v[j] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*j:]))
+			}
+			ch.Embedding = v
+		case EncodingFloat16:
+			v := make([]float32, dims)
+			for j := range v {
+				v[j] = halfToFloat32(binary.LittleEndian.Uint16(buf[2*j:]))
+			}
+			ch.Embedding = v
+		case EncodingInt8:
+			ch.QuantScale = math.Float32frombits(binary.LittleEndian.Uint32(buf))
+			q := make([]int8, dims)
+			for j := range q {
+				q[j] = int8(buf[4+j])
+			}
+			ch.Quantized = q
+		}
+	}
+	return nil
+}
+
+// Quantize maps v to int8 with a per-vector scale: v[i] ≈ q[i] * scale.
+func Quantize(v []float32) ([]int8, float32) {
+	var maxAbs float64
+	for _, x := range v {
+		maxAbs = math.Max(maxAbs, math.Abs(float64(x)))
+	}
+	q := make([]int8, len(v))
+	if maxAbs == 0 {
+		return q, 0
+	}
+	scale := maxAbs / 127
+	for i, x := range v {
+		q[i] = int8(math.Round(float64(x) / scale))
+	}
+	return q, float32(scale)
+}
+
+// Dequantize reverses Quantize.
+func Dequantize(q []int8, scale float32) []float32 {
+	v := make([]float32, len(q))
+	for i, x := range q {
+		v[i] = float32(x) * scale
+	}
+	return v
+}
+
+// float32ToHalf converts to IEEE 754 binary16 with round-to-nearest-even.
+func float32ToHalf(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int((bits>>23)&0xff) - 127 + 15
+	mant := bits & 0x7fffff
+
+	switch {
+	case (bits>>23)&0xff == 0xff: // Inf or NaN
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	case exp >= 0x1f: // Overflow -> Inf
+		return sign | 0x7c00
+	case exp <= 0: // Subnormal or zero
+		if exp < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint32(14 - exp)
+		half := uint16(mant >> shift)
+		rem := mant & (1<<shift - 1)
+		mid := uint32(1) << (shift - 1)
+		if rem > mid || (rem == mid && half&1 == 1) {
+			half++
+		}
+		return sign | half
+	}
+
+	half := sign | uint16(exp)<<10 | uint16(mant>>13)
+	rem := mant & 0x1fff
+	if rem > 0x1000 || (rem == 0x1000 && half&1 == 1) {
+		half++ // May carry into the exponent, which is still correct
+	}
+	return half
+}
+
+// halfToFloat32 converts IEEE 754 binary16 to float32.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+
+	switch {
+	case exp == 0x1f: // Inf or NaN
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	case exp == 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		// Subnormal: normalize the mantissa
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		mant &= 0x3ff
+		return math.Float32frombits(sign | e<<23 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"math"
+	"testing"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// vectorIndex builds an index with two embedded chunks and one without.
+func vectorIndex(docID string) *domain.Index {
+	return &domain.Index{
+		DocID: docID,
+		Path:  "docs/vec.md",
+		Chunks: []domain.Chunk{
+			{ChunkID: docID + ":1-2", Embedding: []float32{0.5, -0.25, 1, 0}},
+			{ChunkID: docID + ":3-4"},
+			{ChunkID: docID + ":5-6", Embedding: []float32{-1, 0.125, 0.75, 0.3}},
+		},
+		NumChunks: 3,
+		Version:   domain.CacheVersion,
+	}
+}
+
+// TestFileCache_VectorSidecarRoundTrip verifies lazy loading for each encoding.
+func TestFileCache_VectorSidecarRoundTrip(t *testing.T) {
+	tests := []struct {
+		enc       VectorEncoding
+		tolerance float64
+	}{
+		{EncodingFloat32, 0},
+		{EncodingFloat16, 1e-3},
+		{EncodingInt8, 1e-2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.enc.String(), func(t *testing.T) {
+			cache, err := NewFileCache(t.TempDir(), WithVectorEncoding(tt.enc))
+			if err != nil {
+				t.Fatalf("NewFileCache: %v", err)
+			}
+			orig := vectorIndex("vec123")
+			if err := cache.SaveToDisk(orig); err != nil {
+				t.Fatalf("SaveToDisk: %v", err)
+			}
+
+			loaded, err := cache.LoadFromDisk("vec123")
+			if err != nil {
+				t.Fatalf("LoadFromDisk: %v", err)
+			}
+			if loaded.Chunks[0].Embedding != nil || loaded.VectorsLoaded {
+				t.Fatal("vectors should not be loaded until LoadVectors")
+			}
+
+			if err := cache.LoadVectors(loaded); err != nil {
+				t.Fatalf("LoadVectors: %v", err)
+			}
+			if loaded.Chunks[1].Embedding != nil || loaded.Chunks[1].Quantized != nil {
+				t.Error("chunk without embedding should stay empty")
+			}
+
+			for _, i := range []int{0, 2} {
+				got := chunkVector(loaded.Chunks[i])
+				want := orig.Chunks[i].Embedding
+				if len(got) != len(want) {
+					t.Fatalf("chunk %d: len = %d, want %d", i, len(got), len(want))
+				}
+				for j := range want {
+					if diff := math.Abs(float64(got[j] - want[j])); diff > tt.tolerance {
+						t.Errorf("chunk %d dim %d: got %v, want %v", i, j, got[j], want[j])
+					}
+				}
+			}
+
+			if tt.enc == EncodingInt8 && loaded.Chunks[0].Quantized == nil {
+				t.Error("int8 vectors should stay quantized in memory")
+			}
+		})
+	}
+}
+
+// TestFileCache_LoadVectorsMissingSidecar verifies docs without vectors load cleanly.
+func TestFileCache_LoadVectorsMissingSidecar(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+	idx := &domain.Index{DocID: "novec", Chunks: []domain.Chunk{{ChunkID: "a"}}}
+
+	if err := cache.LoadVectors(idx); err != nil {
+		t.Fatalf("LoadVectors: %v", err)
+	}
+	if !idx.VectorsLoaded {
+		t.Error("expected VectorsLoaded after a missing sidecar")
+	}
+}
+
+func TestHalfConversion(t *testing.T) {
+	for _, f := range []float32{0, 1, -1, 0.5, 65504, 6.1035156e-05, 5.9604645e-08, -2.75} {
+		if got := halfToFloat32(float32ToHalf(f)); got != f {
+			t.Errorf("half round trip of %v = %v", f, got)
+		}
+	}
+	if got := halfToFloat32(float32ToHalf(1e6)); !math.IsInf(float64(got), 1) {
+		t.Errorf("overflow should become +Inf, got %v", got)
+	}
+}
@@ -7,7 +7,7 @@ import "time"
 
 // CacheVersion is incremented when the cache format changes.
 // This ensures old, incompatible caches are rejected and rebuilt.
-const CacheVersion = 4
+const CacheVersion = 6
 
 // DefaultMaxTokens is the default token limit for query responses.
 const DefaultMaxTokens = 500
@@ -70,9 +70,19 @@ type Chunk struct {
 	// HasCode indicates if this chunk contains code blocks (for quick filtering)
 	HasCode bool `json:"has_code,omitempty"`
 
+	// Metadata is the document's front matter (e.g. title, tags, version).
+	// Every chunk of a document shares the same map.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	// Embedding is the vector representation of this chunk (optional, experimental).
 	// Only populated when --experimental-embeddings is enabled.
-	Embedding []float32 `json:"embedding,omitempty"`
+	// Stored in a binary sidecar file, not in the JSON index.
+	Embedding []float32 `json:"-"`
+
+	// Quantized is an int8 scalar-quantized embedding (Embedding ≈ Quantized * QuantScale).
+	// Set instead of Embedding when vectors were loaded from an int8 sidecar.
+	Quantized  []int8  `json:"-"`
+	QuantScale float32 `json:"-"`
 }
 
 // Index represents a fully parsed and indexed markdown document.
@@ -104,6 +114,62 @@ type Index struct {
 	// NumChunks is len(Chunks), stored for quick access in scoring
 	NumChunks int `json:"num_chunks"`
 
+	// Metadata is the document's front matter with lowercased keys; list
+	// values are joined with ", " (e.g. "tags": "kafka, streaming")
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	// Version identifies the cache format version
 	Version int `json:"version"`
+
+	// EmbeddingState records which model produced the chunk embeddings and
+	// whether every chunk has one. Nil means embeddings were never generated.
+	EmbeddingState *EmbeddingState `json:"embedding_state,omitempty"`
+
+	// Fetch records how a site document was fetched, for revalidating it
+	// once its TTL has passed. Nil for local files.
+	Fetch *FetchInfo `json:"fetch,omitempty"`
+
+	// VectorsLoaded is true once chunk embeddings have been read from the
+	// binary sidecar (vectors are loaded lazily, on first semantic search).
+	VectorsLoaded bool `json:"-"`
+}
+
+// EmbeddingState describes the stored embeddings of an Index.
+// It records the identity of the embedder that produced them, so a restarted
+// server can trust cached vectors or detect that they must be regenerated.
+type EmbeddingState struct {
+	// Provider is the embedding backend (e.g. "ollama", "openai", "builtin")
+	Provider string `json:"provider,omitempty"`
+
+	// Model is the embedding model name reported by the embedder
+	Model string `json:"model"`
+
+	// Dimensions is the length of every chunk embedding
+	Dimensions int `json:"dimensions"`
+
+	// TextVersion identifies how chunk text was prepared before embedding.
+	// Bumped when that preparation changes, which invalidates old vectors.
+	TextVersion int `json:"text_version,omitempty"`
+
+	// Complete is true when every chunk has an embedding
+	Complete bool `json:"complete"`
+}
+
+// FetchInfo describes the HTTP response a site document was indexed from.
+type FetchInfo struct {
+	// ETag and LastModified are the response's validators, sent back in a
+	// conditional GET (If-None-Match, If-Modified-Since)
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+
+	// FetchedAt is when the content was last fetched or revalidated
+	FetchedAt time.Time `json:"fetched_at"`
+
+	// TTL is how long the document is fresh after FetchedAt; 0 means it
+	// never goes stale
+	TTL time.Duration `json:"ttl,omitempty"`
+
+	// Metadata is what the loader added to the front matter (e.g. the
+	// "crawl" a page belongs to), kept when the document is re-indexed
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
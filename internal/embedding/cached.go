@@ -0,0 +1,121 @@
+package embedding
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultQueryCacheSize is how many query embeddings CachedEmbedder keeps.
+const DefaultQueryCacheSize = 256
+
+// CachedEmbedder wraps an Embedder with an LRU cache for single-text embeddings
+// (search queries) and collapses concurrent requests for the same text into one
+// call. EmbedBatch, used for document chunks, is passed through uncached.
+//
+// Cached vectors are shared between callers and must not be modified.
+type CachedEmbedder struct {
+	Embedder
+
+	size int
+
+	mu       sync.Mutex
+	lru      *list.List               // Front = most recently used
+	items    map[string]*list.Element // key -> element holding *cacheEntry
+	inflight map[string]*inflightCall
+}
+
+// cacheEntry is one cached query embedding.
+type cacheEntry struct {
+	key string
+	vec []float32
+}
+
+// inflightCall is an Embed call that other callers with the same key wait on.
+type inflightCall struct {
+	done chan struct{}
+	vec  []float32
+	err  error
+}
+
+// NewCachedEmbedder wraps e with a cache holding up to size query embeddings.
+func NewCachedEmbedder(e Embedder, size int) *CachedEmbedder {
+	if size <= 0 {
+		size = DefaultQueryCacheSize
+	}
+	return &CachedEmbedder{
+		Embedder: e,
+		size:     size,
+		lru:      list.New(),
+		items:    make(map[string]*list.Element),
+		inflight: make(map[string]*inflightCall),
+	}
+}
+
+// Embed returns the cached embedding for text, or computes it once even if
+// several goroutines ask for the same text at the same time.
+func (c *CachedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	key := c.cacheKey(text)
+
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		c.lru.MoveToFront(el)
+		vec := el.Value.(*cacheEntry).vec
+		c.mu.Unlock()
+		return vec, nil
+	}
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.vec, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &inflightCall{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	call.vec, call.err = c.Embedder.Embed(ctx, text)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if call.err == nil {
+		c.addLocked(key, call.vec)
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.vec, call.err
+}
+
+// Len returns the number of cached embeddings.
+func (c *CachedEmbedder) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// cacheKey identifies a text under the wrapped embedder's model, so vectors
+// from different models never mix even if the embedder is reconfigured.
+func (c *CachedEmbedder) cacheKey(text string) string {
+	return fmt.Sprintf("%s\x00%s\x00%d\x00%s", c.Provider(), c.Model(), c.Dimensions(), text)
+}
+
+// addLocked inserts a vector and evicts the least recently used entries.
+// Caller must hold c.mu.
+func (c *CachedEmbedder) addLocked(key string, vec []float32) {
+	if el, ok := c.items[key]; ok {
+		el.Value.(*cacheEntry).vec = vec
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.lru.PushFront(&cacheEntry{key: key, vec: vec})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,96 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingEmbedder counts Embed calls and optionally blocks until released.
+type countingEmbedder struct {
+	HashEmbedder
+	calls   atomic.Int32
+	release chan struct{}
+	fail    bool
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.calls.Add(1)
+	if e.release != nil {
+		<-e.release
+	}
+	if e.fail {
+		return nil, errors.New("service down")
+	}
+	return e.HashEmbedder.Embed(ctx, text)
+}
+
+func TestCachedEmbedder_CachesQueries(t *testing.T) {
+	inner := &countingEmbedder{HashEmbedder: *NewHashEmbedder(16)}
+	c := NewCachedEmbedder(inner, 2)
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := c.Embed(ctx, "consumer"); err != nil {
+			t.Fatalf("Embed: %v", err)
+		}
+	}
+	if n := inner.calls.Load(); n != 1 {
+		t.Errorf("inner calls = %d, want 1", n)
+	}
+
+	// Filling the cache evicts the least recently used entry
+	c.Embed(ctx, "stream")
+	c.Embed(ctx, "subject")
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+	c.Embed(ctx, "consumer")
+	if n := inner.calls.Load(); n != 4 {
+		t.Errorf("inner calls = %d, want 4 after eviction", n)
+	}
+}
+
+func TestCachedEmbedder_DeduplicatesConcurrentRequests(t *testing.T) {
+	inner := &countingEmbedder{HashEmbedder: *NewHashEmbedder(16), release: make(chan struct{})}
+	c := NewCachedEmbedder(inner, 8)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Embed(context.Background(), "consumer"); err != nil {
+				t.Errorf("Embed: %v", err)
+			}
+		}()
+	}
+
+	// Late arrivals either join the in-flight call or hit the cache
+	for inner.calls.Load() == 0 {
+		runtime.Gosched()
+	}
+	close(inner.release)
+	wg.Wait()
+
+	if n := inner.calls.Load(); n != 1 {
+		t.Errorf("inner calls = %d, want 1", n)
+	}
+}
+
+func TestCachedEmbedder_DoesNotCacheErrors(t *testing.T) {
+	inner := &countingEmbedder{HashEmbedder: *NewHashEmbedder(16), fail: true}
+	c := NewCachedEmbedder(inner, 8)
+
+	for range 2 {
+		if _, err := c.Embed(context.Background(), "consumer"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if n := inner.calls.Load(); n != 2 {
+		t.Errorf("inner calls = %d, want 2", n)
+	}
+}
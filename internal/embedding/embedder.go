@@ -1,4 +1,5 @@
-// Package embedding provides async vector embedding generation via Ollama.
+// Package embedding provides async vector embedding generation via Ollama
+// or any OpenAI-compatible /v1/embeddings server.
 // Embeddings are generated in the background after document loading,
 // allowing queries to use BM25 until embeddings are ready.
 package embedding
@@ -32,6 +33,17 @@ type Embedder interface {
 
 	// Available returns true if the embedding service is reachable.
 	Available(ctx context.Context) bool
+
+	// Provider names the backend, e.g. "ollama", "openai" or "builtin".
+	Provider() string
+
+	// Model returns the name of the embedding model.
+	// Stored with each index so cached vectors from another model are not reused.
+	Model() string
+
+	// Dimensions returns the configured vector size, or 0 if it is only
+	// known after the first embedding (the model's default).
+	Dimensions() int
 }
 
 // Status tracks whether embeddings are ready for each document.
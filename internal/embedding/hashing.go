@@ -0,0 +1,133 @@
+package embedding
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/text"
+)
+
+// DefaultHashDimensions is the vector size used by HashEmbedder when none is given.
+const DefaultHashDimensions = 512
+
+// Feature weights for the hashing embedder.
+// Whole terms carry the most signal; trigrams give fuzzy matching across
+// inflections ("consumer" vs "consumers"), bigrams capture short phrases.
+const (
+	hashTermWeight    = 1.0
+	hashBigramWeight  = 0.5
+	hashTrigramWeight = 0.3
+)
+
+// HashEmbedder is a pure-Go, in-process embedder based on feature hashing.
+// It needs no model files and no external service, so hybrid search works on
+// CPU-only laptops and CI machines with zero setup.
+//
+// Each text is turned into normalized terms, term bigrams and character
+// trigrams. Every feature is hashed into one of Dimensions buckets with a
+// pseudo-random sign (the "hashing trick"), and the result is L2-normalized so
+// cosine similarity behaves like a TF-weighted bag-of-words overlap.
+type HashEmbedder struct {
+	dims int
+}
+
+// NewHashEmbedder creates a hashing embedder with the given vector size.
+// Non-positive sizes fall back to DefaultHashDimensions.
+func NewHashEmbedder(dims int) *HashEmbedder {
+	if dims <= 0 {
+		dims = DefaultHashDimensions
+	}
+	return &HashEmbedder{dims: dims}
+}
+
+// Embed generates a single embedding vector.
+func (e *HashEmbedder) Embed(ctx context.Context, s string) ([]float32, error) {
+	return e.embed(s), nil
+}
+
+// EmbedBatch generates embeddings for multiple texts.
+func (e *HashEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	out := make([][]float32, len(texts))
+	for i, s := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out[i] = e.embed(s)
+	}
+	return out, nil
+}
+
+// Available always returns true: the embedder runs in-process.
+func (e *HashEmbedder) Available(ctx context.Context) bool {
+	return true
+}
+
+// Provider returns "builtin".
+func (e *HashEmbedder) Provider() string {
+	return "builtin"
+}
+
+// Model identifies the hashing scheme; Dimensions are checked separately.
+func (e *HashEmbedder) Model() string {
+	return "feature-hashing"
+}
+
+// Dimensions returns the configured vector size.
+func (e *HashEmbedder) Dimensions() int {
+	return e.dims
+}
+
+// embed hashes the features of s into a normalized vector.
+func (e *HashEmbedder) embed(s string) []float32 {
+	acc := make([]float64, e.dims)
+	terms := text.NormalizeTerms(s)
+
+	for i, term := range terms {
+		e.addFeature(acc, "t:"+term, hashTermWeight)
+		if i > 0 {
+			e.addFeature(acc, "b:"+terms[i-1]+" "+term, hashBigramWeight)
+		}
+		padded := "^" + term + "$"
+		for j := 0; j+3 <= len(padded); j++ {
+			e.addFeature(acc, "c:"+padded[j:j+3], hashTrigramWeight)
+		}
+	}
+
+	// Sublinear scaling keeps repeated terms from dominating long chunks
+	var norm float64
+	for i, v := range acc {
+		if v == 0 {
+			continue
+		}
+		scaled := math.Copysign(math.Log1p(math.Abs(v)), v)
+		acc[i] = scaled
+		norm += scaled * scaled
+	}
+
+	vec := make([]float32, e.dims)
+	if norm == 0 {
+		return vec
+	}
+	norm = math.Sqrt(norm)
+	for i, v := range acc {
+		vec[i] = float32(v / norm)
+	}
+	return vec
+}
+
+// addFeature adds a signed, weighted feature to its hash bucket.
+func (e *HashEmbedder) addFeature(acc []float64, feature string, weight float64) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+
+	bucket := int(sum % uint64(e.dims))
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	acc[bucket] += weight
+}
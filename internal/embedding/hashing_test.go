@@ -0,0 +1,66 @@
+package embedding
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func dot(a, b []float32) float64 {
+	var s float64
+	for i := range a {
+		s += float64(a[i]) * float64(b[i])
+	}
+	return s
+}
+
+func TestHashEmbedder_SimilarTextsScoreHigher(t *testing.T) {
+	e := NewHashEmbedder(0)
+	ctx := context.Background()
+
+	vecs, err := e.EmbedBatch(ctx, []string{
+		"Configure a durable consumer on the stream",
+		"Durable consumers keep their state on the stream",
+		"Install the CLI with Homebrew on macOS",
+	})
+	if err != nil {
+		t.Fatalf("EmbedBatch: %v", err)
+	}
+
+	if len(vecs[0]) != DefaultHashDimensions {
+		t.Fatalf("dims = %d, want %d", len(vecs[0]), DefaultHashDimensions)
+	}
+	if n := dot(vecs[0], vecs[0]); math.Abs(n-1) > 1e-5 {
+		t.Errorf("expected unit vector, got squared norm %f", n)
+	}
+
+	related, unrelated := dot(vecs[0], vecs[1]), dot(vecs[0], vecs[2])
+	if related <= unrelated {
+		t.Errorf("related similarity %f should exceed unrelated %f", related, unrelated)
+	}
+}
+
+func TestHashEmbedder_Deterministic(t *testing.T) {
+	e := NewHashEmbedder(64)
+	a, _ := e.Embed(context.Background(), "stream consumer")
+	b, _ := e.Embed(context.Background(), "stream consumer")
+
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("embedding differs at %d: %v vs %v", i, a[i], b[i])
+		}
+	}
+	if !e.Available(context.Background()) {
+		t.Error("hash embedder should always be available")
+	}
+}
+
+func TestHashEmbedder_EmptyText(t *testing.T) {
+	vec, err := NewHashEmbedder(16).Embed(context.Background(), "the and or")
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if dot(vec, vec) != 0 {
+		t.Error("stopword-only text should embed to the zero vector")
+	}
+}
@@ -76,3 +76,18 @@ func (e *OllamaEmbedder) Available(ctx context.Context) bool {
 	_, err := e.client.Version(ctx)
 	return err == nil
 }
+
+// Provider returns "ollama".
+func (e *OllamaEmbedder) Provider() string {
+	return "ollama"
+}
+
+// Model returns the embedding model name.
+func (e *OllamaEmbedder) Model() string {
+	return e.model
+}
+
+// Dimensions returns 0: Ollama models have a fixed, model-defined size.
+func (e *OllamaEmbedder) Dimensions() int {
+	return 0
+}
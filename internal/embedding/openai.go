@@ -0,0 +1,203 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIConfig holds settings for an OpenAI-compatible /v1/embeddings server.
+// This covers llama.cpp's server, LM Studio, vLLM, LocalAI and OpenAI itself.
+type OpenAIConfig struct {
+	BaseURL      string // API base including /v1 (default: "http://localhost:8080/v1")
+	Model        string // Model name sent in the request body
+	Dimensions   int    // Requested output dimensions (0 = server default)
+	APIKey       string // Optional API key (empty = no auth header)
+	APIKeyHeader string // Header carrying the key (default: "Authorization" as Bearer)
+	BatchSize    int    // Max inputs per request (default: 32)
+}
+
+// DefaultOpenAIConfig returns defaults for a local llama.cpp server.
+func DefaultOpenAIConfig() OpenAIConfig {
+	return OpenAIConfig{
+		BaseURL:      "http://localhost:8080/v1",
+		Model:        "nomic-embed-text",
+		APIKeyHeader: "Authorization",
+		BatchSize:    32,
+	}
+}
+
+// OpenAIEmbedder calls the OpenAI-compatible embeddings HTTP API.
+type OpenAIEmbedder struct {
+	client *http.Client
+	cfg    OpenAIConfig
+}
+
+// NewOpenAIEmbedder creates an embedder for an OpenAI-compatible server.
+func NewOpenAIEmbedder(cfg OpenAIConfig) (*OpenAIEmbedder, error) {
+	if strings.TrimSpace(cfg.BaseURL) == "" {
+		return nil, fmt.Errorf("openai base url is required")
+	}
+	if strings.TrimSpace(cfg.Model) == "" {
+		return nil, fmt.Errorf("openai model is required")
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.APIKeyHeader == "" {
+		cfg.APIKeyHeader = "Authorization"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 32
+	}
+
+	return &OpenAIEmbedder{
+		client: &http.Client{Timeout: 60 * time.Second},
+		cfg:    cfg,
+	}, nil
+}
+
+// openAIEmbedRequest is the JSON body for POST /embeddings.
+type openAIEmbedRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+	EncodingFormat string   `json:"encoding_format"`
+}
+
+// openAIEmbedResponse is the JSON body returned by POST /embeddings.
+type openAIEmbedResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed generates a single embedding vector.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := e.embedRequest(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(vecs) == 0 {
+		return nil, fmt.Errorf("openai returned no embeddings")
+	}
+	return vecs[0], nil
+}
+
+// EmbedBatch generates embeddings for multiple texts.
+// Inputs are split into requests of at most BatchSize texts.
+func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.cfg.BatchSize {
+		end := min(start+e.cfg.BatchSize, len(texts))
+		vecs, err := e.embedRequest(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vecs...)
+	}
+	return out, nil
+}
+
+// embedRequest sends one /embeddings request and returns vectors in input order.
+func (e *OpenAIEmbedder) embedRequest(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(openAIEmbedRequest{
+		Model:          e.cfg.Model,
+		Input:          texts,
+		Dimensions:     e.cfg.Dimensions,
+		EncodingFormat: "float",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.BaseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	e.setAuth(req)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai embed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("openai embed: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var parsed openAIEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(parsed.Data) != len(texts) {
+		return nil, fmt.Errorf("openai returned %d embeddings for %d inputs", len(parsed.Data), len(texts))
+	}
+
+	// The spec allows data in any order; "index" identifies the input.
+	vecs := make([][]float32, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(vecs) {
+			return nil, fmt.Errorf("openai returned out-of-range index %d", d.Index)
+		}
+		vecs[d.Index] = d.Embedding
+	}
+	return vecs, nil
+}
+
+// setAuth adds the API key header if a key is configured.
+func (e *OpenAIEmbedder) setAuth(req *http.Request) {
+	if e.cfg.APIKey == "" {
+		return
+	}
+	if strings.EqualFold(e.cfg.APIKeyHeader, "Authorization") {
+		req.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+		return
+	}
+	req.Header.Set(e.cfg.APIKeyHeader, e.cfg.APIKey)
+}
+
+// Available checks if the server answers GET /models.
+func (e *OpenAIEmbedder) Available(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.cfg.BaseURL+"/models", nil)
+	if err != nil {
+		return false
+	}
+	e.setAuth(req)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// Provider returns "openai".
+func (e *OpenAIEmbedder) Provider() string {
+	return "openai"
+}
+
+// Model returns the embedding model name.
+func (e *OpenAIEmbedder) Model() string {
+	return e.cfg.Model
+}
+
+// Dimensions returns the requested output size (0 = server default).
+func (e *OpenAIEmbedder) Dimensions() int {
+	return e.cfg.Dimensions
+}
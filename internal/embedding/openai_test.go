@@ -0,0 +1,110 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newOpenAIStub returns a server that embeds each input as [len(input), index].
+func newOpenAIStub(t *testing.T, requests *int, authHeader *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			w.WriteHeader(http.StatusOK)
+			return
+		case "/v1/embeddings":
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		*requests++
+		*authHeader = r.Header.Get("Authorization")
+
+		var req openAIEmbedRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var resp openAIEmbedResponse
+		// Answer in reverse order to exercise index-based reordering
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			resp.Data = append(resp.Data, struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			}{Index: i, Embedding: []float32{float32(len(req.Input[i])), float32(i)}})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestOpenAIEmbedder_EmbedBatch(t *testing.T) {
+	var requests int
+	var auth string
+	srv := newOpenAIStub(t, &requests, &auth)
+	defer srv.Close()
+
+	e, err := NewOpenAIEmbedder(OpenAIConfig{
+		BaseURL:   srv.URL + "/v1/",
+		Model:     "test-model",
+		APIKey:    "secret",
+		BatchSize: 2,
+	})
+	if err != nil {
+		t.Fatalf("NewOpenAIEmbedder: %v", err)
+	}
+
+	vecs, err := e.EmbedBatch(context.Background(), []string{"a", "bb", "ccc"})
+	if err != nil {
+		t.Fatalf("EmbedBatch: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("expected 2 requests with batch size 2, got %d", requests)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("unexpected Authorization header: %q", auth)
+	}
+	if len(vecs) != 3 {
+		t.Fatalf("expected 3 vectors, got %d", len(vecs))
+	}
+	for i, want := range []float32{1, 2, 3} {
+		if vecs[i][0] != want {
+			t.Errorf("vecs[%d][0] = %v, want %v", i, vecs[i][0], want)
+		}
+	}
+
+	if !e.Available(context.Background()) {
+		t.Error("expected embedder to be available")
+	}
+}
+
+func TestOpenAIEmbedder_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	e, err := NewOpenAIEmbedder(OpenAIConfig{BaseURL: srv.URL, Model: "m"})
+	if err != nil {
+		t.Fatalf("NewOpenAIEmbedder: %v", err)
+	}
+
+	if _, err := e.Embed(context.Background(), "hello"); err == nil {
+		t.Error("expected error for HTTP 503")
+	}
+	if e.Available(context.Background()) {
+		t.Error("expected embedder to be unavailable")
+	}
+}
+
+func TestNewOpenAIEmbedder_RequiresModel(t *testing.T) {
+	if _, err := NewOpenAIEmbedder(OpenAIConfig{BaseURL: "http://localhost"}); err == nil {
+		t.Error("expected error for missing model")
+	}
+}
@@ -0,0 +1,144 @@
+package fetcher
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+// SiteConfig holds per-site fetch settings, keyed by site. A key is a host
+// ("docs.nats.io"), a host with a path prefix ("example.com/docs/") or a
+// wildcard host ("*.readthedocs.io"); the most specific match applies.
+//
+//	sites:
+//	  docs.nats.io:
+//	    extract:
+//	      include: ["article.markdown-section"]
+//	      exclude: [".page-footer", "nav.toc"]
+type SiteConfig struct {
+	Sites map[string]SiteRules `yaml:"sites"`
+
+	rules []*siteRule // Compiled Sites, most specific first
+}
+
+// SiteRules are the settings for one site.
+type SiteRules struct {
+	Extract ExtractRules `yaml:"extract"`
+}
+
+// ExtractRules override main-content extraction with CSS selectors.
+type ExtractRules struct {
+	// Include selects the main content; when it matches, the heuristics are
+	// skipped and the matching elements (in document order) are kept
+	Include []string `yaml:"include"`
+
+	// Exclude selects elements to drop from the content
+	Exclude []string `yaml:"exclude"`
+}
+
+// siteRule is a compiled SiteConfig entry.
+type siteRule struct {
+	host     string // Lowercased host, without "*." for wildcards
+	wildcard bool   // Matches subdomains of host
+	prefix   string // Path prefix ("" for the whole host)
+	include  []cascadia.Sel
+	exclude  []cascadia.Sel
+}
+
+// LoadSiteConfig reads a YAML site config file.
+func LoadSiteConfig(path string) (*SiteConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read site config: %w", err)
+	}
+	return ParseSiteConfig(data)
+}
+
+// ParseSiteConfig parses and validates a YAML site config.
+func ParseSiteConfig(data []byte) (*SiteConfig, error) {
+	var cfg SiteConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse site config: %w", err)
+	}
+	for key, rules := range cfg.Sites {
+		r, err := compileSiteRule(key, rules)
+		if err != nil {
+			return nil, fmt.Errorf("site config %q: %w", key, err)
+		}
+		cfg.rules = append(cfg.rules, r)
+	}
+	sort.Slice(cfg.rules, func(i, j int) bool {
+		if a, b := cfg.rules[i].specificity(), cfg.rules[j].specificity(); a != b {
+			return a > b
+		}
+		return cfg.rules[i].host < cfg.rules[j].host
+	})
+	return &cfg, nil
+}
+
+// compileSiteRule parses a site key and compiles its selectors.
+func compileSiteRule(key string, rules SiteRules) (*siteRule, error) {
+	host, prefix, _ := strings.Cut(strings.TrimSpace(key), "/")
+	r := &siteRule{host: strings.ToLower(host)}
+	if prefix != "" {
+		r.prefix = "/" + prefix
+	}
+	if rest, ok := strings.CutPrefix(r.host, "*."); ok {
+		r.host, r.wildcard = rest, true
+	}
+	if r.host == "" || strings.Contains(r.host, "*") {
+		return nil, fmt.Errorf("invalid site (want host, host/path or *.host)")
+	}
+
+	var err error
+	if r.include, err = compileSelectors(rules.Extract.Include); err != nil {
+		return nil, err
+	}
+	if r.exclude, err = compileSelectors(rules.Extract.Exclude); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// compileSelectors compiles CSS selectors.
+func compileSelectors(selectors []string) ([]cascadia.Sel, error) {
+	sels := make([]cascadia.Sel, 0, len(selectors))
+	for _, s := range selectors {
+		sel, err := cascadia.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", s, err)
+		}
+		sels = append(sels, sel)
+	}
+	return sels, nil
+}
+
+// specificity orders rules: exact hosts before wildcards, then longer hosts
+// and longer path prefixes first.
+func (r *siteRule) specificity() int {
+	n := len(r.host)*1000 + len(r.prefix)
+	if !r.wildcard {
+		n += 1 << 30
+	}
+	return n
+}
+
+// rulesFor returns the most specific rule matching u, or nil.
+func (c *SiteConfig) rulesFor(u *url.URL) *siteRule {
+	if c == nil {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, r := range c.rules {
+		hostMatch := host == r.host || (r.wildcard && strings.HasSuffix(host, "."+r.host))
+		if hostMatch && strings.HasPrefix(u.Path, r.prefix) {
+			return r
+		}
+	}
+	return nil
+}
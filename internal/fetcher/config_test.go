@@ -0,0 +1,64 @@
+package fetcher
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestSiteConfigRulesFor(t *testing.T) {
+	cfg, err := ParseSiteConfig([]byte(`
+sites:
+  "*.readthedocs.io":
+    extract: {include: ["div.wildcard"]}
+  docs.readthedocs.io:
+    extract: {include: ["div.host"]}
+  Example.com/Docs/:
+    extract: {include: ["div.prefix"]}
+  example.com:
+    extract: {exclude: ["div.host"]}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url  string
+		host string
+		pref string
+	}{
+		{"https://docs.readthedocs.io/en/latest/", "docs.readthedocs.io", ""},
+		{"https://nats.readthedocs.io/", "readthedocs.io", ""},
+		{"https://readthedocs.io/", "readthedocs.io", ""},
+		{"https://example.com/Docs/intro", "example.com", "/Docs/"},
+		{"https://EXAMPLE.com/blog", "example.com", ""},
+		{"https://example.org/", "", ""},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		r := cfg.rulesFor(u)
+		switch {
+		case tt.host == "" && r != nil:
+			t.Errorf("%s: got rule for %s, want none", tt.url, r.host)
+		case tt.host != "" && (r == nil || r.host != tt.host || r.prefix != tt.pref):
+			t.Errorf("%s: got %+v, want %s%s", tt.url, r, tt.host, tt.pref)
+		}
+	}
+
+	var none *SiteConfig
+	if none.rulesFor(&url.URL{Host: "example.com"}) != nil {
+		t.Error("nil config should have no rules")
+	}
+}
+
+func TestParseSiteConfigErrors(t *testing.T) {
+	for _, data := range []string{
+		"sites: [",
+		"sites:\n  example.com:\n    extract: {include: ['div[']}",
+		"sites:\n  '*':\n    extract: {}",
+		"sites:\n  '/docs':\n    extract: {}",
+	} {
+		if _, err := ParseSiteConfig([]byte(data)); err == nil {
+			t.Errorf("ParseSiteConfig(%q) should fail", data)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// Crawler is implemented by fetchers that can follow links from a start page.
+type Crawler interface {
+	// Crawl fetches startURL and the pages it links to, breadth first,
+	// calling visit with each page as markdown (or the error fetching it).
+	// visit is never called concurrently.
+	Crawl(ctx context.Context, startURL string, opts CrawlOptions, visit func(Page)) (*CrawlStats, error)
+}
+
+// Crawl scopes.
+const (
+	ScopePrefix = "prefix" // Pages under the start URL's directory
+	ScopeHost   = "host"   // Any page on the start URL's host
+)
+
+// Crawl defaults.
+const (
+	DefaultCrawlDepth       = 2
+	DefaultCrawlPages       = 50
+	DefaultCrawlConcurrency = 4
+)
+
+// CrawlOptions limits a crawl.
+type CrawlOptions struct {
+	MaxDepth    *int     // Link hops from the start page; 0 fetches only the start page (nil: 2)
+	MaxPages    int      // Pages fetched at most, including the start page (default: 50)
+	Include     []string // Path globs pages must match, e.g. "/docs/**" (empty: all)
+	Exclude     []string // Path globs of pages to skip, e.g. "/docs/*/changelog"
+	Scope       string   // ScopePrefix (default) or ScopeHost
+	Concurrency int      // Parallel requests (default: 4); a robots.txt Crawl-delay forces 1
+}
+
+// Page is a crawled page, or the error fetching it.
+type Page struct {
+	URL        string
+	Depth      int
+	Markdown   string
+	Validators Validators
+	Err        error
+}
+
+// CrawlStats summarizes a crawl.
+type CrawlStats struct {
+	Fetched    int           // Pages fetched
+	Failed     int           // Pages that could not be fetched or converted
+	Disallowed int           // Links skipped because robots.txt disallows them
+	CrawlDelay time.Duration // Delay between requests asked for by robots.txt
+}
+
+// assetExtRe matches links to files that are never documentation pages.
+var assetExtRe = regexp.MustCompile(`(?i)\.(png|jpe?g|gif|svg|ico|webp|avif|css|js|mjs|map|woff2?|ttf|eot|otf|pdf|zip|gz|tgz|tar|bz2|xz|7z|mp[34]|webm|mov|wasm|exe|dmg|deb|rpm)$`)
+
+// Crawl fetches startURL and follows its links up to opts.MaxDepth hops,
+// staying on the start host (and, with ScopePrefix, under the start URL's
+// directory). Pages outside Include or inside Exclude are neither fetched nor
+// followed, except the start page, which is always fetched for its links.
+// If the start page redirects, the crawl is scoped to where it redirected to.
+// robots.txt is honored, including Crawl-delay.
+func (f *HTTPFetcher) Crawl(ctx context.Context, startURL string, opts CrawlOptions, visit func(Page)) (*CrawlStats, error) {
+	start, err := url.Parse(startURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse URL: %w", err)
+	}
+	if (start.Scheme != "http" && start.Scheme != "https") || start.Host == "" {
+		return nil, fmt.Errorf("not an http(s) URL: %s", startURL)
+	}
+	start.Fragment = ""
+
+	c, err := newCrawl(start, opts, visit)
+	if err != nil {
+		return nil, err
+	}
+	if c.robots, err = f.robots(ctx, start); err != nil {
+		return nil, err
+	}
+	c.stats.CrawlDelay = c.robots.delay
+	if c.robots.delay > 0 {
+		c.opts.Concurrency = 1
+	}
+	if !c.robots.allowed(start) {
+		return nil, fmt.Errorf("robots.txt disallows %s", startURL)
+	}
+
+	c.seen[start.String()] = true
+	level := []*url.URL{start}
+	for depth := 0; len(level) > 0 && depth <= c.maxDepth; depth++ {
+		level = c.fetchLevel(ctx, f, level, depth)
+		if err := ctx.Err(); err != nil {
+			return c.stats, err
+		}
+	}
+	return c.stats, nil
+}
+
+// crawl is the state of one crawl.
+type crawl struct {
+	opts             CrawlOptions
+	maxDepth         int
+	start            *url.URL
+	prefix           string // Path prefix pages must have (ScopePrefix)
+	include, exclude []*regexp.Regexp
+	robots           *robotsRules
+	visit            func(Page)
+	listed           bool // Listed pages (FetchPages): no start page, forge files are fetched raw, and pages keep their listed URL
+
+	mu      sync.Mutex // Guards the fields below and serializes visit
+	seen    map[string]bool
+	started int
+	stats   *CrawlStats
+
+	throttle sync.Mutex // Spaces requests by the crawl delay
+	last     time.Time
+}
+
+// newCrawl validates opts and fills in defaults.
+func newCrawl(start *url.URL, opts CrawlOptions, visit func(Page)) (*crawl, error) {
+	maxDepth := DefaultCrawlDepth
+	if opts.MaxDepth != nil {
+		maxDepth = max(*opts.MaxDepth, 0)
+	}
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = DefaultCrawlPages
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultCrawlConcurrency
+	}
+
+	switch opts.Scope {
+	case "", ScopePrefix, ScopeHost:
+	default:
+		return nil, fmt.Errorf("invalid scope %q (want %s or %s)", opts.Scope, ScopePrefix, ScopeHost)
+	}
+	c := &crawl{opts: opts, maxDepth: maxDepth, visit: visit, seen: make(map[string]bool), stats: &CrawlStats{}}
+	c.setStart(start)
+	for _, p := range opts.Include {
+		c.include = append(c.include, pathGlob(p))
+	}
+	for _, p := range opts.Exclude {
+		c.exclude = append(c.exclude, pathGlob(p))
+	}
+	return c, nil
+}
+
+// setStart sets the start page and, with ScopePrefix, the directory pages
+// must be under.
+func (c *crawl) setStart(start *url.URL) {
+	if start.Path == "" {
+		start.Path = "/"
+	}
+	c.start, c.prefix = start, ""
+	if c.opts.Scope != ScopeHost {
+		c.prefix = start.Path[:strings.LastIndex(start.Path, "/")+1]
+	}
+}
+
+// rebase moves the crawl to where the start page redirected to (e.g. from
+// example.com to www.example.com, or into a versioned docs directory),
+// reading the new host's robots.txt. Callers hold c.mu.
+func (c *crawl) rebase(ctx context.Context, f *HTTPFetcher, start *url.URL) error {
+	if start.Scheme != c.start.Scheme || !strings.EqualFold(start.Host, c.start.Host) {
+		rules, err := f.robots(ctx, start)
+		if err != nil {
+			return err
+		}
+		c.robots = rules
+		c.stats.CrawlDelay = rules.delay
+		if rules.delay > 0 {
+			c.opts.Concurrency = 1
+		}
+	}
+	c.setStart(start)
+	return nil
+}
+
+// fetchLevel fetches the pages at one depth, at most opts.Concurrency at a
+// time, and returns the new links they contain.
+func (c *crawl) fetchLevel(ctx context.Context, f *HTTPFetcher, level []*url.URL, depth int) []*url.URL {
+	var next []*url.URL
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.opts.Concurrency)
+
+	for _, u := range level {
+		if c.started >= c.opts.MaxPages || ctx.Err() != nil {
+			break
+		}
+		c.started++
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(u *url.URL) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := c.wait(ctx); err != nil {
+				return
+			}
+			target := u
+			if c.listed {
+				if raw := rawURL(u); raw != nil {
+					target = raw
+				}
+			}
+			page, err := f.fetchPage(ctx, target, nil)
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if err != nil {
+				c.stats.Failed++
+				c.visit(Page{URL: u.String(), Depth: depth, Err: err})
+				return
+			}
+			final := page.url
+			if final.String() != target.String() {
+				switch {
+				case depth == 0 && !c.listed:
+					// The start page moved: crawl the site it moved to
+					if err := c.rebase(ctx, f, final); err != nil {
+						c.stats.Failed++
+						c.visit(Page{URL: u.String(), Depth: depth, Err: err})
+						return
+					}
+				case (!c.listed && !c.inScope(final)) || c.seen[final.String()]:
+					// Redirected off the site, or to a page already seen
+					return
+				}
+				c.seen[final.String()] = true
+			}
+			c.stats.Fetched++
+			pageURL := final.String()
+			if c.listed {
+				pageURL = u.String()
+			}
+			if depth > 0 || c.matches(final) {
+				c.visit(Page{URL: pageURL, Depth: depth, Markdown: page.markdown, Validators: page.validators})
+			}
+			if depth < c.maxDepth {
+				for _, link := range page.links {
+					if c.follow(link) {
+						next = append(next, link)
+					}
+				}
+			}
+		}(u)
+	}
+	wg.Wait()
+	return next
+}
+
+// wait blocks until the crawl delay since the previous request has passed.
+func (c *crawl) wait(ctx context.Context) error {
+	if c.robots.delay == 0 {
+		return nil
+	}
+	c.throttle.Lock()
+	defer c.throttle.Unlock()
+	if d := time.Until(c.last.Add(c.robots.delay)); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	c.last = time.Now()
+	return nil
+}
+
+// follow reports whether a link should be crawled, marking it seen.
+// Callers hold c.mu.
+func (c *crawl) follow(u *url.URL) bool {
+	key := u.String()
+	if c.seen[key] || !c.inScope(u) || assetExtRe.MatchString(u.Path) || !c.matches(u) {
+		return false
+	}
+	c.seen[key] = true
+	if !c.robots.allowed(u) {
+		c.stats.Disallowed++
+		return false
+	}
+	return true
+}
+
+// inScope reports whether u is on the start host and under the prefix.
+func (c *crawl) inScope(u *url.URL) bool {
+	return (u.Scheme == "http" || u.Scheme == "https") &&
+		strings.EqualFold(u.Host, c.start.Host) &&
+		strings.HasPrefix(u.Path, c.prefix)
+}
+
+// matches applies the include and exclude patterns to u's path.
+func (c *crawl) matches(u *url.URL) bool {
+	return matchesGlobs(u, c.include, c.exclude)
+}
+
+// matchesGlobs reports whether u's path matches none of exclude and, unless
+// include is empty, one of include.
+func matchesGlobs(u *url.URL, include, exclude []*regexp.Regexp) bool {
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+	for _, re := range exclude {
+		if re.MatchString(p) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, re := range include {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathGlob compiles a path pattern: "**" matches across "/", "*" and "?"
+// within one segment.
+func pathGlob(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			sb.WriteString("[^/]*")
+		case pattern[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+// fetchedPage is a fetched page.
+type fetchedPage struct {
+	markdown    string
+	links       []*url.URL // Resolved, without fragments
+	url         *url.URL   // Final URL, after redirects
+	validators  Validators
+	notModified bool // 304 to a conditional request (no markdown or links)
+}
+
+// acceptPages is the Accept header of page requests: HTML, or markdown and
+// text, which are indexed as they are.
+const acceptPages = "text/html, application/xhtml+xml, text/markdown, text/plain;q=0.9, */*;q=0.5"
+
+// fetchPage fetches a page, with extra request headers, and converts it to
+// markdown, collecting its links. Links are taken from the whole page, before
+// it is reduced to its main content.
+func (f *HTTPFetcher) fetchPage(ctx context.Context, u *url.URL, header http.Header) (*fetchedPage, error) {
+	reqHeader := http.Header{"Accept": {acceptPages}}
+	for k, v := range header {
+		reqHeader[k] = v
+	}
+	resp, body, err := f.get(ctx, u.String(), reqHeader)
+	if err != nil {
+		return nil, err
+	}
+	page := &fetchedPage{url: resp.Request.URL, validators: validators(resp)}
+	page.url.Fragment = ""
+	switch {
+	case resp.StatusCode == http.StatusNotModified && header != nil:
+		page.notModified = true
+		return page, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, statusError(resp, 1)
+	}
+
+	switch mt := mediaType(resp, page.url, body); {
+	case htmlTypes[mt]:
+	case textTypes[mt]:
+		page.markdown = passThrough(body)
+		return page, nil
+	default:
+		return nil, &FetchError{Kind: KindUnsupported, URL: page.url.String(), Err: fmt.Errorf("not an HTML, markdown or text page (%s)", mt)}
+	}
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parse HTML: %w", err)
+	}
+	page.links = pageLinks(doc, page.url)
+
+	content := doc
+	if rule := f.sites.rulesFor(page.url); f.extract {
+		content = extractContent(doc, rule)
+		if textLength(content) == 0 {
+			// Extraction misjudged the layout and kept nothing: convert the
+			// whole page rather than index an empty document
+			if content, err = html.Parse(bytes.NewReader(body)); err != nil {
+				return nil, fmt.Errorf("parse HTML: %w", err)
+			}
+		}
+	} else if rule != nil {
+		content = applySiteRule(doc, rule)
+	}
+	markdown, err := htmltomarkdown.ConvertNode(content, converter.WithDomain(page.url.String()))
+	if err != nil {
+		return nil, fmt.Errorf("convert to markdown: %w", err)
+	}
+	page.markdown = string(markdown)
+	return page, nil
+}
+
+// pageLinks returns the targets of a document's <a href> links, resolved
+// against its <base href> or URL. rel="nofollow" links are skipped.
+func pageLinks(doc *html.Node, pageURL *url.URL) []*url.URL {
+	base := pageURL
+	var hrefs []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "base":
+				if href := attr(n, "href"); href != "" {
+					if b, err := pageURL.Parse(href); err == nil {
+						base = b
+					}
+				}
+			case "a":
+				if href := attr(n, "href"); href != "" && !strings.Contains(attr(n, "rel"), "nofollow") {
+					hrefs = append(hrefs, href)
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	links := make([]*url.URL, 0, len(hrefs))
+	for _, href := range hrefs {
+		u, err := base.Parse(strings.TrimSpace(href))
+		if err != nil {
+			continue
+		}
+		u.Fragment = ""
+		u.RawFragment = ""
+		if u.Path == "" {
+			u.Path = "/"
+		}
+		u.Path = path.Clean(u.Path) + trailingSlash(u.Path)
+		links = append(links, u)
+	}
+	return links
+}
+
+// trailingSlash keeps the "/" that path.Clean drops from directory URLs.
+func trailingSlash(p string) string {
+	if strings.HasSuffix(p, "/") && p != "/" {
+		return "/"
+	}
+	return ""
+}
+
+// attr returns an element's attribute value.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,200 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newDocsSite serves a small documentation site:
+//
+//	/docs/           → intro, guide/, changelog, /blog/, external, image
+//	/docs/intro      → /docs/guide/ (via relative link)
+//	/docs/guide/     → /docs/guide/deep
+//	/docs/guide/deep → /docs/guide/deeper
+//	/old/            → redirects to /docs/
+func newDocsSite(t *testing.T, robots string) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	pages := map[string]string{
+		"/docs/":             `<a href="intro">Intro</a> <a href="guide/#top">Guide</a> <a href="changelog">Changes</a> <a href="/blog/">Blog</a> <a href="https://example.com/">Ext</a> <a href="logo.png">Logo</a> <a href="secret" rel="nofollow">Secret</a>`,
+		"/docs/intro":        `<h1>Intro</h1><p>Streams store messages.</p><a href="guide/">Guide</a>`,
+		"/docs/guide/":       `<h1>Guide</h1><a href="deep">Deep</a>`,
+		"/docs/guide/deep":   `<h1>Deep</h1><a href="deeper">Deeper</a>`,
+		"/docs/guide/deeper": `<h1>Deeper</h1>`,
+		"/docs/changelog":    `<h1>Changelog</h1>`,
+		"/blog/":             `<h1>Blog</h1>`,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/docs/", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/robots.txt" {
+			if robots == "" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, robots)
+			return
+		}
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, "<html><body>%s</body></html>", body)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+// depth returns a CrawlOptions.MaxDepth.
+func depth(n int) *int { return &n }
+
+// crawlURLs crawls from /docs/ and returns the sorted paths of the visited
+// pages.
+func crawlURLs(t *testing.T, srv *httptest.Server, opts CrawlOptions) ([]string, *CrawlStats) {
+	t.Helper()
+	return crawlFrom(t, srv, srv.URL+"/docs/", opts)
+}
+
+// crawlFrom crawls from startURL and returns the sorted paths of the visited
+// pages.
+func crawlFrom(t *testing.T, srv *httptest.Server, startURL string, opts CrawlOptions) ([]string, *CrawlStats) {
+	t.Helper()
+	var paths []string
+	stats, err := NewHTTPFetcher().Crawl(context.Background(), startURL, opts, func(p Page) {
+		if p.Err != nil {
+			t.Errorf("page %s: %v", p.URL, p.Err)
+			return
+		}
+		u, _ := url.Parse(p.URL)
+		paths = append(paths, u.Path)
+	})
+	if err != nil {
+		t.Fatalf("Crawl: %v", err)
+	}
+	sort.Strings(paths)
+	return paths, stats
+}
+
+func TestCrawl_DepthAndScope(t *testing.T) {
+	srv, _ := newDocsSite(t, "")
+	paths, stats := crawlURLs(t, srv, CrawlOptions{MaxDepth: depth(2)})
+
+	want := "/docs/ /docs/changelog /docs/guide/ /docs/guide/deep /docs/intro"
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("pages = %s, want %s", got, want)
+	}
+	if stats.Fetched != 5 || stats.Failed != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// Host scope also reaches /blog/
+	paths, _ = crawlURLs(t, srv, CrawlOptions{MaxDepth: depth(1), Scope: ScopeHost})
+	if got := strings.Join(paths, " "); got != "/blog/ /docs/ /docs/changelog /docs/guide/ /docs/intro" {
+		t.Errorf("host scope pages = %s", got)
+	}
+}
+
+func TestCrawl_StartOnly(t *testing.T) {
+	srv, _ := newDocsSite(t, "")
+	paths, _ := crawlURLs(t, srv, CrawlOptions{MaxDepth: depth(0)})
+	if got := strings.Join(paths, " "); got != "/docs/" {
+		t.Errorf("pages = %s, want only the start page", got)
+	}
+}
+
+func TestCrawl_StartRedirect(t *testing.T) {
+	srv, _ := newDocsSite(t, "")
+	want := "/docs/ /docs/changelog /docs/guide/ /docs/intro"
+
+	// Into another directory
+	paths, _ := crawlFrom(t, srv, srv.URL+"/old/", CrawlOptions{MaxDepth: depth(1)})
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("pages = %s, want %s", got, want)
+	}
+
+	// Onto another host, like example.com → www.example.com
+	other := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	redirect := httptest.NewServer(http.RedirectHandler(other+"/docs/", http.StatusMovedPermanently))
+	defer redirect.Close()
+	paths, _ = crawlFrom(t, srv, redirect.URL+"/docs/", CrawlOptions{MaxDepth: depth(1)})
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("pages on the new host = %s, want %s", got, want)
+	}
+}
+
+func TestCrawl_IncludeExcludeAndLimits(t *testing.T) {
+	srv, _ := newDocsSite(t, "")
+
+	paths, _ := crawlURLs(t, srv, CrawlOptions{MaxDepth: depth(3), Include: []string{"/docs/guide/**"}})
+	if got := strings.Join(paths, " "); got != "/docs/guide/ /docs/guide/deep /docs/guide/deeper" {
+		t.Errorf("included pages = %s", got)
+	}
+
+	paths, _ = crawlURLs(t, srv, CrawlOptions{MaxDepth: depth(1), Exclude: []string{"/docs/*log"}})
+	if got := strings.Join(paths, " "); got != "/docs/ /docs/guide/ /docs/intro" {
+		t.Errorf("pages without changelog = %s", got)
+	}
+
+	paths, stats := crawlURLs(t, srv, CrawlOptions{MaxDepth: depth(3), MaxPages: 2})
+	if len(paths) != 2 || stats.Fetched != 2 {
+		t.Errorf("max pages: %v %+v", paths, stats)
+	}
+}
+
+func TestCrawl_RespectsRobots(t *testing.T) {
+	srv, requests := newDocsSite(t, "User-agent: *\nDisallow: /docs/guide/\nCrawl-delay: 0.05\n")
+
+	begin := time.Now()
+	paths, stats := crawlURLs(t, srv, CrawlOptions{MaxDepth: depth(3)})
+	if got := strings.Join(paths, " "); got != "/docs/ /docs/changelog /docs/intro" {
+		t.Errorf("pages = %s", got)
+	}
+	if stats.Disallowed != 1 || stats.CrawlDelay != 50*time.Millisecond {
+		t.Errorf("stats = %+v", stats)
+	}
+	// robots.txt + 3 pages, the pages 50ms apart
+	if n := atomic.LoadInt32(requests); n != 4 {
+		t.Errorf("requests = %d, want 4", n)
+	}
+	if elapsed := time.Since(begin); elapsed < 100*time.Millisecond {
+		t.Errorf("crawl took %s, expected crawl-delay spacing", elapsed)
+	}
+}
+
+func TestCrawl_StartDisallowed(t *testing.T) {
+	srv, _ := newDocsSite(t, "User-agent: *\nDisallow: /\n")
+	if _, err := NewHTTPFetcher().Crawl(context.Background(), srv.URL+"/docs/", CrawlOptions{}, func(Page) {}); err == nil {
+		t.Error("expected robots.txt to block the crawl")
+	}
+}
+
+func TestPathGlob(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/docs/**", "/docs/a/b", true},
+		{"/docs/*", "/docs/a", true},
+		{"/docs/*", "/docs/a/b", false},
+		{"/docs/v?/**", "/docs/v2/x", true},
+	}
+	for _, tt := range tests {
+		if got := pathGlob(tt.pattern).MatchString(tt.path); got != tt.want {
+			t.Errorf("pathGlob(%q) on %q = %v", tt.pattern, tt.path, got)
+		}
+	}
+}
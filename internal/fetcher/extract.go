@@ -0,0 +1,382 @@
+package fetcher
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Main-content extraction, in the spirit of Mozilla's Readability: pages are
+// reduced to the element holding their content before conversion, so menus,
+// cookie banners, sidebars and footers don't become chunks.
+
+var (
+	// unlikelyRe matches class/id values of boilerplate elements.
+	unlikelyRe = regexp.MustCompile(`(?i)\b(?:-?(?:nav|navbar|navigation|menu|breadcrumbs?|sidebar|side-bar|footer|masthead|header|banner|cookies?|consent|gdpr|popup|modal|newsletter|subscribe|share|sharing|social|ads?|advert|sponsor|promo|related|comments?|skip-link|toc|table-of-contents|edit-page|pagination|feedback|announcement)(?:-\w+)?)\b`)
+
+	// maybeContentRe matches class/id values that rescue an unlikely match,
+	// e.g. "content-wrapper sidebar-open".
+	maybeContentRe = regexp.MustCompile(`(?i)\b(?:article|body|content|main|markdown|prose|doc|docs|documentation|post|entry)\b`)
+
+	// positiveRe and negativeRe weight candidate containers by class/id.
+	positiveRe = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|text|blog|story|markdown|prose|doc`)
+	negativeRe = regexp.MustCompile(`(?i)comment|footer|footnote|masthead|meta|promo|related|scroll|share|sidebar|sponsor|tags|tool|widget|nav|menu|breadcrumb|cookie|banner`)
+)
+
+// boilerplateRoles are ARIA landmark roles that are never main content.
+var boilerplateRoles = map[string]bool{
+	"navigation": true, "banner": true, "contentinfo": true, "complementary": true,
+	"search": true, "dialog": true, "alertdialog": true,
+}
+
+// extractContent returns the element holding doc's main content, with
+// boilerplate removed. doc is modified. Site rules, when given, select the
+// content and extra elements to drop; otherwise the single <main> or
+// <article> is used, or else the element scoring highest by text density.
+// Pages without a clear winner fall back to the cleaned <body>.
+func extractContent(doc *html.Node, rule *siteRule) *html.Node {
+	removeAll(doc, func(n *html.Node) bool {
+		switch n.DataAtom {
+		case atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Iframe, atom.Dialog:
+			return true
+		case atom.Form, atom.Button:
+			// Search boxes and sign-up forms, but not forms wrapping the
+			// page (ASP.NET WebForms wraps the whole <body> in one)
+			return !holdsContent(n)
+		}
+		return false
+	})
+	if rule != nil {
+		if content := applySiteRule(doc, rule); content != doc {
+			return content
+		}
+	}
+
+	removeAll(doc, isBoilerplate)
+
+	body := findElement(doc, atom.Body)
+	if body == nil {
+		return doc
+	}
+	for _, landmark := range []func(*html.Node) bool{isMain, isArticle} {
+		if found := findAll(body, landmark); len(found) == 1 && textLength(found[0]) > 0 {
+			return found[0]
+		}
+	}
+	if best := bestCandidate(body); best != nil && textLength(best)*4 >= textLength(body) {
+		return best
+	}
+	return body
+}
+
+// applySiteRule drops the elements matched by a site's exclude selectors and
+// returns the content its include selectors select, or doc if they select
+// nothing.
+func applySiteRule(doc *html.Node, rule *siteRule) *html.Node {
+	for _, sel := range rule.exclude {
+		for _, n := range cascadia.QueryAll(doc, sel) {
+			detach(n)
+		}
+	}
+	if content := selectContent(doc, rule.include); content != nil {
+		return content
+	}
+	return doc
+}
+
+// selectContent returns the elements matched by the include selectors, in
+// document order, under one wrapper element (nil if nothing matches).
+func selectContent(doc *html.Node, include []cascadia.Sel) *html.Node {
+	if len(include) == 0 {
+		return nil
+	}
+	var matches []*html.Node
+	walk(doc, func(n *html.Node) bool {
+		for _, sel := range include {
+			if n.Type == html.ElementNode && sel.Match(n) {
+				matches = append(matches, n)
+				return false // Don't also collect nested matches
+			}
+		}
+		return true
+	})
+	switch len(matches) {
+	case 0:
+		return nil
+	case 1:
+		return matches[0]
+	}
+	wrapper := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, m := range matches {
+		detach(m)
+		wrapper.AppendChild(m)
+	}
+	return wrapper
+}
+
+// isBoilerplate reports whether an element is page chrome: navigation,
+// asides, page headers and footers, hidden elements, and elements whose
+// class or id says so. Elements holding a <main> or <article> are kept.
+func isBoilerplate(n *html.Node) bool {
+	switch n.DataAtom {
+	case atom.Html, atom.Body, atom.Main, atom.Article:
+		return false
+	case atom.Nav, atom.Aside:
+		return true
+	case atom.Header, atom.Footer:
+		// Headers and footers of the page, not of an article (which may
+		// hold its title)
+		if !hasAncestor(n, isArticle) && !hasAncestor(n, isMain) {
+			return true
+		}
+	}
+	if boilerplateRoles[attr(n, "role")] || isHidden(n) {
+		return true
+	}
+	ident := attr(n, "class") + " " + attr(n, "id")
+	if unlikelyRe.MatchString(ident) && !maybeContentRe.MatchString(ident) {
+		// Class names lie: "page-header-wrapper" may wrap the whole page,
+		// so only drop elements that are short or mostly links
+		return !holdsContent(n) || linkDensity(n) > 0.5
+	}
+	return false
+}
+
+// minContentText is the text length from which an element counts as holding
+// content rather than being a widget.
+const minContentText = 200
+
+// holdsContent reports whether an element holds a <main> or <article>, or
+// at least minContentText of text.
+func holdsContent(n *html.Node) bool {
+	if findAll(n, func(c *html.Node) bool { return isMain(c) || isArticle(c) }) != nil {
+		return true
+	}
+	return textLength(n) >= minContentText
+}
+
+// isHidden reports whether an element is hidden from readers.
+func isHidden(n *html.Node) bool {
+	if hasAttr(n, "hidden") || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", "")
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+func isMain(n *html.Node) bool {
+	return n.DataAtom == atom.Main || attr(n, "role") == "main"
+}
+
+func isArticle(n *html.Node) bool {
+	return n.DataAtom == atom.Article
+}
+
+// ─── Scoring ─────────────────────────────────────────────────────────────────
+
+// bestCandidate scores the containers of text blocks (paragraphs, code,
+// list items, ...) by the amount of prose they hold, discounted by their
+// link density, and returns the best one. A parent replaces the winner when
+// a sibling container scores close to it, so content split across sections
+// stays together. Ties go to the node first in document order, so a page
+// always extracts the same way.
+func bestCandidate(body *html.Node) *html.Node {
+	scores := make(map[*html.Node]float64)
+	addScore := func(n *html.Node, s float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = initialScore(n)
+		}
+		scores[n] += s
+	}
+
+	walk(body, func(n *html.Node) bool {
+		switch n.DataAtom {
+		case atom.P, atom.Pre, atom.Td, atom.Blockquote, atom.Dd, atom.Li:
+		default:
+			return true
+		}
+		text := innerText(n)
+		if len(text) < 25 {
+			return false
+		}
+		s := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+		addScore(n.Parent, s)
+		if n.Parent != nil {
+			addScore(n.Parent.Parent, s/2)
+		}
+		return false
+	})
+
+	var best *html.Node
+	bestScore := 0.0
+	walk(body, func(n *html.Node) bool {
+		s, ok := scores[n]
+		if !ok {
+			return true
+		}
+		s *= 1 - linkDensity(n)
+		scores[n] = s
+		if s > bestScore {
+			best, bestScore = n, s
+		}
+		return true
+	})
+	if best == nil {
+		return nil
+	}
+
+	// Promote to the parent while a sibling holds comparable content
+	for best.Parent != nil && best.Parent != body.Parent {
+		promoted := false
+		for sib := best.Parent.FirstChild; sib != nil; sib = sib.NextSibling {
+			if sib != best && scores[sib] >= bestScore*0.3 && scores[sib] > 0 {
+				promoted = true
+				break
+			}
+		}
+		if !promoted {
+			break
+		}
+		best = best.Parent
+	}
+	return best
+}
+
+// initialScore weights a container by its tag and class/id.
+func initialScore(n *html.Node) float64 {
+	s := 0.0
+	switch n.DataAtom {
+	case atom.Div, atom.Section:
+		s = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		s = 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li:
+		s = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		s = -5
+	}
+	ident := attr(n, "class") + " " + attr(n, "id")
+	if positiveRe.MatchString(ident) {
+		s += 25
+	}
+	if negativeRe.MatchString(ident) {
+		s -= 25
+	}
+	return s
+}
+
+// linkDensity is the share of an element's text inside links.
+func linkDensity(n *html.Node) float64 {
+	total := textLength(n)
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	walk(n, func(c *html.Node) bool {
+		if c.DataAtom == atom.A {
+			linked += textLength(c)
+			return false
+		}
+		return true
+	})
+	return float64(linked) / float64(total)
+}
+
+// ─── DOM helpers ─────────────────────────────────────────────────────────────
+
+// walk calls visit for n and its descendants in document order, skipping the
+// children of nodes for which visit returns false.
+func walk(n *html.Node, visit func(*html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling // visit may detach c
+		walk(c, visit)
+		c = next
+	}
+}
+
+// removeAll detaches every element for which match returns true.
+func removeAll(root *html.Node, match func(*html.Node) bool) {
+	walk(root, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && n != root && match(n) {
+			detach(n)
+			return false
+		}
+		return true
+	})
+}
+
+// detach removes n from its parent.
+func detach(n *html.Node) {
+	if n.Parent != nil {
+		n.Parent.RemoveChild(n)
+	}
+}
+
+// findAll returns the elements under root (inclusive) that match, without
+// descending into matches.
+func findAll(root *html.Node, match func(*html.Node) bool) []*html.Node {
+	var found []*html.Node
+	walk(root, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && match(n) {
+			found = append(found, n)
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// findElement returns the first element with the given tag.
+func findElement(root *html.Node, a atom.Atom) *html.Node {
+	if found := findAll(root, func(n *html.Node) bool { return n.DataAtom == a }); len(found) > 0 {
+		return found[0]
+	}
+	return nil
+}
+
+// hasAncestor reports whether one of n's ancestors matches.
+func hasAncestor(n *html.Node, match func(*html.Node) bool) bool {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// innerText returns an element's text with whitespace collapsed.
+func innerText(n *html.Node) string {
+	var sb strings.Builder
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+			sb.WriteByte(' ')
+		}
+		return true
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// textLength is the length of an element's collapsed text.
+func textLength(n *html.Node) int {
+	return len(innerText(n))
+}
+
+// hasAttr reports whether an element has an attribute.
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package fetcher
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const prose = "JetStream persists messages, so consumers can replay them later, at their own pace, and acknowledge each one."
+
+// extractMarkdown extracts a page's content with the rule for pageURL and
+// converts it to markdown.
+func extractMarkdown(t *testing.T, page string, cfg *SiteConfig, pageURL string) string {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(pageURL)
+	md, err := htmltomarkdown.ConvertNode(extractContent(doc, cfg.rulesFor(u)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(md)
+}
+
+func TestExtractContent(t *testing.T) {
+	chrome := `<header><a href="/">Home</a> <a href="/docs">Docs</a></header>
+<nav><ul><li><a href="/a">Getting started with the NATS server</a></li></ul></nav>
+<div class="cookie-banner">We use cookies to improve your experience, accept them all.</div>
+<div style="display: none">Hidden survey asking how we are doing today?</div>`
+	footer := `<footer><p>Copyright 2024, the NATS authors, all rights reserved.</p></footer>`
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+		drop []string
+	}{
+		{
+			name: "main element",
+			body: chrome + `<main><h1>Streams</h1><p>` + prose + `</p></main>
+<aside><p>Related: Key/Value stores, Object stores, and more, all built on streams.</p></aside>` + footer,
+			want: []string{"# Streams", "JetStream persists"},
+			drop: []string{"Home", "Getting started", "cookies", "Hidden survey", "Related", "Copyright"},
+		},
+		{
+			name: "single article",
+			body: chrome + `<div><article><header><h1>Streams</h1></header><p>` + prose + `</p></article></div>` + footer,
+			want: []string{"# Streams", "JetStream persists"},
+			drop: []string{"Home", "cookies", "Copyright"},
+		},
+		{
+			name: "text density",
+			body: chrome + `<div id="wrapper">
+<div class="links"><p><a href="/x">A list of links to other pages, with nothing else</a></p></div>
+<div class="entry"><h2>Streams</h2><p>` + prose + `</p><p>` + prose + `</p><pre>nats stream add ORDERS --subjects "orders.*"</pre></div>
+</div>` + footer,
+			want: []string{"## Streams", "JetStream persists", "nats stream add"},
+			drop: []string{"A list of links", "cookies", "Copyright"},
+		},
+		{
+			name: "page wrapped in a form",
+			body: `<form id="aspnetForm" action="/streams.aspx">` + chrome + `<div class="entry"><h2>Streams</h2><p>` + prose + `</p><p>` + prose + `</p></div>
+<form class="search"><input name="q"><button>Search the documentation</button></form>` + footer + `</form>`,
+			want: []string{"## Streams", "JetStream persists"},
+			drop: []string{"Search the documentation", "cookies", "Copyright"},
+		},
+		{
+			name: "unlikely class around the page",
+			body: `<div class="page-header-wrapper">` + chrome + `<h1>Streams</h1><p>` + prose + `</p><p>` + prose + `</p></div>`,
+			want: []string{"# Streams", "JetStream persists"},
+			drop: []string{"cookies", "Hidden survey"},
+		},
+		{
+			name: "no clear content",
+			body: `<p>Short.</p><div><p>Also short.</p></div>`,
+			want: []string{"Short.", "Also short."},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := extractMarkdown(t, "<html><body>"+tt.body+"</body></html>", nil, "https://docs.nats.io/streams")
+			for _, s := range tt.want {
+				if !strings.Contains(md, s) {
+					t.Errorf("missing %q in:\n%s", s, md)
+				}
+			}
+			for _, s := range tt.drop {
+				if strings.Contains(md, s) {
+					t.Errorf("kept %q in:\n%s", s, md)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractContentSiteRules(t *testing.T) {
+	cfg, err := ParseSiteConfig([]byte(`
+sites:
+  docs.nats.io:
+    extract:
+      include: [".intro", ".body"]
+      exclude: [".edit-link"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := `<html><body><main><p>` + prose + `</p></main>
+<div class="body"><p>Second part of the page.</p><a class="edit-link" href="/edit">Edit this page</a></div>
+<div class="intro"><p>First part of the page.</p></div>
+</body></html>`
+
+	md := extractMarkdown(t, page, cfg, "https://docs.nats.io/streams")
+	if strings.Contains(md, "JetStream") || strings.Contains(md, "Edit this page") {
+		t.Errorf("selectors not applied:\n%s", md)
+	}
+	if i, j := strings.Index(md, "Second part"), strings.Index(md, "First part"); i < 0 || j < 0 || i > j {
+		t.Errorf("want included elements in document order:\n%s", md)
+	}
+
+	// Other sites use the heuristics
+	md = extractMarkdown(t, page, cfg, "https://example.com/streams")
+	if !strings.Contains(md, "JetStream") || strings.Contains(md, "First part") {
+		t.Errorf("other site:\n%s", md)
+	}
+}
+
+func TestExtractContentTieBreak(t *testing.T) {
+	// Two unrelated sections scoring the same: the first one wins, every time
+	section := `<div class="section"><p>` + prose + `</p></div>`
+	page := `<html><body><div><span>First</span>` + section + `</div><div><span>Second</span>` + section + `</div></body></html>`
+	for range 20 {
+		doc, _ := html.Parse(strings.NewReader(page))
+		sections := findAll(doc, func(n *html.Node) bool { return attr(n, "class") == "section" })
+		if got := bestCandidate(findElement(doc, atom.Body)); got != sections[0] {
+			t.Fatalf("best candidate = %q, want the first section", innerText(got.Parent))
+		}
+	}
+}
+
+func TestFetchFallsBackToWholePage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body><nav><p>The only text on this page lives in its nav.</p></nav></body></html>")
+	}))
+	defer srv.Close()
+
+	md, err := NewHTTPFetcher().FetchAsMarkdown(srv.URL)
+	if err != nil || !strings.Contains(md, "The only text") {
+		t.Errorf("FetchAsMarkdown = %q, %v; want the whole page", md, err)
+	}
+}
@@ -3,14 +3,12 @@
 package fetcher
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"time"
-
-	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
-	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
 )
 
 // Fetcher abstracts URL fetching and conversion for testability.
@@ -22,60 +20,216 @@ type Fetcher interface {
 
 // HTTPFetcher is the production implementation using real HTTP requests.
 type HTTPFetcher struct {
-	client *http.Client
+	client  *http.Client
+	policy  Policy
+	hosts   *hostLimits
+	sites   *SiteConfig // Per-site settings (nil: none)
+	extract bool        // Reduce HTML pages to their main content
+}
+
+// Option configures an HTTPFetcher.
+type Option func(*HTTPFetcher)
+
+// WithSiteConfig sets per-site settings, such as selectors overriding
+// main-content extraction.
+func WithSiteConfig(cfg *SiteConfig) Option {
+	return func(f *HTTPFetcher) {
+		f.sites = cfg
+	}
 }
 
-// NewHTTPFetcher creates a new HTTPFetcher with sensible defaults.
-func NewHTTPFetcher() *HTTPFetcher {
-	return &HTTPFetcher{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
+// WithContentExtraction enables or disables main-content extraction
+// (enabled by default). Disabled, whole pages are converted, navigation and
+// all; site selectors still apply.
+func WithContentExtraction(enabled bool) Option {
+	return func(f *HTTPFetcher) {
+		f.extract = enabled
+	}
+}
+
+// NewHTTPFetcher creates a new HTTPFetcher with sensible defaults (see
+// Policy).
+func NewHTTPFetcher(opts ...Option) *HTTPFetcher {
+	f := &HTTPFetcher{
+		policy:  Policy{}.withDefaults(),
+		extract: true,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	f.client = &http.Client{
+		Timeout: f.policy.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > f.policy.MaxRedirects {
+				return &FetchError{
+					Kind: KindRedirects,
+					URL:  via[0].URL.String(),
+					Err:  fmt.Errorf("stopped after %d redirects", f.policy.MaxRedirects),
+				}
+			}
+			return nil
 		},
 	}
+	f.hosts = newHostLimits(f.policy)
+	return f
 }
 
-// FetchAsMarkdown fetches a URL and converts HTML to markdown.
+// FetchAsMarkdown fetches a URL and converts HTML to markdown (plain text and
+// markdown pages are returned as they are; see FetchConditional).
 func (f *HTTPFetcher) FetchAsMarkdown(urlStr string) (string, error) {
-	// Parse URL to extract domain for relative link resolution
-	parsedURL, err := url.Parse(urlStr)
+	resp, err := f.FetchConditional(context.Background(), urlStr, Validators{})
 	if err != nil {
-		return "", fmt.Errorf("parse URL: %w", err)
+		return "", err
 	}
+	return resp.Markdown, nil
+}
 
-	// Build base URL for relative link resolution
-	domain := fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host)
+// Revalidator is implemented by fetchers that can make conditional requests,
+// so cached pages can be checked for changes cheaply.
+type Revalidator interface {
+	// FetchConditional fetches a URL as markdown, sending v as
+	// If-None-Match/If-Modified-Since. A 304 yields NotModified and no markdown.
+	FetchConditional(ctx context.Context, urlStr string, v Validators) (*Response, error)
+}
+
+// Validators are a response's cache validators.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// Response is the result of a conditional fetch.
+type Response struct {
+	Markdown    string
+	Validators  Validators // Of the response (of the cached copy on a 304)
+	NotModified bool
+}
 
-	// Fetch the page
-	req, err := http.NewRequest(http.MethodGet, urlStr, nil)
+// FetchConditional fetches a URL and converts HTML to markdown, unless the
+// server reports it unchanged since the response v came from. Plain text and
+// markdown are returned as they are. File pages on GitHub, GitLab and Gitea
+// ("blob" URLs) are fetched from their raw URL.
+func (f *HTTPFetcher) FetchConditional(ctx context.Context, urlStr string, v Validators) (*Response, error) {
+	u, err := url.Parse(urlStr)
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return nil, &FetchError{Kind: KindInvalidURL, URL: urlStr, Err: fmt.Errorf("parse URL: %w", err)}
+	}
+	if raw := rawURL(u); raw != nil {
+		u = raw
 	}
-	req.Header.Set("User-Agent", "mcp-md-index/1.0")
 
-	resp, err := f.client.Do(req)
+	var header http.Header
+	if v.ETag != "" || v.LastModified != "" {
+		header = make(http.Header)
+		if v.ETag != "" {
+			header.Set("If-None-Match", v.ETag)
+		}
+		if v.LastModified != "" {
+			header.Set("If-Modified-Since", v.LastModified)
+		}
+	}
+	page, err := f.fetchPage(ctx, u, header)
 	if err != nil {
-		return "", fmt.Errorf("fetch URL: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	if page.notModified {
+		// A 304 may carry updated validators
+		if page.validators.ETag == "" {
+			page.validators.ETag = v.ETag
+		}
+		if page.validators.LastModified == "" {
+			page.validators.LastModified = v.LastModified
+		}
+		return &Response{Validators: page.validators, NotModified: true}, nil
+	}
+	return &Response{Markdown: page.markdown, Validators: page.validators}, nil
+}
+
+// validators returns a response's ETag and Last-Modified headers.
+func validators(resp *http.Response) Validators {
+	return Validators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+}
+
+// userAgent identifies the fetcher to web servers.
+const userAgent = "mcp-md-index/1.0"
+
+// get fetches urlStr with the extra request headers and reads the response
+// body, following the fetch policy: requests wait for a slot and a rate token
+// of their host, and timeouts, dropped connections and 429/502/503/504
+// responses are retried with backoff (or after the server's Retry-After).
+// Other statuses are returned to the caller; a retryable status that persists
+// is a *FetchError, like transport failures and oversized bodies. The
+// response's Request.URL is the final URL after redirects.
+func (f *HTTPFetcher) get(ctx context.Context, urlStr string, header http.Header) (*http.Response, []byte, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil || u.Host == "" {
+		return nil, nil, &FetchError{Kind: KindInvalidURL, URL: urlStr, Err: fmt.Errorf("invalid URL %q", urlStr)}
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, body, err := f.attempt(ctx, u, header)
+
+		retry, wait := false, f.policy.backoff(attempt)
+		switch {
+		case err != nil:
+			retry = retryError(ctx, err)
+		case retryStatus(resp.StatusCode):
+			retry = true
+			if d, ok := retryAfter(resp, time.Now()); ok {
+				wait = d
+			}
+		}
+		if retry && attempt < f.policy.MaxAttempts && wait <= f.policy.MaxRetryWait && sleep(ctx, wait) == nil {
+			continue
+		}
+
+		switch {
+		case err != nil:
+			return nil, nil, transportError(urlStr, err, attempt)
+		case retry:
+			return nil, nil, statusError(resp, attempt)
+		}
+		return resp, body, nil
+	}
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+// attempt makes one request, within the host's limits, and reads the body.
+func (f *HTTPFetcher) attempt(ctx context.Context, u *url.URL, header http.Header) (*http.Response, []byte, error) {
+	release, err := f.hosts.acquire(ctx, u.Host)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer release()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return "", fmt.Errorf("read body: %w", err)
+		return nil, nil, fmt.Errorf("create request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
 	}
+	req.Header.Set("User-Agent", userAgent)
 
-	// Convert HTML to markdown with domain for absolute URLs
-	markdown, err := htmltomarkdown.ConvertString(
-		string(body),
-		converter.WithDomain(domain),
-	)
+	resp, err := f.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("convert to markdown: %w", err)
+		return nil, nil, fmt.Errorf("fetch URL: %w", err)
 	}
+	defer resp.Body.Close()
 
-	return markdown, nil
+	tooLarge := &FetchError{
+		Kind: KindTooLarge,
+		URL:  u.String(),
+		Err:  fmt.Errorf("response body over %d bytes", f.policy.MaxBodySize),
+	}
+	if resp.ContentLength > f.policy.MaxBodySize {
+		return nil, nil, tooLarge
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.policy.MaxBodySize+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read body: %w", err)
+	}
+	if int64(len(body)) > f.policy.MaxBodySize {
+		return nil, nil, tooLarge
+	}
+	return resp, body, nil
 }
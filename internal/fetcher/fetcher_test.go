@@ -0,0 +1,52 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchConditional(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/etag":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			fmt.Fprint(w, "<html><body><h1>Streams</h1></body></html>")
+		case "/modified":
+			w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+			if r.Header.Get("If-Modified-Since") == "Mon, 01 Jan 2024 00:00:00 GMT" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Content-Type", "text/markdown")
+			fmt.Fprint(w, "# Streams\n\n*Markdown* stays as is.\n")
+		}
+	}))
+	defer srv.Close()
+	f := NewHTTPFetcher()
+	ctx := context.Background()
+
+	resp, err := f.FetchConditional(ctx, srv.URL+"/etag", Validators{})
+	if err != nil || resp.NotModified || resp.Markdown != "# Streams" || resp.Validators.ETag != `"v1"` {
+		t.Fatalf("first fetch = %+v, %v", resp, err)
+	}
+	resp, err = f.FetchConditional(ctx, srv.URL+"/etag", resp.Validators)
+	if err != nil || !resp.NotModified || resp.Markdown != "" || resp.Validators.ETag != `"v1"` {
+		t.Errorf("revalidation = %+v, %v", resp, err)
+	}
+
+	resp, err = f.FetchConditional(ctx, srv.URL+"/modified", Validators{})
+	if err != nil || resp.Markdown != "# Streams\n\n*Markdown* stays as is.\n" {
+		t.Fatalf("markdown fetch = %+v, %v", resp, err)
+	}
+	resp, err = f.FetchConditional(ctx, srv.URL+"/modified", Validators{LastModified: resp.Validators.LastModified})
+	if err != nil || !resp.NotModified {
+		t.Errorf("revalidation = %+v, %v", resp, err)
+	}
+}
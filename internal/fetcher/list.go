@@ -0,0 +1,383 @@
+package fetcher
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Lister is implemented by fetchers that can read the page lists sites
+// publish for crawlers and LLMs: sitemap.xml and llms.txt.
+type Lister interface {
+	// List reads a sitemap (following sitemap indexes), llms.txt or
+	// llms-full.txt file.
+	List(ctx context.Context, listURL string, opts ListOptions) (*Listing, error)
+
+	// FetchPages fetches pages, calling visit with each page as markdown
+	// (or the error fetching it). visit is never called concurrently.
+	FetchPages(ctx context.Context, urls []string, visit func(Page)) (*CrawlStats, error)
+}
+
+// List formats.
+const (
+	ListSitemap  = "sitemap"
+	ListLLMs     = "llms.txt"
+	ListLLMsFull = "llms-full.txt"
+)
+
+// DefaultListPages is the default limit on the pages a list yields.
+const DefaultListPages = 200
+
+// maxSitemaps caps how many sitemaps a sitemap index may pull in.
+const maxSitemaps = 50
+
+// ListOptions limits the pages a list yields.
+type ListOptions struct {
+	MaxPages int      // Entries returned at most (default: 200)
+	Include  []string // Path globs entries must match, e.g. "/docs/**" (empty: all)
+	Exclude  []string // Path globs of entries to skip
+}
+
+// Listing is a parsed sitemap or llms.txt file.
+type Listing struct {
+	URL     string
+	Format  string // ListSitemap, ListLLMs or ListLLMsFull
+	Title   string // llms.txt H1
+	Summary string // llms.txt blockquote
+
+	// Markdown is the llms.txt or llms-full.txt file itself ("" for sitemaps)
+	Markdown string
+
+	Entries   []ListEntry
+	Sitemaps  int // Sitemaps read, including the index
+	Truncated int // Matching entries dropped by MaxPages (of the sitemaps read)
+}
+
+// ListEntry is a page listed by a sitemap or llms.txt.
+type ListEntry struct {
+	URL         string
+	LastMod     time.Time // Sitemap <lastmod>, zero if unknown
+	Title       string    // llms.txt link text
+	Description string    // llms.txt notes after the link
+	Section     string    // llms.txt "##" section
+	Optional    bool      // In the llms.txt "Optional" section
+}
+
+// List fetches and parses listURL. XML content is read as a sitemap or
+// sitemap index (gzipped or not); anything else as llms.txt, unless the file
+// is named llms-full.txt, whose content is the documentation itself.
+func (f *HTTPFetcher) List(ctx context.Context, listURL string, opts ListOptions) (*Listing, error) {
+	u, err := url.Parse(listURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("not an http(s) URL: %s", listURL)
+	}
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = DefaultListPages
+	}
+
+	body, err := f.getList(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &listing{opts: opts, seen: make(map[string]bool), Listing: &Listing{URL: listURL}}
+	for _, p := range opts.Include {
+		l.include = append(l.include, pathGlob(p))
+	}
+	for _, p := range opts.Exclude {
+		l.exclude = append(l.exclude, pathGlob(p))
+	}
+
+	switch {
+	case looksLikeXML(body):
+		l.Format = ListSitemap
+		if err := l.sitemap(ctx, f, u, body); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(path.Base(u.Path), "llms-full"):
+		l.Format = ListLLMsFull
+		l.Markdown = string(body)
+		l.Title, l.Summary = llmsHeader(l.Markdown)
+	default:
+		l.Format = ListLLMs
+		l.Markdown = string(body)
+		l.llms(u, l.Markdown)
+	}
+	return l.Listing, nil
+}
+
+// getList fetches a list file, gunzipping it if needed.
+func (f *HTTPFetcher) getList(ctx context.Context, listURL string) ([]byte, error) {
+	resp, body, err := f.get(ctx, listURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %w", listURL, statusError(resp, 1))
+	}
+	if len(body) > 2 && body[0] == 0x1f && body[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", listURL, err)
+		}
+		if body, err = io.ReadAll(io.LimitReader(zr, f.policy.MaxBodySize)); err != nil {
+			return nil, fmt.Errorf("%s: %w", listURL, err)
+		}
+	}
+	return body, nil
+}
+
+// listing is the state of reading one list.
+type listing struct {
+	*Listing
+	opts             ListOptions
+	include, exclude []*regexp.Regexp
+	seen             map[string]bool
+}
+
+// add appends an entry unless it is a duplicate, filtered out or over the
+// page limit.
+func (l *listing) add(e ListEntry) {
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	e.URL = u.String()
+	if l.seen[e.URL] || !l.matches(u) {
+		return
+	}
+	l.seen[e.URL] = true
+	if len(l.Entries) >= l.opts.MaxPages {
+		l.Truncated++
+		return
+	}
+	l.Entries = append(l.Entries, e)
+}
+
+// matches applies the include and exclude patterns to u's path.
+func (l *listing) matches(u *url.URL) bool {
+	return matchesGlobs(u, l.include, l.exclude)
+}
+
+// ─── Sitemaps ────────────────────────────────────────────────────────────────
+
+// sitemapXML is a <urlset> or <sitemapindex> document.
+type sitemapXML struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+// sitemapLoc is a <url> or <sitemap> element.
+type sitemapLoc struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// sitemap reads a sitemap, and the sitemaps of a sitemap index, breadth
+// first until MaxPages entries are found.
+func (l *listing) sitemap(ctx context.Context, f *HTTPFetcher, u *url.URL, body []byte) error {
+	visited := map[string]bool{u.String(): true}
+	for queue := []string{u.String()}; len(queue) > 0 && len(l.Entries) < l.opts.MaxPages; queue = queue[1:] {
+		if l.Sitemaps > 0 {
+			var err error
+			if body, err = f.getList(ctx, queue[0]); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				continue // An unreachable child sitemap loses only its own pages
+			}
+		}
+
+		var doc sitemapXML
+		if err := xml.Unmarshal(body, &doc); err != nil {
+			if l.Sitemaps == 0 {
+				return fmt.Errorf("parse sitemap: %w", err)
+			}
+			continue
+		}
+		l.Sitemaps++
+		switch doc.XMLName.Local {
+		case "urlset":
+			for _, loc := range doc.URLs {
+				l.add(ListEntry{URL: strings.TrimSpace(loc.Loc), LastMod: parseLastMod(loc.LastMod)})
+			}
+		case "sitemapindex":
+			for _, loc := range doc.Sitemaps {
+				child, err := u.Parse(strings.TrimSpace(loc.Loc))
+				if err != nil || visited[child.String()] || len(visited) > maxSitemaps {
+					continue
+				}
+				visited[child.String()] = true
+				queue = append(queue, child.String())
+			}
+		default:
+			if l.Sitemaps == 1 {
+				return fmt.Errorf("parse sitemap: unexpected <%s> root element", doc.XMLName.Local)
+			}
+		}
+	}
+	return nil
+}
+
+// lastModLayouts are the W3C datetime forms allowed in <lastmod>.
+var lastModLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// parseLastMod parses a <lastmod> value, returning the zero time if it is
+// missing or malformed.
+func parseLastMod(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range lastModLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// looksLikeXML reports whether body is an XML document.
+func looksLikeXML(body []byte) bool {
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	return bytes.HasPrefix(bytes.TrimLeft(body, " \t\r\n"), []byte("<"))
+}
+
+// ─── llms.txt ────────────────────────────────────────────────────────────────
+
+// llmsLinkRe matches an llms.txt list item: "- [Title](url): notes".
+var llmsLinkRe = regexp.MustCompile(`^\s*[-*+]\s+\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)\s*(?::\s*(.*))?$`)
+
+// llms reads the file lists of an llms.txt file: the links listed under its
+// "##" sections. Links in the "Optional" section are marked as such.
+func (l *listing) llms(u *url.URL, content string) {
+	l.Title, l.Summary = llmsHeader(content)
+
+	section := ""
+	inFence := false
+	sc := bufio.NewScanner(strings.NewReader(content))
+	sc.Buffer(make([]byte, 0, 64*1024), DefaultMaxBodySize)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if inFence {
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			section = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			continue
+		}
+		m := llmsLinkRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		target, err := u.Parse(m[2])
+		if err != nil {
+			continue
+		}
+		l.add(ListEntry{
+			URL:         target.String(),
+			Title:       strings.TrimSpace(m[1]),
+			Description: strings.TrimSpace(m[3]),
+			Section:     section,
+			Optional:    strings.EqualFold(section, "Optional"),
+		})
+	}
+}
+
+// llmsHeader returns an llms.txt file's H1 title and blockquote summary.
+func llmsHeader(content string) (title, summary string) {
+	var quote []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case title == "" && strings.HasPrefix(line, "# "):
+			title = strings.TrimSpace(line[2:])
+		case title != "" && strings.HasPrefix(line, ">"):
+			quote = append(quote, strings.TrimSpace(strings.TrimPrefix(line, ">")))
+		case title != "" && (len(quote) > 0 || strings.HasPrefix(line, "#")):
+			return title, strings.Join(quote, " ")
+		}
+	}
+	return title, strings.Join(quote, " ")
+}
+
+// ─── Fetching listed pages ───────────────────────────────────────────────────
+
+// FetchPages fetches listed pages, a few at a time, reporting each under its
+// listed URL even when it redirects or is a code forge file fetched from its
+// raw URL (see FetchConditional). The robots.txt of each
+// host is honored: disallowed pages are skipped, and a Crawl-delay makes the
+// fetches sequential, spaced by the longest delay asked for.
+func (f *HTTPFetcher) FetchPages(ctx context.Context, urls []string, visit func(Page)) (*CrawlStats, error) {
+	c := &crawl{
+		opts:   CrawlOptions{MaxPages: len(urls), Concurrency: DefaultCrawlConcurrency},
+		robots: &robotsRules{},
+		visit:  visit,
+		listed: true,
+		seen:   make(map[string]bool),
+		stats:  &CrawlStats{},
+	}
+
+	hosts := make(map[string]*robotsRules)
+	var level []*url.URL
+	for _, s := range urls {
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.stats.Failed++
+			visit(Page{URL: s, Err: fmt.Errorf("not an http(s) URL: %s", s)})
+			continue
+		}
+		if c.seen[u.String()] {
+			continue
+		}
+		c.seen[u.String()] = true
+
+		key := u.Scheme + "://" + u.Host
+		rules, ok := hosts[key]
+		if !ok {
+			if rules, err = f.robots(ctx, u); err != nil {
+				if ctx.Err() != nil {
+					return c.stats, ctx.Err()
+				}
+				rules = &robotsRules{} // Unreachable robots.txt: the pages will likely fail too
+			}
+			hosts[key] = rules
+			c.robots.delay = max(c.robots.delay, rules.delay)
+		}
+		if !rules.allowed(u) {
+			c.stats.Disallowed++
+			continue
+		}
+		level = append(level, u)
+	}
+
+	c.stats.CrawlDelay = c.robots.delay
+	if c.robots.delay > 0 {
+		c.opts.Concurrency = 1
+	}
+	c.fetchLevel(ctx, f, level, 0)
+	return c.stats, ctx.Err()
+}
@@ -0,0 +1,183 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newListSite serves a sitemap index with a plain and a gzipped sitemap, an
+// llms.txt file and the pages they list.
+func newListSite(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%[1]s/sitemap-docs.xml</loc></sitemap>
+  <sitemap><loc>/sitemap-blog.xml.gz</loc></sitemap>
+  <sitemap><loc>%[1]s/missing.xml</loc></sitemap>
+</sitemapindex>`, srv.URL)
+	})
+	mux.HandleFunc("/sitemap-docs.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/docs/streams</loc><lastmod>2024-03-01</lastmod></url>
+  <url><loc>%[1]s/docs/consumers#top</loc><lastmod>2024-03-02T10:00:00+01:00</lastmod></url>
+  <url><loc>%[1]s/docs/streams</loc></url>
+  <url><loc>mailto:docs@example.com</loc></url>
+</urlset>`, srv.URL)
+	})
+	mux.HandleFunc("/sitemap-blog.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		var gz bytes.Buffer
+		zw := gzip.NewWriter(&gz)
+		fmt.Fprintf(zw, `<urlset><url><loc>%s/blog/release</loc></url></urlset>`, srv.URL)
+		zw.Close()
+		w.Write(gz.Bytes())
+	})
+	mux.HandleFunc("/llms.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# NATS\n\n> Messaging for distributed systems.\n> Docs for LLMs.\n\nSome notes.\n\n"+
+			"## Docs\n\n- [Streams](/docs/streams.md): Durable message storage\n- [Consumers](docs/consumers.md)\n\n"+
+			"```\n- [Not a link](/ignored)\n```\n\n"+
+			"## Optional\n\n- [Blog](https://blog.example.com/post \"Blog\")\n")
+	})
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+	})
+	mux.HandleFunc("/docs/streams.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/markdown")
+		fmt.Fprint(w, "# Streams\n\nStreams store messages.\n")
+	})
+	mux.HandleFunc("/old/consumers", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/docs/consumers", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/docs/consumers", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body><h1>Consumers</h1><p>Consumers read streams.</p></body></html>")
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestList_SitemapIndex(t *testing.T) {
+	srv := newListSite(t)
+	l, err := NewHTTPFetcher().List(context.Background(), srv.URL+"/sitemap.xml", ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if l.Format != ListSitemap || l.Sitemaps != 3 {
+		t.Errorf("format %q, %d sitemaps read", l.Format, l.Sitemaps)
+	}
+
+	var got []string
+	for _, e := range l.Entries {
+		got = append(got, strings.TrimPrefix(e.URL, srv.URL))
+	}
+	if strings.Join(got, " ") != "/docs/streams /docs/consumers /blog/release" {
+		t.Errorf("entries = %v", got)
+	}
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); !l.Entries[0].LastMod.Equal(want) {
+		t.Errorf("lastmod = %s, want %s", l.Entries[0].LastMod, want)
+	}
+	if want := time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC); !l.Entries[1].LastMod.Equal(want) {
+		t.Errorf("lastmod = %s, want %s", l.Entries[1].LastMod, want)
+	}
+	if !l.Entries[2].LastMod.IsZero() {
+		t.Errorf("missing lastmod parsed as %s", l.Entries[2].LastMod)
+	}
+}
+
+func TestList_FiltersAndLimit(t *testing.T) {
+	srv := newListSite(t)
+	f := NewHTTPFetcher()
+
+	l, err := f.List(context.Background(), srv.URL+"/sitemap.xml", ListOptions{Exclude: []string{"/blog/**"}})
+	if err != nil || len(l.Entries) != 2 {
+		t.Fatalf("exclude: %+v, %v", l, err)
+	}
+
+	l, err = f.List(context.Background(), srv.URL+"/sitemap.xml", ListOptions{MaxPages: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The index stops at the first sitemap once it has enough pages
+	if len(l.Entries) != 1 || l.Truncated != 1 || l.Sitemaps != 2 {
+		t.Errorf("max pages: %d entries, %d truncated, %d sitemaps", len(l.Entries), l.Truncated, l.Sitemaps)
+	}
+}
+
+func TestList_LLMsTxt(t *testing.T) {
+	srv := newListSite(t)
+	l, err := NewHTTPFetcher().List(context.Background(), srv.URL+"/llms.txt", ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if l.Format != ListLLMs || l.Title != "NATS" || l.Summary != "Messaging for distributed systems. Docs for LLMs." {
+		t.Errorf("header: %q %q %q", l.Format, l.Title, l.Summary)
+	}
+	if !strings.HasPrefix(l.Markdown, "# NATS") {
+		t.Errorf("markdown = %q", l.Markdown)
+	}
+
+	want := []ListEntry{
+		{URL: srv.URL + "/docs/streams.md", Title: "Streams", Description: "Durable message storage", Section: "Docs"},
+		{URL: srv.URL + "/docs/consumers.md", Title: "Consumers", Section: "Docs"},
+		{URL: "https://blog.example.com/post", Title: "Blog", Section: "Optional", Optional: true},
+	}
+	if len(l.Entries) != len(want) {
+		t.Fatalf("entries = %+v", l.Entries)
+	}
+	for i := range want {
+		if l.Entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, l.Entries[i], want[i])
+		}
+	}
+}
+
+func TestFetchPages(t *testing.T) {
+	srv := newListSite(t)
+	urls := []string{
+		srv.URL + "/docs/streams.md",
+		srv.URL + "/old/consumers",
+		srv.URL + "/private/keys",
+		srv.URL + "/docs/missing",
+	}
+
+	pages := make(map[string]Page)
+	stats, err := NewHTTPFetcher().FetchPages(context.Background(), urls, func(p Page) {
+		pages[strings.TrimPrefix(p.URL, srv.URL)] = p
+	})
+	if err != nil {
+		t.Fatalf("FetchPages: %v", err)
+	}
+	if stats.Fetched != 2 || stats.Failed != 1 || stats.Disallowed != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if p := pages["/docs/streams.md"]; p.Markdown != "# Streams\n\nStreams store messages.\n" {
+		t.Errorf("markdown page = %q", p.Markdown)
+	}
+	// Redirected pages keep their listed URL
+	if p := pages["/old/consumers"]; !strings.Contains(p.Markdown, "Consumers read streams.") {
+		t.Errorf("redirected page = %+v", p)
+	}
+	if p := pages["/docs/missing"]; p.Err == nil {
+		t.Error("expected an error for a missing page")
+	}
+}
+
+func TestParseLastMod(t *testing.T) {
+	for _, s := range []string{"2024-03-01", "2024-03-01T00:00Z", "2024-03-01T00:00:00Z", "2024-03-01T01:00:00.5+01:00"} {
+		if got := parseLastMod(s); got.Year() != 2024 {
+			t.Errorf("parseLastMod(%q) = %s", s, got)
+		}
+	}
+	if !parseLastMod("yesterday").IsZero() {
+		t.Error("expected zero time for a malformed lastmod")
+	}
+}
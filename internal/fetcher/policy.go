@@ -0,0 +1,313 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy controls how the fetcher treats web servers: how many requests it
+// makes to a host at once and per second, how it retries, and what it accepts.
+// Zero fields take the defaults.
+type Policy struct {
+	Timeout      time.Duration // Per attempt, including reading the body (default: 30s)
+	MaxAttempts  int           // Attempts per request, including the first (default: 3)
+	RetryBackoff time.Duration // Delay before the first retry, doubled per retry, with jitter (default: 500ms)
+	MaxRetryWait time.Duration // Longest wait between attempts; a longer Retry-After fails the request (default: 30s)
+
+	HostConcurrency int     // Requests in flight per host (default: 4)
+	HostRate        float64 // Requests per second per host; negative means unlimited (default: 5)
+	HostBurst       int     // Requests a host may get at once before HostRate applies (default: 5)
+
+	MaxBodySize  int64 // Largest response body accepted, in bytes (default: 20 MiB)
+	MaxRedirects int   // Redirects followed per request (default: 10)
+}
+
+// Policy defaults.
+const (
+	DefaultFetchTimeout    = 30 * time.Second
+	DefaultMaxAttempts     = 3
+	DefaultRetryBackoff    = 500 * time.Millisecond
+	DefaultMaxRetryWait    = 30 * time.Second
+	DefaultHostConcurrency = 4
+	DefaultHostRate        = 5
+	DefaultHostBurst       = 5
+	DefaultMaxBodySize     = 20 << 20
+	DefaultMaxRedirects    = 10
+)
+
+// withDefaults fills in zero fields.
+func (p Policy) withDefaults() Policy {
+	if p.Timeout <= 0 {
+		p.Timeout = DefaultFetchTimeout
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.RetryBackoff <= 0 {
+		p.RetryBackoff = DefaultRetryBackoff
+	}
+	if p.MaxRetryWait <= 0 {
+		p.MaxRetryWait = DefaultMaxRetryWait
+	}
+	if p.HostConcurrency <= 0 {
+		p.HostConcurrency = DefaultHostConcurrency
+	}
+	if p.HostRate == 0 {
+		p.HostRate = DefaultHostRate
+	}
+	if p.HostBurst <= 0 {
+		p.HostBurst = DefaultHostBurst
+	}
+	if p.MaxBodySize <= 0 {
+		p.MaxBodySize = DefaultMaxBodySize
+	}
+	if p.MaxRedirects <= 0 {
+		p.MaxRedirects = DefaultMaxRedirects
+	}
+	return p
+}
+
+// WithPolicy sets the fetch policy.
+func WithPolicy(p Policy) Option {
+	return func(f *HTTPFetcher) {
+		f.policy = p.withDefaults()
+	}
+}
+
+// ─── Errors ──────────────────────────────────────────────────────────────────
+
+// ErrorKind categorizes fetch failures.
+type ErrorKind string
+
+// Kinds of fetch failures.
+const (
+	KindInvalidURL  ErrorKind = "invalid_url"
+	KindNetwork     ErrorKind = "network"      // DNS, connection or TLS failure
+	KindTimeout     ErrorKind = "timeout"      // No complete response within Policy.Timeout
+	KindRateLimited ErrorKind = "rate_limited" // HTTP 429, after retries
+	KindNotFound    ErrorKind = "not_found"    // HTTP 404 or 410
+	KindForbidden   ErrorKind = "forbidden"    // HTTP 401 or 403
+	KindClientError ErrorKind = "client_error" // Other HTTP 4xx
+	KindServerError ErrorKind = "server_error" // HTTP 5xx, after retries
+	KindTooLarge    ErrorKind = "too_large"    // Body over Policy.MaxBodySize
+	KindRedirects   ErrorKind = "redirects"    // Over Policy.MaxRedirects
+	KindUnsupported ErrorKind = "unsupported"  // Not HTML, markdown or plain text
+	KindOther       ErrorKind = "other"
+)
+
+// FetchError is a failed fetch.
+type FetchError struct {
+	Kind     ErrorKind
+	URL      string
+	Status   int // HTTP status, for HTTP errors
+	Attempts int // Requests made, when retried
+	Err      error
+}
+
+func (e *FetchError) Error() string {
+	msg := e.Err.Error()
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" (after %d attempts)", e.Attempts)
+	}
+	return msg
+}
+
+func (e *FetchError) Unwrap() error { return e.Err }
+
+// KindOf returns the kind of a fetch failure (KindOther for errors that
+// aren't fetch errors, "" for nil).
+func KindOf(err error) ErrorKind {
+	if err == nil {
+		return ""
+	}
+	var fe *FetchError
+	if errors.As(err, &fe) {
+		return fe.Kind
+	}
+	return KindOther
+}
+
+// statusError is the error for an HTTP error response.
+func statusError(resp *http.Response, attempts int) *FetchError {
+	kind := KindClientError
+	switch code := resp.StatusCode; {
+	case code == http.StatusTooManyRequests:
+		kind = KindRateLimited
+	case code == http.StatusNotFound || code == http.StatusGone:
+		kind = KindNotFound
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		kind = KindForbidden
+	case code >= 500:
+		kind = KindServerError
+	}
+	return &FetchError{
+		Kind:     kind,
+		URL:      resp.Request.URL.String(),
+		Status:   resp.StatusCode,
+		Attempts: attempts,
+		Err:      fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status),
+	}
+}
+
+// transportError categorizes an error from http.Client.Do.
+func transportError(urlStr string, err error, attempts int) *FetchError {
+	var fe *FetchError
+	if errors.As(err, &fe) {
+		return fe // From CheckRedirect
+	}
+	kind := KindNetwork
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		kind = KindOther
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		kind = KindTimeout
+	}
+	return &FetchError{Kind: kind, URL: urlStr, Attempts: attempts, Err: err}
+}
+
+// ─── Retries ─────────────────────────────────────────────────────────────────
+
+// retryStatus reports whether a response status is worth retrying.
+func retryStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryError reports whether a transport error is worth retrying: timeouts
+// and dropped connections are; unknown hosts, bad certificates, redirect
+// loops and cancellations are not.
+func retryError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var fe *FetchError
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	switch {
+	case errors.As(err, &fe), errors.As(err, &certErr):
+		return false
+	case errors.As(err, &dnsErr):
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	return true
+}
+
+// backoff is the wait before retry n (1-based): RetryBackoff doubled per
+// retry, capped at MaxRetryWait, with "equal jitter" (half fixed, half
+// random) so clients that failed together don't retry together.
+func (p Policy) backoff(n int) time.Duration {
+	d := p.RetryBackoff << (n - 1)
+	if d <= 0 || d > p.MaxRetryWait {
+		d = p.MaxRetryWait
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses a Retry-After header (seconds or an HTTP date).
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ─── Per-host limits ─────────────────────────────────────────────────────────
+
+// hostLimits bounds the requests made to each host.
+type hostLimits struct {
+	policy Policy
+	mu     sync.Mutex
+	hosts  map[string]*hostLimit
+}
+
+// hostLimit is one host's concurrency slots and rate limit.
+type hostLimit struct {
+	slots chan struct{}
+
+	mu     sync.Mutex // Guards the token bucket
+	tokens float64
+	last   time.Time
+}
+
+func newHostLimits(p Policy) *hostLimits {
+	return &hostLimits{policy: p, hosts: make(map[string]*hostLimit)}
+}
+
+// acquire waits for a request slot and a rate token for host, returning the
+// function that frees the slot.
+func (l *hostLimits) acquire(ctx context.Context, host string) (release func(), err error) {
+	l.mu.Lock()
+	h, ok := l.hosts[host]
+	if !ok {
+		h = &hostLimit{slots: make(chan struct{}, l.policy.HostConcurrency), tokens: float64(l.policy.HostBurst)}
+		l.hosts[host] = h
+	}
+	l.mu.Unlock()
+
+	select {
+	case h.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release = func() { <-h.slots }
+	if wait := h.reserve(l.policy, time.Now()); wait > 0 {
+		if err := sleep(ctx, wait); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// reserve takes a token from the host's bucket, which refills at HostRate up
+// to HostBurst tokens, and returns how long to wait until the token is due.
+// Tokens are reserved ahead (the bucket goes negative), so waiters are served
+// in order.
+func (h *hostLimit) reserve(p Policy, now time.Time) time.Duration {
+	if p.HostRate < 0 {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.last.IsZero() {
+		h.tokens = min(h.tokens+now.Sub(h.last).Seconds()*p.HostRate, float64(p.HostBurst))
+	}
+	h.last = now
+	h.tokens--
+	if h.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-h.tokens / p.HostRate * float64(time.Second))
+}
@@ -0,0 +1,153 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchPolicyErrors(t *testing.T) {
+	var mu sync.Mutex
+	hits := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		hit := hits[r.URL.Path]
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/flaky": // Recovers on the third attempt
+			if hit < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, "<html><body><p>Back again.</p></body></html>")
+		case "/throttled":
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/later":
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/big":
+			fmt.Fprint(w, strings.Repeat("x", 100))
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			fmt.Fprint(w, "PNG")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	f := NewHTTPFetcher(WithPolicy(Policy{RetryBackoff: time.Millisecond, MaxBodySize: 50, MaxRedirects: 3, HostRate: -1}))
+	ctx := context.Background()
+
+	resp, err := f.FetchConditional(ctx, srv.URL+"/flaky", Validators{})
+	if err != nil || resp.Markdown != "Back again." {
+		t.Fatalf("flaky = %+v, %v", resp, err)
+	}
+
+	tests := []struct {
+		path     string
+		kind     ErrorKind
+		requests int
+	}{
+		{"/throttled", KindRateLimited, 3},
+		{"/later", KindRateLimited, 1}, // Retry-After over MaxRetryWait
+		{"/missing", KindNotFound, 1},
+		{"/big", KindTooLarge, 1},
+		{"/loop", KindRedirects, 4}, // The request and 3 redirects
+		{"/image", KindUnsupported, 1},
+	}
+	for _, tt := range tests {
+		_, err := f.FetchConditional(ctx, srv.URL+tt.path, Validators{})
+		if got := KindOf(err); got != tt.kind {
+			t.Errorf("%s: kind = %q (%v), want %q", tt.path, got, err, tt.kind)
+		}
+		if hits[tt.path] != tt.requests {
+			t.Errorf("%s: %d requests, want %d", tt.path, hits[tt.path], tt.requests)
+		}
+	}
+
+	_, err = f.FetchConditional(ctx, srv.URL+"/throttled", Validators{})
+	if !strings.Contains(fmt.Sprint(err), "HTTP 429") || !strings.Contains(fmt.Sprint(err), "after 3 attempts") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestHostConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+	f := NewHTTPFetcher(WithPolicy(Policy{HostConcurrency: 2, HostRate: -1}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := f.get(context.Background(), srv.URL, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if p := peak.Load(); p > 2 {
+		t.Errorf("%d requests in flight, want at most 2", p)
+	}
+}
+
+func TestHostRate(t *testing.T) {
+	p := Policy{HostRate: 2, HostBurst: 2}.withDefaults()
+	h := &hostLimit{tokens: float64(p.HostBurst)}
+	now := time.Now()
+
+	// The burst is free, then requests are spaced by 1/rate
+	for i, want := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if got := h.reserve(p, now); got != want {
+			t.Errorf("request %d: wait %s, want %s", i, got, want)
+		}
+	}
+	// Tokens refill over time, up to the burst
+	if got := h.reserve(p, now.Add(10*time.Second)); got != 0 {
+		t.Errorf("after a pause: wait %s, want 0", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{"Mon, 01 Jan 2024 00:00:30 GMT", 30 * time.Second, true},
+		{"Sun, 31 Dec 2023 00:00:00 GMT", 0, true}, // In the past
+		{"soon", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{"Retry-After": {tt.header}}}
+		if got, ok := retryAfter(resp, now); got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %s, %v; want %s, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package fetcher
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// rawURL returns the raw-file URL for a file page of a code forge, or nil
+// for other URLs, so the file is fetched as it is instead of as the forge's
+// HTML rendering of it:
+//
+//	github.com/o/r/blob/ref/path          → raw.githubusercontent.com/o/r/ref/path
+//	<gitlab>/group/project/-/blob/ref/path → <gitlab>/group/project/-/raw/ref/path
+//	<gitea>/o/r/src/branch/ref/path       → <gitea>/o/r/raw/branch/ref/path (also tag/, commit/)
+//
+// GitLab and Gitea (Forgejo, Codeberg) are recognized by their path layout on
+// any host; GitHub only on github.com.
+func rawURL(u *url.URL) *url.URL {
+	segs := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	raw := *u
+	raw.RawQuery, raw.Fragment, raw.RawFragment, raw.RawPath = "", "", "", ""
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch {
+	case host == "github.com" && len(segs) >= 5 && (segs[2] == "blob" || segs[2] == "raw"):
+		raw.Host = "raw.githubusercontent.com"
+		raw.Path = "/" + path.Join(append(segs[:2:2], segs[3:]...)...)
+		return &raw
+	}
+	for i, s := range segs {
+		switch {
+		case s == "-" && i >= 2 && i+3 < len(segs) && segs[i+1] == "blob":
+			segs[i+1] = "raw"
+		case s == "src" && i == 2 && i+3 < len(segs) && (segs[i+1] == "branch" || segs[i+1] == "tag" || segs[i+1] == "commit"):
+			segs[i] = "raw"
+		default:
+			continue
+		}
+		raw.Path = "/" + strings.Join(segs, "/")
+		return &raw
+	}
+	return nil
+}
+
+// Media types of pages that are converted from HTML, and of pages that are
+// passed through as they are.
+var (
+	htmlTypes = map[string]bool{"text/html": true, "application/xhtml+xml": true}
+	textTypes = map[string]bool{"text/markdown": true, "text/x-markdown": true, "text/plain": true}
+)
+
+// textExtensions are the extensions of markdown and plain-text files, for
+// servers that don't label them.
+var textExtensions = map[string]bool{".md": true, ".markdown": true, ".mdown": true, ".txt": true, ".text": true}
+
+// mediaType returns a response's media type. Untyped and generically typed
+// (application/octet-stream) responses count as markdown if the URL names a
+// markdown or text file, and are sniffed otherwise.
+func mediaType(resp *http.Response, u *url.URL, body []byte) string {
+	mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mt {
+	case "", "application/octet-stream", "binary/octet-stream":
+	default:
+		return mt
+	}
+	if textExtensions[strings.ToLower(path.Ext(u.Path))] {
+		return "text/markdown"
+	}
+	mt, _, _ = mime.ParseMediaType(http.DetectContentType(body))
+	return mt
+}
+
+// utf8BOM is the byte order mark some editors put at the start of files.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// passThrough returns a markdown or text body as markdown.
+func passThrough(body []byte) string {
+	return string(bytes.TrimPrefix(body, utf8BOM))
+}
@@ -0,0 +1,84 @@
+package fetcher
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRawURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://github.com/nats-io/nats.go/blob/main/README.md", "https://raw.githubusercontent.com/nats-io/nats.go/main/README.md"},
+		{"https://github.com/nats-io/nats.go/blob/v1.37.0/docs/js.md?plain=1#L10", "https://raw.githubusercontent.com/nats-io/nats.go/v1.37.0/docs/js.md"},
+		{"https://www.github.com/o/r/raw/main/CHANGELOG", "https://raw.githubusercontent.com/o/r/main/CHANGELOG"},
+		{"https://gitlab.com/group/sub/project/-/blob/main/docs/index.md?ref_type=heads", "https://gitlab.com/group/sub/project/-/raw/main/docs/index.md"},
+		{"https://git.example.com/team/project/-/blob/main/README.md", "https://git.example.com/team/project/-/raw/main/README.md"},
+		{"https://codeberg.org/forgejo/forgejo/src/branch/forgejo/README.md", "https://codeberg.org/forgejo/forgejo/raw/branch/forgejo/README.md"},
+		{"https://gitea.com/gitea/tea/src/tag/v0.9.0/docs/CLI.md", "https://gitea.com/gitea/tea/raw/tag/v0.9.0/docs/CLI.md"},
+
+		// Not file pages
+		{"https://github.com/nats-io/nats.go", ""},
+		{"https://github.com/nats-io/nats.go/tree/main/docs", ""},
+		{"https://github.com/nats-io/nats.go/blob/main", ""},
+		{"https://example.com/o/r/blob/main/README.md", ""}, // GitHub layout only on github.com
+		{"https://gitlab.com/group/project/-/tree/main/docs", ""},
+		{"https://codeberg.org/forgejo/forgejo/src/branch/forgejo", ""},
+		{"https://docs.nats.io/src/branch/x/y", ""},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.in)
+		got := ""
+		if raw := rawURL(u); raw != nil {
+			got = raw.String()
+		}
+		if got != tt.want {
+			t.Errorf("rawURL(%s) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFetchContentTypes(t *testing.T) {
+	const md = "# Changelog\n\n* **v2** – <b>bold</b> claims\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/o/r/raw/branch/main/CHANGELOG.md": // A Gitea raw file
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprint(w, "\xef\xbb\xbf"+md)
+		case "/o/r/src/branch/main/CHANGELOG.md":
+			fmt.Fprint(w, "<html><body><nav>Repository chrome</nav><main>Rendered</main></body></html>")
+		case "/notes.md":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			fmt.Fprint(w, md)
+		case "/untyped":
+			w.Header()["Content-Type"] = nil // Don't let the server sniff
+			fmt.Fprint(w, "<!DOCTYPE html><html><body><p>Sniffed HTML.</p></body></html>")
+		case "/data.json":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"markdown": false}`)
+		}
+	}))
+	defer srv.Close()
+	f := NewHTTPFetcher()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/o/r/src/branch/main/CHANGELOG.md", md}, // Rewritten to the raw file, BOM dropped
+		{"/notes.md", md},
+		{"/untyped", "Sniffed HTML."},
+	}
+	for _, tt := range tests {
+		got, err := f.FetchAsMarkdown(srv.URL + tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("%s = %q, %v; want %q", tt.path, got, err, tt.want)
+		}
+	}
+	if _, err := f.FetchAsMarkdown(srv.URL + "/data.json"); KindOf(err) != KindUnsupported {
+		t.Errorf("json: %v, want an unsupported error", err)
+	}
+}
@@ -0,0 +1,158 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robotsAgent is the product token matched against robots.txt user agents.
+const robotsAgent = "mcp-md-index"
+
+// robotsRules are the robots.txt rules that apply to this crawler.
+type robotsRules struct {
+	rules []robotsRule
+	delay time.Duration // Crawl-delay, 0 if unset
+}
+
+// robotsRule is one Allow or Disallow line.
+type robotsRule struct {
+	allow   bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+// robots fetches and parses the robots.txt of u's host. A missing file (4xx)
+// allows everything; an unreachable one (5xx) is an error, as in RFC 9309.
+func (f *HTTPFetcher) robots(ctx context.Context, u *url.URL) (*robotsRules, error) {
+	robotsURL := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	resp, body, err := f.get(ctx, robotsURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch robots.txt: %w", err)
+	}
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("fetch robots.txt: %w", statusError(resp, 1))
+	case resp.StatusCode != http.StatusOK:
+		return &robotsRules{}, nil
+	}
+	return parseRobots(string(body), robotsAgent), nil
+}
+
+// parseRobots extracts the rules for agent: those of the groups naming it,
+// or else of the "*" groups.
+func parseRobots(body, agent string) *robotsRules {
+	type group struct {
+		agents []string
+		rules  []robotsRule
+		delay  time.Duration
+	}
+	var groups []*group
+	var cur *group
+	inAgents := false
+
+	for _, line := range strings.Split(body, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			// Consecutive user-agent lines share one group
+			if !inAgents {
+				cur = &group{}
+				groups = append(groups, cur)
+			}
+			cur.agents = append(cur.agents, strings.ToLower(value))
+			inAgents = true
+			continue
+		}
+		inAgents = false
+		if cur == nil {
+			continue
+		}
+		switch key {
+		case "allow", "disallow":
+			if value != "" { // An empty Disallow allows everything
+				cur.rules = append(cur.rules, robotsRule{allow: key == "allow", pattern: value, re: robotsPattern(value)})
+			}
+		case "crawl-delay":
+			if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+				cur.delay = time.Duration(secs * float64(time.Second))
+			}
+		}
+	}
+
+	agent = strings.ToLower(agent)
+	var specific, wildcard []*group
+	for _, g := range groups {
+		for _, a := range g.agents {
+			if a == "*" {
+				wildcard = append(wildcard, g)
+			} else if a != "" && strings.Contains(agent, a) {
+				specific = append(specific, g)
+			}
+		}
+	}
+	chosen := specific
+	if len(chosen) == 0 {
+		chosen = wildcard
+	}
+
+	r := &robotsRules{}
+	for _, g := range chosen {
+		r.rules = append(r.rules, g.rules...)
+		r.delay = max(r.delay, g.delay)
+	}
+	return r
+}
+
+// robotsPattern compiles a rule path: "*" matches any characters and a
+// trailing "$" anchors the end.
+func robotsPattern(p string) *regexp.Regexp {
+	anchored := strings.HasSuffix(p, "$")
+	p = strings.TrimSuffix(p, "$")
+	parts := strings.Split(p, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// allowed reports whether u may be fetched: the longest matching rule wins,
+// and Allow wins a tie.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	p := u.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	if u.RawQuery != "" {
+		p += "?" + u.RawQuery
+	}
+	if p == "/robots.txt" {
+		return true
+	}
+
+	allow, best := true, -1
+	for _, rule := range r.rules {
+		if !rule.re.MatchString(p) {
+			continue
+		}
+		if n := len(rule.pattern); n > best || (n == best && rule.allow) {
+			allow, best = rule.allow, n
+		}
+	}
+	return allow
+}
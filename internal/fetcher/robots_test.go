@@ -0,0 +1,51 @@
+package fetcher
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseRobots(t *testing.T) {
+	body := `# Example
+User-agent: *
+Disallow: /private/
+Crawl-delay: 5
+
+User-agent: Googlebot
+User-agent: mcp-md-index
+Disallow: /drafts/
+Allow: /drafts/public
+Disallow: /*.json$
+Crawl-delay: 0.5
+`
+	r := parseRobots(body, robotsAgent)
+	if r.delay != 500*time.Millisecond {
+		t.Errorf("delay = %s, want 500ms", r.delay)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/docs/intro", true},
+		{"/private/x", true}, // Only the * group disallows it
+		{"/drafts/secret", false},
+		{"/drafts/public/page", true}, // Longer Allow wins
+		{"/api/spec.json", false},
+		{"/api/spec.json?v=1", true}, // $ anchors the end
+		{"/robots.txt", true},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse("https://example.com" + tt.path)
+		if got := r.allowed(u); got != tt.want {
+			t.Errorf("allowed(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// Other crawlers fall back to the * group
+	other := parseRobots(body, "otherbot")
+	if u, _ := url.Parse("https://example.com/private/x"); other.allowed(u) || other.delay != 5*time.Second {
+		t.Errorf("* group not applied: %+v", other)
+	}
+}
@@ -0,0 +1,59 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
+)
+
+// CrawlResult summarizes crawling and indexing a site.
+type CrawlResult struct {
+	StartURL   string
+	Pages      []*SiteLoadResult
+	Failed     int           // Pages that could not be fetched or indexed
+	Errors     []string      // Error messages for failed pages
+	Disallowed int           // Links skipped because robots.txt disallows them
+	CrawlDelay time.Duration // Delay robots.txt asked for between requests
+}
+
+// CrawlSite crawls a site from startURL and indexes each page as its own
+// document. Every page is tagged with "crawl" metadata set to startURL, so
+// the crawl can be queried or listed as a group with the filter
+// "crawl:<startURL>". The fetcher must implement fetcher.Crawler.
+func (idx *Indexer) CrawlSite(ctx context.Context, startURL string, opts fetcher.CrawlOptions) (*CrawlResult, error) {
+	if startURL == "" {
+		return nil, errors.New("url is required")
+	}
+	crawler, ok := idx.fetcher.(fetcher.Crawler)
+	if !ok {
+		return nil, errors.New("site crawling not configured (fetcher cannot crawl)")
+	}
+
+	result := &CrawlResult{StartURL: startURL}
+	group := map[string]string{"crawl": startURL}
+	stats, err := crawler.Crawl(ctx, startURL, opts, func(page fetcher.Page) {
+		if page.Err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", page.URL, page.Err))
+			return
+		}
+		r, err := idx.indexSite(page.URL, page.Markdown, idx.fetchInfo(page.Validators, idx.siteTTL, group))
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", page.URL, err))
+			return
+		}
+		result.Pages = append(result.Pages, r)
+	})
+	if stats != nil {
+		result.Disallowed = stats.Disallowed
+		result.CrawlDelay = stats.CrawlDelay
+	}
+	if err != nil {
+		return nil, fmt.Errorf("crawl %s: %w", startURL, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
+	"github.com/bad33ndj3/mcp-md-index/internal/parser"
+	"github.com/bad33ndj3/mcp-md-index/internal/search"
+	"github.com/bad33ndj3/mcp-md-index/internal/testutil"
+)
+
+func TestCrawlSite_IndexesPagesAsGroup(t *testing.T) {
+	pages := map[string]string{
+		"/docs/":             `<h1>Docs</h1><p>Welcome.</p><a href="streams">Streams</a> <a href="private/keys">Keys</a>`,
+		"/docs/streams":      `<h1>Streams</h1><p>A stream stores messages on subjects.</p>`,
+		"/docs/private/keys": `<h1>Keys</h1>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nDisallow: /docs/private/\n")
+			return
+		}
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><body>%s</body></html>", body)
+	}))
+	defer srv.Close()
+
+	registry := parser.NewDefaultRegistry(parser.NewCommonMarkParser())
+	idx := New(testutil.NewMockCache(), registry, search.NewBM25Searcher(), OSFileReader{}, testutil.NewMockClock(time.Time{}), fetcher.NewHTTPFetcher())
+
+	start := srv.URL + "/docs/"
+	maxDepth := 2
+	result, err := idx.CrawlSite(context.Background(), start, fetcher.CrawlOptions{MaxDepth: &maxDepth})
+	if err != nil {
+		t.Fatalf("CrawlSite: %v", err)
+	}
+	if len(result.Pages) != 2 || result.Failed != 0 || result.Disallowed != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+
+	filters, _ := ParseFilters([]string{"crawl:" + start})
+	var urls []string
+	for _, doc := range idx.List(filters) {
+		urls = append(urls, strings.TrimPrefix(doc.SourceURL, srv.URL))
+	}
+	sort.Strings(urls)
+	if got := strings.Join(urls, " "); got != "/docs/ /docs/streams" {
+		t.Errorf("crawl group = %s", got)
+	}
+
+	out, err := idx.QueryAll("stream stores messages", 500, search.Options{}, filters)
+	if err != nil {
+		t.Fatalf("QueryAll: %v", err)
+	}
+	if !strings.Contains(out, "A stream stores messages on subjects.") {
+		t.Errorf("query output:\n%s", out)
+	}
+
+	// A forced reload keeps the page in the crawl group
+	if _, err := idx.LoadSite(srv.URL+"/docs/streams", SiteLoadOptions{Force: true}); err != nil {
+		t.Fatalf("LoadSite: %v", err)
+	}
+	if n := len(idx.List(filters)); n != 2 {
+		t.Errorf("crawl group has %d documents after a forced reload, want 2", n)
+	}
+}
+
+func TestCrawlSite_RequiresCrawler(t *testing.T) {
+	idx := New(testutil.NewMockCache(), parser.NewDefaultRegistry(parser.NewCommonMarkParser()), search.NewBM25Searcher(), OSFileReader{}, testutil.NewMockClock(time.Time{}), nil)
+	if _, err := idx.CrawlSite(context.Background(), "https://example.com/", fetcher.CrawlOptions{}); err == nil {
+		t.Error("expected an error without a crawling fetcher")
+	}
+}
@@ -0,0 +1,214 @@
+package indexer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/build"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+	"github.com/bad33ndj3/mcp-md-index/internal/parser"
+)
+
+// LoadGoResult summarizes indexing the Go packages under a directory.
+type LoadGoResult struct {
+	Packages []*GoPackageResult
+	Cached   int      // How many packages were already cached
+	Failed   int      // Packages that could not be parsed
+	Errors   []string // Error messages for failed packages
+}
+
+// GoPackageResult describes one indexed Go package.
+type GoPackageResult struct {
+	LoadResult
+	ImportPath string
+}
+
+// LoadGo indexes the documentation of every Go package under dir (a module
+// or any directory inside one) using go/doc. Each package is cached as its
+// own document whose Path is the package directory; chunk source links point
+// at the declaring .go file. No network access or build is needed.
+func (idx *Indexer) LoadGo(dir string) (*LoadGoResult, error) {
+	if dir == "" {
+		return nil, errors.New("dir is required")
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve dir: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", dir)
+	}
+
+	modRoot, modPath := idx.findModule(root)
+	result := &LoadGoResult{}
+
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if p != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+
+		pkg, err := build.Default.ImportDir(p, 0)
+		if err != nil {
+			var noGo *build.NoGoError
+			if !errors.As(err, &noGo) {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", p, err))
+			}
+			return nil
+		}
+		if len(pkg.GoFiles)+len(pkg.CgoFiles) == 0 {
+			return nil // Only tests
+		}
+
+		var files []string
+		for _, group := range [][]string{pkg.GoFiles, pkg.CgoFiles, pkg.TestGoFiles, pkg.XTestGoFiles} {
+			for _, f := range group {
+				files = append(files, filepath.Join(p, f))
+			}
+		}
+
+		pr, err := idx.loadGoPackage(p, goImportPath(modRoot, modPath, p), files)
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", p, err))
+			return nil
+		}
+		if pr.FromCache {
+			result.Cached++
+		}
+		result.Packages = append(result.Packages, pr)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", dir, err)
+	}
+
+	if len(result.Packages) == 0 && result.Failed == 0 {
+		return nil, fmt.Errorf("no Go packages found in %s", dir)
+	}
+	return result, nil
+}
+
+// loadGoPackage indexes one package, reusing the cache while its files are unchanged.
+func (idx *Indexer) loadGoPackage(dir, importPath string, files []string) (*GoPackageResult, error) {
+	docID := parser.DocIDForPath(dir)
+
+	sources := make(map[string]string, len(files))
+	h := sha256.New()
+	sort.Strings(files)
+	for _, f := range files {
+		content, err := idx.reader.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("read file: %w", err)
+		}
+		sources[f] = string(content)
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.Base(f), len(content))
+		h.Write(content)
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	cached := func(index *domain.Index) *GoPackageResult {
+		return &GoPackageResult{
+			LoadResult: LoadResult{
+				DocID:     index.DocID,
+				Path:      index.Path,
+				NumChunks: index.NumChunks,
+				FromCache: true,
+				IndexedAt: index.IndexedAt,
+			},
+			ImportPath: importPath,
+		}
+	}
+
+	// 1. Memory, then disk cache, while no file changed
+	if index, err := idx.cache.Get(docID); err == nil && index.FileHash == hash {
+		return cached(index), nil
+	}
+	if index, err := idx.cache.LoadFromDisk(docID); err == nil && index.Path == dir && index.FileHash == hash {
+		idx.cache.Set(docID, index)
+		idx.restoreEmbeddings(index)
+		return cached(index), nil
+	}
+
+	// 2. Extract the documentation
+	pkg, err := parser.ParseGoPackage(dir, importPath, sources)
+	if err != nil {
+		return nil, err
+	}
+	index := &domain.Index{
+		DocID:     docID,
+		Path:      dir,
+		FileHash:  hash,
+		IndexedAt: idx.clock.Now(),
+		Chunks:    pkg.Chunks,
+		DocFreq:   pkg.DocFreq,
+		NumChunks: len(pkg.Chunks),
+		Metadata: map[string]string{
+			"language":    "go",
+			"package":     pkg.Name,
+			"import_path": importPath,
+		},
+		Version: domain.CacheVersion,
+	}
+	idx.reuseEmbeddings(idx.previousIndex(docID), index)
+
+	// 3. Save to both memory and disk, then embed in background
+	idx.cache.Set(docID, index)
+	if err := idx.cache.SaveToDisk(index); err != nil {
+		return nil, fmt.Errorf("save cache: %w", err)
+	}
+	idx.scheduleEmbeddings(index)
+
+	return &GoPackageResult{
+		LoadResult: LoadResult{
+			DocID:     index.DocID,
+			Path:      index.Path,
+			NumChunks: index.NumChunks,
+			IndexedAt: index.IndexedAt,
+		},
+		ImportPath: importPath,
+	}, nil
+}
+
+// findModule walks up from dir to the nearest go.mod and returns its
+// directory and module path, or empty strings if there is none.
+func (idx *Indexer) findModule(dir string) (root, modPath string) {
+	for d := dir; ; d = filepath.Dir(d) {
+		if content, err := idx.reader.ReadFile(filepath.Join(d, "go.mod")); err == nil {
+			for _, line := range strings.Split(string(content), "\n") {
+				if f := strings.Fields(line); len(f) >= 2 && f[0] == "module" {
+					return d, strings.Trim(f[1], `"`)
+				}
+			}
+			return d, ""
+		}
+		if filepath.Dir(d) == d {
+			return "", ""
+		}
+	}
+}
+
+// goImportPath derives a package's import path from its module, falling back
+// to the directory name outside a module.
+func goImportPath(modRoot, modPath, dir string) string {
+	if modPath == "" {
+		return filepath.Base(dir)
+	}
+	rel, err := filepath.Rel(modRoot, dir)
+	if err != nil || rel == "." {
+		return modPath
+	}
+	return path.Join(modPath, filepath.ToSlash(rel))
+}
@@ -0,0 +1,63 @@
+package indexer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/testutil"
+)
+
+func TestLoadGo_IndexesPackagesOfModule(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/lib\n\ngo 1.22\n")
+	write("lib.go", "// Package lib does things.\npackage lib\n\n// Hello greets.\nfunc Hello() string { return \"hi\" }\n")
+	write("sub/sub.go", "package sub\n\n// Answer is 42.\nconst Answer = 42\n")
+	write("testdata/skip.go", "package skip\n")
+	write("onlytests/x_test.go", "package onlytests\n")
+
+	cache := testutil.NewMockCache()
+	indexer := New(cache, testutil.MockParser{}, testutil.MockSearcher{}, OSFileReader{}, testutil.NewMockClock(time.Time{}), nil)
+
+	result, err := indexer.LoadGo(root)
+	if err != nil {
+		t.Fatalf("LoadGo: %v", err)
+	}
+	if len(result.Packages) != 2 || result.Failed != 0 {
+		t.Fatalf("packages = %+v, failed = %d %v", result.Packages, result.Failed, result.Errors)
+	}
+
+	imports := map[string]bool{}
+	for _, p := range result.Packages {
+		imports[p.ImportPath] = true
+		index, err := cache.Get(p.DocID)
+		if err != nil {
+			t.Fatalf("package %s not cached", p.ImportPath)
+		}
+		if index.Metadata["import_path"] != p.ImportPath || index.Metadata["language"] != "go" {
+			t.Errorf("metadata = %v", index.Metadata)
+		}
+	}
+	if !imports["example.com/lib"] || !imports["example.com/lib/sub"] {
+		t.Errorf("import paths = %v", imports)
+	}
+
+	// Unchanged packages come from cache
+	again, err := indexer.LoadGo(root)
+	if err != nil {
+		t.Fatalf("second LoadGo: %v", err)
+	}
+	if again.Cached != 2 {
+		t.Errorf("Cached = %d, want 2", again.Cached)
+	}
+}
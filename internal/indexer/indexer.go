@@ -4,6 +4,8 @@
 package indexer
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
@@ -23,7 +25,9 @@ import (
 	"github.com/bad33ndj3/mcp-md-index/internal/embedding"
 	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
 	"github.com/bad33ndj3/mcp-md-index/internal/parser"
+	"github.com/bad33ndj3/mcp-md-index/internal/queue"
 	"github.com/bad33ndj3/mcp-md-index/internal/search"
+	"github.com/bad33ndj3/mcp-md-index/internal/vector"
 )
 
 // FileReader abstracts file system access for testability.
@@ -54,10 +58,18 @@ type Indexer struct {
 	fetcher  fetcher.Fetcher
 
 	// Embedding support (optional, experimental)
-	embedder    embedding.Embedder // nil if embeddings disabled
-	embedStatus *embedding.Status  // tracks per-doc embedding readiness
-	logger      *slog.Logger       // for async error logging
-	sem         chan struct{}      // concurrency limit for embeddings
+	embedder       embedding.Embedder // nil if embeddings disabled
+	embedStatus    *embedding.Status  // tracks per-doc embedding readiness
+	logger         *slog.Logger       // for async error logging
+	jobs           *queue.Queue       // background embedding jobs (nil if disabled)
+	jobConfig      queue.Config       // settings used to create jobs
+	embedBatchSize int                // chunks per EmbedBatch call
+	vectors        *vector.HNSW       // ANN index over all chunk embeddings (optional)
+	vectorSaveMu   sync.Mutex         // Guards vectorSave
+	vectorSave     *time.Timer        // Pending save of vectors (nil: saved)
+
+	manPath []string      // Directories searched by LoadMan (nil: $MANPATH or system default)
+	siteTTL time.Duration // How long site documents stay fresh (0: forever)
 }
 
 // Option configures the Indexer.
@@ -84,7 +96,30 @@ func WithMaxConcurrentEmbeddings(n int) Option {
 		n = 1
 	}
 	return func(idx *Indexer) {
-		idx.sem = make(chan struct{}, n)
+		idx.jobConfig.Workers = n
+	}
+}
+
+// WithEmbeddingBatchSize sets how many chunks are embedded per request.
+func WithEmbeddingBatchSize(n int) Option {
+	return func(idx *Indexer) {
+		idx.embedBatchSize = n
+	}
+}
+
+// WithVectorIndex keeps an approximate nearest-neighbour index in sync with
+// chunk embeddings, so cross-document semantic search is sub-linear.
+func WithVectorIndex(v *vector.HNSW) Option {
+	return func(idx *Indexer) {
+		idx.vectors = v
+	}
+}
+
+// WithEmbeddingJobFile persists the embedding job queue to path,
+// so pending and failed jobs survive restarts.
+func WithEmbeddingJobFile(path string) Option {
+	return func(idx *Indexer) {
+		idx.jobConfig.StatePath = path
 	}
 }
 
@@ -98,13 +133,33 @@ func New(c cache.Cache, p parser.Parser, s search.Searcher, r FileReader, clk Cl
 		reader:   r,
 		clock:    clk,
 		fetcher:  f,
+		siteTTL:  DefaultSiteTTL,
 	}
 	for _, opt := range opts {
 		opt(idx)
 	}
+
+	if idx.embedder != nil {
+		idx.startEmbeddingJobs()
+	}
 	return idx
 }
 
+// startEmbeddingJobs creates the embedding job queue and starts its workers.
+// A corrupt state file is logged and replaced by an empty queue.
+func (idx *Indexer) startEmbeddingJobs() {
+	jobs, err := queue.New(idx.jobConfig, idx.embedDocument, idx.logger)
+	if err != nil {
+		if idx.logger != nil {
+			idx.logger.Warn("discarding embedding job state", "error", err)
+		}
+		_ = os.Remove(idx.jobConfig.StatePath)
+		jobs, _ = queue.New(idx.jobConfig, idx.embedDocument, idx.logger)
+	}
+	idx.jobs = jobs
+	idx.jobs.Start(context.Background())
+}
+
 // LoadResult contains information about a loaded document.
 type LoadResult struct {
 	DocID     string
@@ -114,9 +169,18 @@ type LoadResult struct {
 	IndexedAt time.Time
 }
 
-// Load indexes a markdown file and caches it.
+// ErrUnsupportedFile is returned for files no parser handles (e.g. binaries).
+var ErrUnsupportedFile = errors.New("unsupported file type")
+
+// Load indexes a document and caches it.
 // If already cached and file hasn't changed, returns cached version.
 func (idx *Indexer) Load(path string) (*LoadResult, error) {
+	return idx.load(path, false)
+}
+
+// load indexes a document. In strict mode (LoadGlob) only files of a
+// recognized type are indexed; otherwise any text file is.
+func (idx *Indexer) load(path string, strict bool) (*LoadResult, error) {
 	if path == "" {
 		return nil, errors.New("path is required")
 	}
@@ -143,12 +207,22 @@ func (idx *Indexer) Load(path string) (*LoadResult, error) {
 	if err != nil {
 		return nil, fmt.Errorf("hash file: %w", err)
 	}
+	content, name, err := decompress(path, content)
+	if err != nil {
+		return nil, err
+	}
+	p, err := idx.parserFor(name, string(content), strict)
+	if err != nil {
+		return nil, err
+	}
 
 	// 3. Try disk cache (survives restarts)
+	var previous *domain.Index
 	if cached, err := idx.cache.LoadFromDisk(docID); err == nil {
 		// Validate: same path and file hasn't changed
 		if cached.Path == path && cached.FileHash == fileHash {
 			idx.cache.Set(docID, cached)
+			idx.restoreEmbeddings(cached)
 			return &LoadResult{
 				DocID:     cached.DocID,
 				Path:      cached.Path,
@@ -158,10 +232,11 @@ func (idx *Indexer) Load(path string) (*LoadResult, error) {
 			}, nil
 		}
 		// File changed, need to re-index
+		previous = cached
 	}
 
 	// 4. Parse and index the document
-	chunks, docFreq := idx.parser.Parse(path, string(content))
+	chunks, docFreq := p.Parse(path, string(content))
 	index := &domain.Index{
 		DocID:     docID,
 		Path:      path,
@@ -170,8 +245,10 @@ func (idx *Indexer) Load(path string) (*LoadResult, error) {
 		Chunks:    chunks,
 		DocFreq:   docFreq,
 		NumChunks: len(chunks),
+		Metadata:  documentMetadata(string(content), chunks),
 		Version:   domain.CacheVersion,
 	}
+	idx.reuseEmbeddings(previous, index)
 
 	// 5. Save to both memory and disk
 	idx.cache.Set(docID, index)
@@ -180,9 +257,7 @@ func (idx *Indexer) Load(path string) (*LoadResult, error) {
 	}
 
 	// 6. Generate embeddings in background (NON-BLOCKING)
-	if idx.embedder != nil {
-		go idx.generateEmbeddingsAsync(index)
-	}
+	idx.scheduleEmbeddings(index)
 
 	return &LoadResult{
 		DocID:     index.DocID,
@@ -193,10 +268,47 @@ func (idx *Indexer) Load(path string) (*LoadResult, error) {
 	}, nil
 }
 
+// parserFor picks the parser for a file. If the configured parser is a
+// parser.Selector (the format registry) it chooses by file type; in strict
+// mode files of unknown type are rejected instead of parsed as markdown.
+func (idx *Indexer) parserFor(path, content string, strict bool) (parser.Parser, error) {
+	sel, ok := idx.parser.(parser.Selector)
+	if !ok {
+		return idx.parser, nil
+	}
+	p := sel.Detect(path, content)
+	if p == nil && !strict {
+		p = sel.Fallback(content)
+	}
+	if p == nil {
+		return nil, ErrUnsupportedFile
+	}
+	return p, nil
+}
+
+// decompress gunzips .gz files (such as installed man pages) and returns the
+// content with the name to choose a parser by: the path without ".gz".
+func decompress(path string, content []byte) ([]byte, string, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".gz") {
+		return content, path, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, "", fmt.Errorf("decompress: %w", err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, "", fmt.Errorf("decompress: %w", err)
+	}
+	return out, path[:len(path)-len(".gz")], nil
+}
+
 // LoadGlobResult contains summary of bulk loading operation.
 type LoadGlobResult struct {
 	Loaded  int      // Number of files successfully loaded
 	Cached  int      // How many were already cached
+	Skipped int      // Files of unsupported type (e.g. images)
 	Failed  int      // How many failed to load
 	Errors  []string // Error messages for failed files
 	Results []*LoadResult
@@ -211,6 +323,8 @@ type loadJobResult struct {
 
 // LoadGlob loads all files matching a glob pattern.
 // Supports ** for recursive directory matching (e.g., "docs/**/*.md").
+// Files whose type no parser recognizes are skipped, so "docs/**/*" can
+// index a mixed tree of markdown, MDX, reStructuredText and AsciiDoc.
 // Uses parallel workers for improved performance on multi-core systems.
 func (idx *Indexer) LoadGlob(pattern string) (*LoadGlobResult, error) {
 	if pattern == "" {
@@ -257,7 +371,11 @@ func (idx *Indexer) LoadGlob(pattern string) (*LoadGlobResult, error) {
 	// For small file counts, load sequentially (avoid goroutine overhead)
 	if len(files) <= 2 {
 		for _, path := range files {
-			loadResult, err := idx.Load(path)
+			loadResult, err := idx.load(path, true)
+			if errors.Is(err, ErrUnsupportedFile) {
+				result.Skipped++
+				continue
+			}
 			if err != nil {
 				result.Failed++
 				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", path, err))
@@ -284,7 +402,7 @@ func (idx *Indexer) LoadGlob(pattern string) (*LoadGlobResult, error) {
 		go func() {
 			defer wg.Done()
 			for path := range jobs {
-				loadResult, err := idx.Load(path)
+				loadResult, err := idx.load(path, true)
 				results <- loadJobResult{path: path, result: loadResult, err: err}
 			}
 		}()
@@ -306,6 +424,10 @@ func (idx *Indexer) LoadGlob(pattern string) (*LoadGlobResult, error) {
 
 	// Collect results
 	for r := range results {
+		if errors.Is(r.err, ErrUnsupportedFile) {
+			result.Skipped++
+			continue
+		}
 		if r.err != nil {
 			result.Failed++
 			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", r.path, r.err))
@@ -359,7 +481,13 @@ func findFilesRecursive(pattern string) []string {
 }
 
 // Query searches an indexed document and returns token-bounded excerpts.
-func (idx *Indexer) Query(docID, path, prompt string, maxTokens int) (string, error) {
+// opts overrides retrieval mode and fusion if the searcher supports it.
+// If the document's metadata doesn't match filters, nothing is searched.
+func (idx *Indexer) Query(docID, path, prompt string, maxTokens int, opts search.Options, filters []Filter) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+
 	// Resolve docID from path if not provided
 	if docID == "" {
 		if path == "" {
@@ -387,27 +515,57 @@ func (idx *Indexer) Query(docID, path, prompt string, maxTokens int) (string, er
 
 		// Warm up memory cache
 		idx.cache.Set(docID, index)
+		idx.restoreEmbeddings(index)
 	}
 
 	if prompt == "" {
 		return "", errors.New("prompt is required")
 	}
 
+	if !matchesFilters(index, filters) {
+		return "Document metadata does not match the filters.", nil
+	}
+
+	if s, ok := idx.searcher.(search.OptionSearcher); ok {
+		return s.SearchWithOptions(index, prompt, maxTokens, opts), nil
+	}
 	return idx.searcher.Search(index, prompt, maxTokens), nil
 }
 
-// QueryAll searches all cached documents and returns combined results.
-// Results are merged by BM25 score across all documents.
-func (idx *Indexer) QueryAll(prompt string, maxTokens int) (string, error) {
+// QueryAll searches all cached documents matching filters and returns
+// combined results. A search.MultiSearcher ranks all chunks together;
+// otherwise each document is searched in turn until the token budget is
+// used up.
+func (idx *Indexer) QueryAll(prompt string, maxTokens int, opts search.Options, filters []Filter) (string, error) {
 	if prompt == "" {
 		return "", errors.New("prompt is required")
 	}
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
 
 	docIDs := idx.cache.List()
 	if len(docIDs) == 0 {
 		return "", errors.New("no documents loaded (use docs_load or site_load first)")
 	}
 
+	// Searchers that rank across documents get everything in one call
+	if multi, ok := idx.searcher.(search.MultiSearcher); ok {
+		indexes := make([]*domain.Index, 0, len(docIDs))
+		for _, docID := range docIDs {
+			if index, err := idx.cache.Get(docID); err == nil && matchesFilters(index, filters) {
+				indexes = append(indexes, index)
+			}
+		}
+		if len(indexes) == 0 {
+			return "No loaded documents match the filters.", nil
+		}
+		if s, ok := multi.(search.MultiOptionSearcher); ok {
+			return s.SearchAllWithOptions(indexes, prompt, maxTokens, opts), nil
+		}
+		return multi.SearchAll(indexes, prompt, maxTokens), nil
+	}
+
 	// Collect results from all documents
 	var results []string
 	tokensUsed := 0
@@ -417,6 +575,9 @@ func (idx *Indexer) QueryAll(prompt string, maxTokens int) (string, error) {
 		if err != nil {
 			continue // Skip if not in memory
 		}
+		if !matchesFilters(index, filters) {
+			continue
+		}
 
 		// Get per-document results with remaining token budget
 		remaining := maxTokens - tokensUsed
@@ -424,7 +585,12 @@ func (idx *Indexer) QueryAll(prompt string, maxTokens int) (string, error) {
 			break
 		}
 
-		excerpt := idx.searcher.Search(index, prompt, remaining)
+		var excerpt string
+		if s, ok := idx.searcher.(search.OptionSearcher); ok {
+			excerpt = s.SearchWithOptions(index, prompt, remaining, opts)
+		} else {
+			excerpt = idx.searcher.Search(index, prompt, remaining)
+		}
 		if excerpt != "" && !strings.Contains(excerpt, "No relevant excerpts") {
 			results = append(results, excerpt)
 			// Rough token estimate: ~4 chars per token
@@ -441,11 +607,12 @@ func (idx *Indexer) QueryAll(prompt string, maxTokens int) (string, error) {
 
 // SiteLoadResult contains information about a loaded site.
 type SiteLoadResult struct {
-	DocID     string
-	URL       string
-	NumChunks int
-	FromCache bool
-	IndexedAt time.Time
+	DocID       string
+	URL         string
+	NumChunks   int
+	FromCache   bool
+	Revalidated bool // A stale cached copy was confirmed unchanged with the server
+	IndexedAt   time.Time
 }
 
 // docIDForURL generates a unique document ID from a URL.
@@ -455,8 +622,53 @@ func docIDForURL(urlStr string) string {
 }
 
 // LoadSite fetches a URL, converts HTML to markdown, and caches it.
-// If already cached and force is false, returns the cached version.
-func (idx *Indexer) LoadSite(urlStr string, force bool) (*SiteLoadResult, error) {
+// A cached version is returned while it is fresh (see WithSiteTTL); once
+// stale, it is revalidated with a conditional GET and re-indexed only if the
+// content changed. opts.Force always re-fetches.
+func (idx *Indexer) LoadSite(urlStr string, opts SiteLoadOptions) (*SiteLoadResult, error) {
+	return idx.loadSite(context.Background(), urlStr, opts)
+}
+
+// maxSiteWorkers bounds how many sites LoadSites loads at once; the fetcher
+// also limits the requests made to each host.
+const maxSiteWorkers = 8
+
+// SiteLoadOutcome is the outcome of loading one site with LoadSites.
+type SiteLoadOutcome struct {
+	URL    string
+	Result *SiteLoadResult
+	Err    error
+}
+
+// LoadSites loads several sites concurrently, like LoadSite. Outcomes are in
+// the order of urls; repeated URLs are loaded once.
+func (idx *Indexer) LoadSites(ctx context.Context, urls []string, opts SiteLoadOptions) []SiteLoadOutcome {
+	var outcomes []SiteLoadOutcome
+	seen := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		if !seen[u] {
+			seen[u] = true
+			outcomes = append(outcomes, SiteLoadOutcome{URL: u})
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxSiteWorkers)
+	for i := range outcomes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(o *SiteLoadOutcome) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			o.Result, o.Err = idx.loadSite(ctx, o.URL, opts)
+		}(&outcomes[i])
+	}
+	wg.Wait()
+	return outcomes
+}
+
+// loadSite implements LoadSite.
+func (idx *Indexer) loadSite(ctx context.Context, urlStr string, opts SiteLoadOptions) (*SiteLoadResult, error) {
 	if urlStr == "" {
 		return nil, errors.New("url is required")
 	}
@@ -465,56 +677,83 @@ func (idx *Indexer) LoadSite(urlStr string, force bool) (*SiteLoadResult, error)
 		return nil, errors.New("site loading not configured (no fetcher)")
 	}
 
-	docID := docIDForURL(urlStr)
-
-	// Skip cache if force refresh requested
-	if !force {
-		// 1. Check in-memory cache first (fastest path)
-		if cached, err := idx.cache.Get(docID); err == nil {
-			return &SiteLoadResult{
-				DocID:     cached.DocID,
-				URL:       cached.Path, // We store URL in Path field
-				NumChunks: cached.NumChunks,
-				FromCache: true,
-				IndexedAt: cached.IndexedAt,
-			}, nil
+	// 1. Return the cached version while fresh, revalidate it once stale
+	cached, ok := idx.cachedSite(urlStr)
+	if ok && !opts.Force {
+		if idx.stale(cached) {
+			return idx.revalidate(ctx, cached, opts.TTL)
 		}
-
-		// 2. Try disk cache (survives restarts)
-		if cached, err := idx.cache.LoadFromDisk(docID); err == nil {
-			// Validate: same URL
-			if cached.Path == urlStr {
-				idx.cache.Set(docID, cached)
-				return &SiteLoadResult{
-					DocID:     cached.DocID,
-					URL:       cached.Path,
-					NumChunks: cached.NumChunks,
-					FromCache: true,
-					IndexedAt: cached.IndexedAt,
-				}, nil
+		if opts.TTL != 0 {
+			if err := idx.setSiteTTL(cached, opts.TTL); err != nil {
+				return nil, err
 			}
-			// URL changed (hash collision unlikely, but possible)
 		}
+		return cachedSiteResult(cached), nil
 	}
 
-	// 3. Fetch and convert to markdown
-	markdown, err := idx.fetcher.FetchAsMarkdown(urlStr)
+	// 2. Fetch and convert to markdown
+	resp, err := idx.fetchSite(ctx, urlStr, fetcher.Validators{})
 	if err != nil {
 		return nil, fmt.Errorf("fetch site: %w", err)
 	}
 
-	// 4. Save markdown to a local file for source links
+	// 3. Parse, index and cache it
+	// A forced reload keeps the page's crawl or list metadata
+	var prev *domain.FetchInfo
+	var extra map[string]string
+	if ok {
+		prev = idx.siteFetch(cached)
+		extra = prev.Metadata
+	}
+	return idx.indexSite(urlStr, resp.Markdown, idx.fetchInfo(resp.Validators, idx.siteTTLFor(opts.TTL, prev), extra))
+}
+
+// cachedSite returns the cached index of a page, checking the in-memory cache
+// first and then the disk cache (which survives restarts).
+func (idx *Indexer) cachedSite(urlStr string) (*domain.Index, bool) {
+	docID := docIDForURL(urlStr)
+	if cached, err := idx.cache.Get(docID); err == nil {
+		return cached, true
+	}
+	// Validate: same URL (hash collision unlikely, but possible); entries
+	// from before SourceURL existed keep the URL in Path
+	if cached, err := idx.cache.LoadFromDisk(docID); err == nil && (cached.SourceURL == urlStr || cached.Path == urlStr) {
+		idx.cache.Set(docID, cached)
+		idx.restoreEmbeddings(cached)
+		return cached, true
+	}
+	return nil, false
+}
+
+// indexSite indexes a fetched page's markdown under the page URL. The
+// fetch's metadata (e.g. the crawl the page belongs to) is added to its
+// front matter.
+func (idx *Indexer) indexSite(urlStr, markdown string, fetch *domain.FetchInfo) (*SiteLoadResult, error) {
+	docID := docIDForURL(urlStr)
+
+	// 1. Save markdown to a local file for source links
 	localPath, err := idx.cache.SaveMarkdown(docID, markdown)
 	if err != nil {
 		return nil, fmt.Errorf("save markdown: %w", err)
 	}
 
-	// 5. Hash the content for change detection
+	// 2. Hash the content for change detection
 	contentHash := sha256.Sum256([]byte(markdown))
 	fileHash := hex.EncodeToString(contentHash[:])
 
-	// 6. Parse and index using the LOCAL path (so source links work)
+	// 3. Parse and index using the LOCAL path (so source links work)
 	chunks, docFreq := idx.parser.Parse(localPath, markdown)
+	metadata := documentMetadata(markdown, chunks)
+	if extra := fetch.Metadata; len(extra) > 0 {
+		merged := make(map[string]string, len(metadata)+len(extra))
+		for k, v := range metadata {
+			merged[k] = v
+		}
+		for k, v := range extra {
+			merged[k] = v
+		}
+		metadata = merged
+	}
 	index := &domain.Index{
 		DocID:     docID,
 		Path:      localPath, // Use local path so source links are openable
@@ -524,15 +763,21 @@ func (idx *Indexer) LoadSite(urlStr string, force bool) (*SiteLoadResult, error)
 		Chunks:    chunks,
 		DocFreq:   docFreq,
 		NumChunks: len(chunks),
+		Metadata:  metadata,
 		Version:   domain.CacheVersion,
+		Fetch:     fetch,
 	}
+	idx.reuseEmbeddings(idx.previousIndex(docID), index)
 
-	// 7. Save to both memory and disk
+	// 4. Save to both memory and disk
 	idx.cache.Set(docID, index)
 	if err := idx.cache.SaveToDisk(index); err != nil {
 		return nil, fmt.Errorf("save cache: %w", err)
 	}
 
+	// 5. Generate embeddings in background (NON-BLOCKING)
+	idx.scheduleEmbeddings(index)
+
 	return &SiteLoadResult{
 		DocID:     index.DocID,
 		URL:       urlStr,
@@ -549,27 +794,72 @@ type DocInfo struct {
 	SourceURL string // Original URL for site_load entries (empty for local files)
 	NumChunks int
 	IndexedAt time.Time
+
+	// Embeddings summarizes vector state, e.g. "ready (ollama/nomic-embed-text, 768d)"
+	// or "outdated: model changed (a → b)". Empty when embeddings are disabled.
+	Embeddings string
+
+	// Metadata is the document's front matter (nil if it has none)
+	Metadata map[string]string
+
+	// Fetch describes how a site document was fetched (nil for local files);
+	// Stale is set once its TTL has passed
+	Fetch *domain.FetchInfo
+	Stale bool
 }
 
-// List returns information about all documents currently in memory cache.
-func (idx *Indexer) List() []DocInfo {
+// List returns information about documents in memory cache whose metadata
+// matches filters (all documents if filters is empty).
+func (idx *Indexer) List(filters []Filter) []DocInfo {
 	docIDs := idx.cache.List()
 	docs := make([]DocInfo, 0, len(docIDs))
 
 	for _, docID := range docIDs {
-		if index, err := idx.cache.Get(docID); err == nil {
+		if index, err := idx.cache.Get(docID); err == nil && matchesFilters(index, filters) {
+			var fetch *domain.FetchInfo
+			if index.SourceURL != "" {
+				fetch = idx.siteFetch(index)
+			}
 			docs = append(docs, DocInfo{
-				DocID:     index.DocID,
-				Path:      index.Path,
-				SourceURL: index.SourceURL,
-				NumChunks: index.NumChunks,
-				IndexedAt: index.IndexedAt,
+				DocID:      index.DocID,
+				Path:       index.Path,
+				SourceURL:  index.SourceURL,
+				NumChunks:  index.NumChunks,
+				IndexedAt:  index.IndexedAt,
+				Embeddings: idx.embeddingSummary(index),
+				Metadata:   index.Metadata,
+				Fetch:      fetch,
+				Stale:      idx.stale(index),
 			})
 		}
 	}
 	return docs
 }
 
+// embeddingSummary describes a document's embeddings for List.
+func (idx *Indexer) embeddingSummary(index *domain.Index) string {
+	if idx.jobs == nil {
+		return ""
+	}
+
+	if reason := embeddingMismatch(index.EmbeddingState, idx.currentEmbedding()); reason == "" {
+		s := index.EmbeddingState
+		return fmt.Sprintf("ready (%s/%s, %dd)", s.Provider, s.Model, s.Dimensions)
+	} else if reason != "none" && reason != "incomplete" {
+		// Set until the re-embed job discards the old vectors
+		return "outdated: " + reason
+	}
+
+	job, ok := idx.jobs.Get(index.DocID)
+	if !ok {
+		return "none"
+	}
+	if job.Total > 0 {
+		return fmt.Sprintf("%s (%d/%d chunks)", job.State, job.Done, job.Total)
+	}
+	return string(job.State)
+}
+
 // OSFileReader is the production implementation using the real filesystem.
 type OSFileReader struct{}
 
@@ -600,58 +890,383 @@ func (RealClock) Now() time.Time {
 	return time.Now()
 }
 
-// generateEmbeddingsAsync generates embeddings in background and updates cache.
-// This runs as a goroutine to keep Load() non-blocking.
-func (idx *Indexer) generateEmbeddingsAsync(index *domain.Index) {
-	// Respect concurrency limit if set
-	if idx.sem != nil {
-		idx.sem <- struct{}{}
-		defer func() { <-idx.sem }()
+// DefaultEmbeddingBatchSize is how many chunks are sent per EmbedBatch call.
+const DefaultEmbeddingBatchSize = 32
+
+// scheduleEmbeddings queues background embedding for a freshly indexed document.
+func (idx *Indexer) scheduleEmbeddings(index *domain.Index) {
+	if idx.jobs == nil {
+		return
 	}
+	if idx.embedStatus != nil {
+		idx.embedStatus.Clear(index.DocID)
+	}
+	if idx.vectors != nil {
+		idx.vectors.RemoveDoc(index.DocID) // Stale until re-embedded
+	}
+	idx.jobs.Enqueue(index.DocID, displayPath(index))
+}
 
-	ctx := context.Background()
+// vectorSaveDelay is how long vector index changes wait to be saved, so a
+// batch of documents rewrites the index file once instead of once per document.
+const vectorSaveDelay = 5 * time.Second
 
-	// Collect chunk texts with additional semantic context (headings)
-	texts := make([]string, len(index.Chunks))
-	for i, c := range index.Chunks {
-		texts[i] = idx.prepareTextForEmbedding(c)
+// indexVectors replaces a document's vectors in the ANN index and schedules
+// saving it.
+func (idx *Indexer) indexVectors(index *domain.Index) {
+	if idx.vectors == nil {
+		return
 	}
 
-	// Generate embeddings (this is the slow part)
-	embeddings, err := idx.embedder.EmbedBatch(ctx, texts)
-	if err != nil {
-		// Log error but don't fail - BM25 still works
+	if err := idx.cache.LoadVectors(index); err != nil {
 		if idx.logger != nil {
-			idx.logger.Warn("failed to generate embeddings",
-				"doc_id", index.DocID,
-				"error", err)
+			idx.logger.Warn("failed to load vectors", "doc_id", index.DocID, "error", err)
+		}
+		return
+	}
+
+	idx.vectors.RemoveDoc(index.DocID)
+	for _, c := range index.Chunks {
+		vec := c.Embedding
+		if len(vec) == 0 && len(c.Quantized) > 0 {
+			vec = cache.Dequantize(c.Quantized, c.QuantScale)
 		}
+		if len(vec) == 0 {
+			continue
+		}
+		err := idx.vectors.Add(c.ChunkID, index.DocID, vec)
+		if errors.Is(err, vector.ErrDimensionMismatch) {
+			// Vectors from an older model: start the index over
+			idx.vectors.Reset()
+			err = idx.vectors.Add(c.ChunkID, index.DocID, vec)
+		}
+		if err != nil {
+			if idx.logger != nil {
+				idx.logger.Warn("failed to add vector", "chunk_id", c.ChunkID, "error", err)
+			}
+			return
+		}
+	}
+
+	idx.scheduleVectorSave()
+}
+
+// scheduleVectorSave saves the vector index vectorSaveDelay from now, unless
+// a save is already pending. Close saves any pending changes.
+func (idx *Indexer) scheduleVectorSave() {
+	idx.vectorSaveMu.Lock()
+	defer idx.vectorSaveMu.Unlock()
+	if idx.vectorSave == nil {
+		idx.vectorSave = time.AfterFunc(vectorSaveDelay, idx.flushVectors)
+	}
+}
+
+// flushVectors saves the vector index if a save is pending.
+func (idx *Indexer) flushVectors() {
+	idx.vectorSaveMu.Lock()
+	pending := idx.vectorSave != nil
+	if pending {
+		idx.vectorSave.Stop()
+		idx.vectorSave = nil
+	}
+	idx.vectorSaveMu.Unlock()
+	if !pending {
 		return
 	}
+	if err := idx.vectors.Save(); err != nil && idx.logger != nil {
+		idx.logger.Warn("failed to save vector index", "error", err)
+	}
+}
+
+// embedDocument is the queue.Processor for embedding jobs.
+// It embeds chunks in bounded batches and saves progress after each batch,
+// so a restart or retry resumes where the previous attempt stopped.
+func (idx *Indexer) embedDocument(ctx context.Context, docID string, progress queue.ProgressFunc) error {
+	index, err := idx.cache.Get(docID)
+	if err != nil {
+		// Not in memory (e.g. resumed after restart): work on the disk copy
+		index, err = idx.cache.LoadFromDisk(docID)
+		if err != nil {
+			return queue.Permanent(fmt.Errorf("load index: %w", err))
+		}
+	}
 
-	// Update chunks with embeddings
-	if len(embeddings) == len(index.Chunks) {
+	// Resume from vectors saved by an earlier attempt
+	if err := idx.cache.LoadVectors(index); err != nil && idx.logger != nil {
+		idx.logger.Warn("failed to load saved vectors", "doc_id", docID, "error", err)
+	}
+
+	// Drop vectors from a different embedder; keep partial progress otherwise
+	want := idx.currentEmbedding()
+	if reason := embeddingMismatch(index.EmbeddingState, want); reason != "" && reason != "incomplete" {
+		if idx.logger != nil && index.EmbeddingState != nil {
+			idx.logger.Info("discarding outdated embeddings", "doc_id", docID, "reason", reason)
+		}
 		for i := range index.Chunks {
-			index.Chunks[i].Embedding = embeddings[i]
+			index.Chunks[i].Embedding = nil
+			index.Chunks[i].Quantized = nil
 		}
+		index.EmbeddingState = &want
+	}
+	state := index.EmbeddingState
 
-		// Update caches
-		idx.cache.Set(index.DocID, index)
-		_ = idx.cache.SaveToDisk(index) // Best-effort, already saved without embeddings
+	// Collect chunks that still need a vector
+	var todo []int
+	for i, c := range index.Chunks {
+		n := max(len(c.Embedding), len(c.Quantized))
+		if n == 0 || (state.Dimensions > 0 && n != state.Dimensions) {
+			todo = append(todo, i)
+		}
+	}
+	total := len(index.Chunks)
+	done := total - len(todo)
+	progress(done, total)
 
-		// Mark as ready for hybrid search
-		if idx.embedStatus != nil {
-			idx.embedStatus.SetReady(index.DocID)
+	batchSize := idx.embedBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultEmbeddingBatchSize
+	}
+
+	for start := 0; start < len(todo); start += batchSize {
+		batch := todo[start:min(start+batchSize, len(todo))]
+
+		// Collect chunk texts with additional semantic context (headings)
+		texts := make([]string, len(batch))
+		for i, ci := range batch {
+			texts[i] = idx.prepareTextForEmbedding(index.Chunks[ci])
 		}
 
-		if idx.logger != nil {
-			idx.logger.Debug("embeddings generated",
-				"doc_id", index.DocID,
-				"chunks", len(index.Chunks))
+		embeddings, err := idx.embedder.EmbedBatch(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("embed batch: %w", err)
+		}
+		if len(embeddings) != len(batch) {
+			return fmt.Errorf("embedder returned %d vectors for %d chunks", len(embeddings), len(batch))
+		}
+
+		if !idx.isCurrent(index) {
+			return queue.Permanent(errors.New("document was re-indexed while embedding"))
+		}
+		for i, ci := range batch {
+			index.Chunks[ci].Embedding = embeddings[i]
+		}
+		if n := embeddingDimensions(embeddings); state.Dimensions == 0 {
+			state.Dimensions = n
+		} else if n != state.Dimensions {
+			return queue.Permanent(fmt.Errorf("embedder returned %d dimensions, expected %d", n, state.Dimensions))
+		}
+
+		done += len(batch)
+		state.Complete = done == total
+		if err := idx.cache.SaveToDisk(index); err != nil && idx.logger != nil {
+			idx.logger.Warn("failed to save embedding progress", "doc_id", docID, "error", err)
+		}
+		progress(done, total)
+	}
+
+	// Covers documents with no chunks, where the loop never runs
+	state.Complete = true
+	if len(todo) == 0 {
+		_ = idx.cache.SaveToDisk(index) // Best-effort
+	}
+
+	// Mark as ready for hybrid search
+	idx.indexVectors(index)
+	if idx.embedStatus != nil {
+		idx.embedStatus.SetReady(docID)
+	}
+
+	if idx.logger != nil {
+		idx.logger.Debug("embeddings generated",
+			"doc_id", docID,
+			"chunks", total)
+	}
+	return nil
+}
+
+// previousIndex returns the existing index for docID from memory or disk, if any.
+func (idx *Indexer) previousIndex(docID string) *domain.Index {
+	if index, err := idx.cache.Get(docID); err == nil {
+		return index
+	}
+	if index, err := idx.cache.LoadFromDisk(docID); err == nil {
+		return index
+	}
+	return nil
+}
+
+// reuseEmbeddings copies vectors from a previous version of a document into a
+// freshly parsed one, for every chunk whose embedding text is unchanged.
+// Only vectors from the current embedder are reused; the embedding job then
+// only has to embed the chunks that actually changed.
+func (idx *Indexer) reuseEmbeddings(previous, index *domain.Index) {
+	if idx.jobs == nil || previous == nil || previous.EmbeddingState == nil {
+		return
+	}
+	if reason := embeddingMismatch(previous.EmbeddingState, idx.currentEmbedding()); reason != "" && reason != "incomplete" {
+		return
+	}
+	if err := idx.cache.LoadVectors(previous); err != nil {
+		return // Nothing to reuse; the job embeds everything
+	}
+
+	byText := make(map[[sha256.Size]byte]domain.Chunk, len(previous.Chunks))
+	for _, c := range previous.Chunks {
+		if len(c.Embedding) > 0 || len(c.Quantized) > 0 {
+			byText[sha256.Sum256([]byte(idx.prepareTextForEmbedding(c)))] = c
+		}
+	}
+	if len(byText) == 0 {
+		return
+	}
+
+	reused := 0
+	for i := range index.Chunks {
+		old, ok := byText[sha256.Sum256([]byte(idx.prepareTextForEmbedding(index.Chunks[i])))]
+		if !ok {
+			continue
+		}
+		index.Chunks[i].Embedding = old.Embedding
+		index.Chunks[i].Quantized = old.Quantized
+		index.Chunks[i].QuantScale = old.QuantScale
+		reused++
+	}
+	if reused == 0 {
+		return
+	}
+
+	state := *previous.EmbeddingState
+	state.Complete = false // Set by the embedding job once the rest is done
+	index.EmbeddingState = &state
+
+	if idx.logger != nil {
+		idx.logger.Debug("reused embeddings from previous version",
+			"doc_id", index.DocID,
+			"reused", reused,
+			"chunks", len(index.Chunks))
+	}
+}
+
+// isCurrent reports whether index is still the version held in memory.
+// A doc that is only on disk counts as current.
+func (idx *Indexer) isCurrent(index *domain.Index) bool {
+	inMem, err := idx.cache.Get(index.DocID)
+	return err != nil || inMem == index
+}
+
+// displayPath returns the URL for site documents and the file path otherwise.
+func displayPath(index *domain.Index) string {
+	if index.SourceURL != "" {
+		return index.SourceURL
+	}
+	return index.Path
+}
+
+// EmbeddingsEnabled reports whether background embedding is configured.
+func (idx *Indexer) EmbeddingsEnabled() bool {
+	return idx.jobs != nil
+}
+
+// EmbeddingJobs returns the state of every embedding job.
+func (idx *Indexer) EmbeddingJobs() []queue.Job {
+	if idx.jobs == nil {
+		return nil
+	}
+	return idx.jobs.Snapshot()
+}
+
+// RetryFailedEmbeddings re-queues failed embedding jobs and returns how many.
+func (idx *Indexer) RetryFailedEmbeddings() int {
+	if idx.jobs == nil {
+		return 0
+	}
+	return idx.jobs.RetryFailed()
+}
+
+// Close stops background embedding workers and saves the vector index.
+func (idx *Indexer) Close() {
+	if idx.jobs != nil {
+		idx.jobs.Close()
+	}
+	idx.flushVectors()
+}
+
+// restoreEmbeddings handles an index loaded from the disk cache.
+// If its stored vectors are complete and came from the current embedder, the doc
+// is marked ready for hybrid search right away; otherwise embedding is re-queued.
+func (idx *Indexer) restoreEmbeddings(index *domain.Index) {
+	if idx.jobs == nil || idx.embedStatus == nil {
+		return
+	}
+
+	reason := embeddingMismatch(index.EmbeddingState, idx.currentEmbedding())
+	if reason == "" {
+		if idx.vectors != nil && !idx.vectors.HasDoc(index.DocID) {
+			idx.indexVectors(index)
 		}
+		idx.embedStatus.SetReady(index.DocID)
+		idx.jobs.MarkReady(index.DocID, displayPath(index), len(index.Chunks))
+		return
 	}
+
+	if idx.logger != nil {
+		idx.logger.Debug("re-queueing embeddings for cached doc", "doc_id", index.DocID, "reason", reason)
+	}
+	idx.scheduleEmbeddings(index)
+}
+
+// currentEmbedding describes the vectors the configured embedder produces.
+// Dimensions is 0 when the embedder only learns it from its first response.
+func (idx *Indexer) currentEmbedding() domain.EmbeddingState {
+	return domain.EmbeddingState{
+		Provider:    idx.embedder.Provider(),
+		Model:       idx.embedder.Model(),
+		Dimensions:  idx.embedder.Dimensions(),
+		TextVersion: EmbeddingTextVersion,
+	}
+}
+
+// embeddingMismatch explains why stored embeddings can't be used with want,
+// or returns "" if they can. Only the recorded state is checked: vectors
+// themselves are loaded lazily.
+func embeddingMismatch(stored *domain.EmbeddingState, want domain.EmbeddingState) string {
+	switch {
+	case stored == nil:
+		return "none"
+	case stored.Provider != want.Provider:
+		return fmt.Sprintf("provider changed (%s → %s)", orUnknown(stored.Provider), want.Provider)
+	case stored.Model != want.Model:
+		return fmt.Sprintf("model changed (%s → %s)", orUnknown(stored.Model), want.Model)
+	case stored.TextVersion != want.TextVersion:
+		return fmt.Sprintf("text version changed (%d → %d)", stored.TextVersion, want.TextVersion)
+	case want.Dimensions > 0 && stored.Dimensions > 0 && stored.Dimensions != want.Dimensions:
+		return fmt.Sprintf("dimensions changed (%d → %d)", stored.Dimensions, want.Dimensions)
+	case !stored.Complete || stored.Dimensions == 0:
+		return "incomplete"
+	}
+	return ""
 }
 
+// orUnknown substitutes a placeholder for empty identity fields of old caches.
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+// embeddingDimensions returns the vector length of a batch (0 if empty).
+func embeddingDimensions(embeddings [][]float32) int {
+	if len(embeddings) == 0 {
+		return 0
+	}
+	return len(embeddings[0])
+}
+
+// EmbeddingTextVersion identifies the output format of prepareTextForEmbedding.
+// Bump it whenever that function changes so cached vectors are regenerated.
+const EmbeddingTextVersion = 1
+
 // prepareTextForEmbedding prepends heading path to chunk text for better semantic context.
 func (idx *Indexer) prepareTextForEmbedding(chunk domain.Chunk) string {
 	var sb strings.Builder
@@ -1,11 +1,26 @@
 package indexer
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+	"github.com/bad33ndj3/mcp-md-index/internal/embedding"
+	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
+	"github.com/bad33ndj3/mcp-md-index/internal/parser"
+	"github.com/bad33ndj3/mcp-md-index/internal/queue"
+	"github.com/bad33ndj3/mcp-md-index/internal/search"
 	"github.com/bad33ndj3/mcp-md-index/internal/testutil"
+	"github.com/bad33ndj3/mcp-md-index/internal/vector"
 )
 
 // --- Tests ---
@@ -113,7 +128,7 @@ func TestQuery_ReturnsExcerpts(t *testing.T) {
 	}
 
 	// Query
-	result, err := indexer.Query("", "docs/test.md", "test query", 500)
+	result, err := indexer.Query("", "docs/test.md", "test query", 500, search.Options{}, nil)
 	if err != nil {
 		t.Fatalf("Query: %v", err)
 	}
@@ -126,7 +141,7 @@ func TestQuery_ReturnsExcerpts(t *testing.T) {
 func TestQuery_ErrorsWhenNotLoaded(t *testing.T) {
 	indexer := New(testutil.NewMockCache(), testutil.MockParser{}, testutil.MockSearcher{}, testutil.NewMockReader(), testutil.NewMockClock(time.Time{}), nil)
 
-	_, err := indexer.Query("", "docs/nonexistent.md", "test", 500)
+	_, err := indexer.Query("", "docs/nonexistent.md", "test", 500, search.Options{}, nil)
 	if err == nil {
 		t.Error("Expected error for document not loaded")
 	}
@@ -140,7 +155,7 @@ func TestQuery_ErrorsWithoutPrompt(t *testing.T) {
 	indexer := New(cache, testutil.MockParser{}, testutil.MockSearcher{}, reader, testutil.NewMockClock(time.Time{}), nil)
 	_, _ = indexer.Load("docs/test.md")
 
-	_, err := indexer.Query("", "docs/test.md", "", 500) // Empty prompt
+	_, err := indexer.Query("", "docs/test.md", "", 500, search.Options{}, nil) // Empty prompt
 	if err == nil {
 		t.Error("Expected error for empty prompt")
 	}
@@ -149,12 +164,247 @@ func TestQuery_ErrorsWithoutPrompt(t *testing.T) {
 func TestQuery_ErrorsWithoutDocIDOrPath(t *testing.T) {
 	indexer := New(testutil.NewMockCache(), testutil.MockParser{}, testutil.MockSearcher{}, testutil.NewMockReader(), testutil.NewMockClock(time.Time{}), nil)
 
-	_, err := indexer.Query("", "", "test", 500) // Both empty
+	_, err := indexer.Query("", "", "test", 500, search.Options{}, nil) // Both empty
 	if err == nil {
 		t.Error("Expected error when both doc_id and path are empty")
 	}
 }
 
+func TestLoad_RestoresEmbeddingReadinessFromDisk(t *testing.T) {
+	cache := testutil.NewMockCache()
+	reader := testutil.NewMockReader()
+	reader.Files["docs/test.md"] = "# Test"
+	embedder := &testutil.MockEmbedder{}
+	status := embedding.NewStatus()
+
+	indexer := New(cache, testutil.MockParser{}, testutil.MockSearcher{}, reader, testutil.NewMockClock(time.Time{}), nil,
+		WithEmbedder(embedder, status))
+
+	// Seed the disk cache as if a previous run had embedded the doc
+	docID := parser.DocIDForPath("docs/test.md")
+	hash, _ := reader.HashFile("docs/test.md")
+	cache.Disk[docID] = &domain.Index{
+		DocID:          docID,
+		Path:           "docs/test.md",
+		FileHash:       hash,
+		Chunks:         []domain.Chunk{{ChunkID: "c1", Embedding: []float32{1, 0}}},
+		NumChunks:      1,
+		EmbeddingState: &domain.EmbeddingState{Provider: "mock", Model: "mock", Dimensions: 2, TextVersion: EmbeddingTextVersion, Complete: true},
+	}
+
+	result, err := indexer.Load("docs/test.md")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !result.FromCache {
+		t.Fatal("Expected FromCache=true")
+	}
+	if !status.IsReady(docID) {
+		t.Error("Expected embeddings to be ready after loading from disk")
+	}
+	if embedder.CallCount() != 0 {
+		t.Errorf("Expected no re-embedding, got %d EmbedBatch calls", embedder.CallCount())
+	}
+}
+
+func TestLoad_ReportsOutdatedEmbeddings(t *testing.T) {
+	cache := testutil.NewMockCache()
+	reader := testutil.NewMockReader()
+	reader.Files["docs/test.md"] = "# Test"
+	status := embedding.NewStatus()
+
+	embedder := &testutil.MockEmbedder{}
+	indexer := New(cache, testutil.MockParser{}, testutil.MockSearcher{}, reader, testutil.NewMockClock(time.Time{}), nil,
+		WithEmbedder(embedder, status))
+	indexer.Close() // Stop workers so the re-embed job never runs
+
+	docID := parser.DocIDForPath("docs/test.md")
+	hash, _ := reader.HashFile("docs/test.md")
+	cache.Disk[docID] = &domain.Index{
+		DocID:          docID,
+		Path:           "docs/test.md",
+		FileHash:       hash,
+		Chunks:         []domain.Chunk{{ChunkID: "c1", Embedding: []float32{1, 0}}},
+		NumChunks:      1,
+		EmbeddingState: &domain.EmbeddingState{Provider: "mock", Model: "mock", Dimensions: 2, TextVersion: 0, Complete: true},
+	}
+
+	if _, err := indexer.Load("docs/test.md"); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if status.IsReady(docID) {
+		t.Error("Expected vectors from an older text version not to be used")
+	}
+
+	docs := indexer.List(nil)
+	if len(docs) != 1 {
+		t.Fatalf("Expected 1 doc, got %d", len(docs))
+	}
+	if want := "outdated: text version changed (0 → 1)"; docs[0].Embeddings != want {
+		t.Errorf("Embeddings = %q, want %q", docs[0].Embeddings, want)
+	}
+}
+
+func TestLoad_RequeuesEmbeddingsFromOtherModel(t *testing.T) {
+	cache := testutil.NewMockCache()
+	reader := testutil.NewMockReader()
+	reader.Files["docs/test.md"] = "# Test"
+	embedder := &testutil.MockEmbedder{ModelName: "new-model"}
+	status := embedding.NewStatus()
+
+	indexer := New(cache, testutil.MockParser{}, testutil.MockSearcher{}, reader, testutil.NewMockClock(time.Time{}), nil,
+		WithEmbedder(embedder, status))
+
+	docID := parser.DocIDForPath("docs/test.md")
+	hash, _ := reader.HashFile("docs/test.md")
+	cache.Disk[docID] = &domain.Index{
+		DocID:          docID,
+		Path:           "docs/test.md",
+		FileHash:       hash,
+		Chunks:         []domain.Chunk{{ChunkID: "c1", Embedding: []float32{1, 0, 0}}},
+		NumChunks:      1,
+		EmbeddingState: &domain.EmbeddingState{Model: "old-model", Dimensions: 3, Complete: true},
+	}
+
+	if _, err := indexer.Load("docs/test.md"); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// Re-embedding runs in the background; wait for it to finish
+	deadline := time.Now().Add(2 * time.Second)
+	for !status.IsReady(docID) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !status.IsReady(docID) {
+		t.Fatal("Expected doc to be re-embedded and marked ready")
+	}
+
+	state := cache.Disk[docID].EmbeddingState
+	if state == nil || state.Model != "new-model" || state.Dimensions != 2 {
+		t.Errorf("Unexpected embedding state after re-embed: %+v", state)
+	}
+}
+
+func TestLoad_ReusesEmbeddingsForUnchangedChunks(t *testing.T) {
+	cache := testutil.NewMockCache()
+	reader := testutil.NewMockReader()
+	reader.Files["docs/test.md"] = "# Test"
+	embedder := &testutil.MockEmbedder{}
+	status := embedding.NewStatus()
+
+	indexer := New(cache, testutil.MockParser{}, testutil.MockSearcher{}, reader, testutil.NewMockClock(time.Time{}), nil,
+		WithEmbedder(embedder, status))
+	defer indexer.Close()
+
+	// The file changed on disk, but its only chunk has the same text
+	docID := parser.DocIDForPath("docs/test.md")
+	cache.Disk[docID] = &domain.Index{
+		DocID:          docID,
+		Path:           "docs/test.md",
+		FileHash:       "stale",
+		Chunks:         []domain.Chunk{{ChunkID: "old", Title: "Mock Section", Text: "# Test", Embedding: []float32{0, 1}}},
+		NumChunks:      1,
+		EmbeddingState: &domain.EmbeddingState{Provider: "mock", Model: "mock", Dimensions: 2, TextVersion: EmbeddingTextVersion, Complete: true},
+	}
+
+	result, err := indexer.Load("docs/test.md")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if result.FromCache {
+		t.Fatal("Expected a re-index")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !status.IsReady(docID) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !status.IsReady(docID) {
+		t.Fatal("Expected embeddings to be ready")
+	}
+	if embedder.CallCount() != 0 {
+		t.Errorf("Expected reused vectors, got %d EmbedBatch calls", embedder.CallCount())
+	}
+	if got := cache.Disk[docID].Chunks[0].Embedding; len(got) != 2 || got[1] != 1 {
+		t.Errorf("Embedding = %v, want reused [0 1]", got)
+	}
+}
+
+func TestLoad_EmbeddingJobReportsProgress(t *testing.T) {
+	cache := testutil.NewMockCache()
+	reader := testutil.NewMockReader()
+	reader.Files["docs/test.md"] = "# Test"
+	embedder := &testutil.MockEmbedder{}
+	status := embedding.NewStatus()
+
+	indexer := New(cache, testutil.MockParser{}, testutil.MockSearcher{}, reader, testutil.NewMockClock(time.Time{}), nil,
+		WithEmbedder(embedder, status), WithEmbeddingBatchSize(1))
+	defer indexer.Close()
+
+	result, err := indexer.Load("docs/test.md")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !status.IsReady(result.DocID) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	jobs := indexer.EmbeddingJobs()
+	if len(jobs) != 1 {
+		t.Fatalf("Expected 1 embedding job, got %d", len(jobs))
+	}
+	if jobs[0].State != queue.StateReady || jobs[0].Done != 1 || jobs[0].Total != 1 {
+		t.Errorf("Unexpected job: %+v", jobs[0])
+	}
+	if !cache.Disk[result.DocID].EmbeddingState.Complete {
+		t.Error("Expected embedding state to be complete on disk")
+	}
+}
+
+func TestClose_SavesVectorIndex(t *testing.T) {
+	reader := testutil.NewMockReader()
+	for _, name := range []string{"a", "b", "c"} {
+		reader.Files["docs/"+name+".md"] = "# " + name + "\n\nSome text about " + name + ".\n"
+	}
+	status := embedding.NewStatus()
+	path := filepath.Join(t.TempDir(), "vectors.hnsw")
+
+	registry := parser.NewDefaultRegistry(parser.NewCommonMarkParser())
+	indexer := New(testutil.NewMockCache(), registry, testutil.MockSearcher{}, reader, testutil.NewMockClock(time.Time{}), nil,
+		WithEmbedder(&testutil.MockEmbedder{}, status), WithVectorIndex(vector.NewFile(path, vector.DefaultConfig())))
+	var ids []string
+	for _, name := range []string{"a", "b", "c"} {
+		result, err := indexer.Load("docs/" + name + ".md")
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		ids = append(ids, result.DocID)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for _, id := range ids {
+		for !status.IsReady(id) && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// Saved once, later, not per document
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("vector index saved before the save delay: %v", err)
+	}
+	indexer.Close()
+	saved, err := vector.Open(path, vector.DefaultConfig())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for _, id := range ids {
+		if !saved.HasDoc(id) {
+			t.Errorf("saved index misses %s", id)
+		}
+	}
+}
+
 // --- Benchmarks ---
 
 // BenchmarkLoad measures single file loading performance.
@@ -213,6 +463,131 @@ func BenchmarkQuery(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = indexer.Query("", "docs/test.md", "consumer configuration", 500)
+		_, _ = indexer.Query("", "docs/test.md", "consumer configuration", 500, search.Options{}, nil)
+	}
+}
+
+func TestLoadGlob_SkipsUnsupportedFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"guide.md":   "# Guide\n\ntext",
+		"api.rst":    "API\n===\n\ntext",
+		"logo.png":   "\x89PNG\x00\x00",
+		"sidebar.js": "module.exports = {}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := parser.NewDefaultRegistry(parser.NewCommonMarkParser())
+	indexer := New(testutil.NewMockCache(), registry, testutil.MockSearcher{}, OSFileReader{}, testutil.NewMockClock(time.Time{}), nil)
+
+	result, err := indexer.LoadGlob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatalf("LoadGlob: %v", err)
+	}
+	if result.Loaded != 2 || result.Skipped != 2 || result.Failed != 0 {
+		t.Errorf("loaded=%d skipped=%d failed=%d, want 2/2/0", result.Loaded, result.Skipped, result.Failed)
+	}
+
+	// Loading one file explicitly accepts any text file
+	if _, err := indexer.Load(filepath.Join(dir, "sidebar.js")); err != nil {
+		t.Errorf("Load text file: %v", err)
+	}
+	if _, err := indexer.Load(filepath.Join(dir, "logo.png")); !errors.Is(err, ErrUnsupportedFile) {
+		t.Errorf("Load binary: err = %v, want ErrUnsupportedFile", err)
+	}
+
+	// Text in a legacy encoding is still text
+	latin1 := filepath.Join(dir, "notes.md")
+	if err := os.WriteFile(latin1, []byte("# Caf\xe9\n\nR\xe9sum\xe9 of the meeting"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := indexer.Load(latin1); err != nil {
+		t.Errorf("Load Latin-1 file: %v", err)
+	}
+}
+
+// siteFetcher serves fixed pages, failing for unknown URLs, and records how
+// many fetches ran at once.
+type siteFetcher struct {
+	pages map[string]string
+
+	mu             sync.Mutex
+	inFlight, peak int
+}
+
+func (f *siteFetcher) FetchAsMarkdown(urlStr string) (string, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.peak = max(f.peak, f.inFlight)
+	f.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+
+	if md, ok := f.pages[urlStr]; ok {
+		return md, nil
+	}
+	return "", &fetcher.FetchError{Kind: fetcher.KindNotFound, URL: urlStr, Err: errors.New("HTTP 404: 404 Not Found")}
+}
+
+func TestLoadSites_Concurrent(t *testing.T) {
+	f := &siteFetcher{pages: map[string]string{}}
+	var urls []string
+	for i := range 10 {
+		u := fmt.Sprintf("https://docs.example.com/page%d", i)
+		f.pages[u] = fmt.Sprintf("# Page %d\n\nContent.", i)
+		urls = append(urls, u)
+	}
+	urls = append(urls, "https://docs.example.com/missing", urls[0])
+
+	idx := New(testutil.NewMockCache(), parser.NewDefaultRegistry(parser.NewCommonMarkParser()), search.NewBM25Searcher(), OSFileReader{}, testutil.NewMockClock(time.Time{}), f)
+	outcomes := idx.LoadSites(context.Background(), urls, SiteLoadOptions{})
+
+	if len(outcomes) != 11 {
+		t.Fatalf("got %d outcomes, want 11 (duplicates loaded once)", len(outcomes))
+	}
+	for i, o := range outcomes[:10] {
+		if o.URL != urls[i] || o.Err != nil || o.Result.NumChunks == 0 {
+			t.Errorf("outcome %d = %+v", i, o)
+		}
+	}
+	if o := outcomes[10]; fetcher.KindOf(o.Err) != fetcher.KindNotFound {
+		t.Errorf("missing page: %v, want a not_found error", o.Err)
+	}
+	if f.peak < 2 || f.peak > maxSiteWorkers {
+		t.Errorf("%d fetches at once, want 2..%d", f.peak, maxSiteWorkers)
+	}
+}
+
+func TestLoadSite_ForgeFileKeepsWebURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/o/r/raw/branch/main/README.md" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, "# Readme\n\nRaw *markdown*.\n")
+	}))
+	defer srv.Close()
+
+	cache := testutil.NewMockCache()
+	idx := New(cache, parser.NewDefaultRegistry(parser.NewCommonMarkParser()), search.NewBM25Searcher(), OSFileReader{}, testutil.NewMockClock(time.Time{}), fetcher.NewHTTPFetcher())
+	webURL := srv.URL + "/o/r/src/branch/main/README.md"
+
+	result, err := idx.LoadSite(webURL, SiteLoadOptions{})
+	if err != nil {
+		t.Fatalf("LoadSite: %v", err)
+	}
+	index := cache.Mem[result.DocID]
+	if result.URL != webURL || index.SourceURL != webURL {
+		t.Errorf("URL = %q, SourceURL = %q; want the web URL", result.URL, index.SourceURL)
+	}
+	if len(index.Chunks) == 0 || !strings.Contains(index.Chunks[0].Text, "Raw *markdown*.") {
+		t.Errorf("chunks = %+v, want the raw markdown", index.Chunks)
 	}
 }
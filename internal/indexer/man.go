@@ -0,0 +1,96 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/parser"
+)
+
+// defaultManPath is searched for man pages when MANPATH is unset.
+var defaultManPath = []string{"/usr/local/share/man", "/usr/share/man", "/usr/local/man"}
+
+// manSectionRe matches a page name with its section, e.g. "git-commit(1)".
+var manSectionRe = regexp.MustCompile(`^(.+)\(([1-9][a-z]*|n)\)$`)
+
+// WithManPath sets the directories searched by LoadMan (default: $MANPATH,
+// then the usual system directories).
+func WithManPath(dirs ...string) Option {
+	return func(idx *Indexer) {
+		idx.manPath = dirs
+	}
+}
+
+// LoadMan indexes the installed man pages called name, e.g. "nats" or
+// "git-commit"; "git-commit(1)" or "git-commit.1" picks one section. Pages
+// are looked up in the man<section> directories of the man path and loaded
+// like any other file, gzipped or not. When a page is installed in several
+// directories, the first one on the man path wins.
+func (idx *Indexer) LoadMan(name string) ([]*LoadResult, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	page, section := parser.ManPageName(name)
+	if m := manSectionRe.FindStringSubmatch(name); m != nil {
+		page, section = m[1], m[2]
+	}
+
+	dirs := idx.manDirs()
+	var pages []string
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		matches, err := filepath.Glob(filepath.Join(dir, "man*", page+".*"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid name %q: %w", name, err)
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			n, s := parser.ManPageName(m)
+			if n != page || s == "" || (section != "" && s != section) || seen[s] {
+				continue
+			}
+			seen[s] = true
+			pages = append(pages, m)
+		}
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no man page for %q in %s", name, strings.Join(dirs, string(os.PathListSeparator)))
+	}
+
+	results := make([]*LoadResult, 0, len(pages))
+	for _, p := range pages {
+		result, err := idx.load(p, false)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// manDirs returns the man path. Empty entries in MANPATH stand for the
+// system directories, as in man(1).
+func (idx *Indexer) manDirs() []string {
+	if len(idx.manPath) > 0 {
+		return idx.manPath
+	}
+	env := os.Getenv("MANPATH")
+	if env == "" {
+		return defaultManPath
+	}
+	var dirs []string
+	for _, d := range filepath.SplitList(env) {
+		if d == "" {
+			dirs = append(dirs, defaultManPath...)
+		} else {
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs
+}
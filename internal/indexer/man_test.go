@@ -0,0 +1,65 @@
+package indexer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/parser"
+	"github.com/bad33ndj3/mcp-md-index/internal/search"
+	"github.com/bad33ndj3/mcp-md-index/internal/testutil"
+)
+
+func TestLoadMan_FindsGzippedPages(t *testing.T) {
+	root := t.TempDir()
+	page := ".TH NATS 1\n.SH OPTIONS\n.TP\n\\fB\\-\\-purge\\fR \\fIsubject\\fR\nPurge a subject.\n"
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(page))
+	zw.Close()
+	for name, content := range map[string][]byte{
+		"man1/nats.1.gz":      gz.Bytes(),
+		"man5/nats.5":         []byte(".TH NATS 5\n.SH FILES\nnats.conf\n"),
+		"man1/nats-server.1":  []byte(".TH NATS-SERVER 1\n"),
+		"man1/nats.1.bak.txt": []byte("not a page"),
+	} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry := parser.NewDefaultRegistry(parser.NewCommonMarkParser())
+	idx := New(testutil.NewMockCache(), registry, search.NewBM25Searcher(), OSFileReader{}, testutil.NewMockClock(time.Time{}), nil, WithManPath(root))
+
+	results, err := idx.LoadMan("nats")
+	if err != nil {
+		t.Fatalf("LoadMan: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected sections 1 and 5, got %d results", len(results))
+	}
+
+	out, err := idx.Query(results[0].DocID, "", "purge subject", 500, search.Options{}, nil)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if !strings.Contains(out, "nats(1) › OPTIONS") || !strings.Contains(out, "--purge subject") {
+		t.Errorf("query output:\n%s", out)
+	}
+
+	if results, err := idx.LoadMan("nats(5)"); err != nil || len(results) != 1 || !strings.HasSuffix(results[0].Path, "nats.5") {
+		t.Errorf("LoadMan(nats(5)) = %+v, %v", results, err)
+	}
+	if _, err := idx.LoadMan("kubectl"); err == nil {
+		t.Error("expected an error for a missing page")
+	}
+}
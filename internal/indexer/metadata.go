@@ -0,0 +1,73 @@
+package indexer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+	"github.com/bad33ndj3/mcp-md-index/internal/parser"
+)
+
+// Filter restricts queries and listings to documents whose front matter
+// has Key set to Value, e.g. "tags:kafka" or "deprecated:false".
+type Filter struct {
+	Key   string
+	Value string
+}
+
+// ParseFilters parses "key:value" expressions. Keys are case-insensitive,
+// matching the lowercased keys of parsed front matter.
+func ParseFilters(exprs []string) ([]Filter, error) {
+	filters := make([]Filter, 0, len(exprs))
+	for _, expr := range exprs {
+		key, value, ok := strings.Cut(expr, ":")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid filter %q (want key:value)", expr)
+		}
+		filters = append(filters, Filter{Key: key, Value: value})
+	}
+	return filters, nil
+}
+
+// matches reports whether metadata satisfies the filter. List values
+// ("kafka, streaming") match any of their items, and "false" also matches
+// a missing key so deprecated:false keeps documents that never set it.
+func (f Filter) matches(meta map[string]string) bool {
+	got, ok := meta[f.Key]
+	if !ok {
+		return strings.EqualFold(f.Value, "false")
+	}
+	if strings.EqualFold(got, f.Value) {
+		return true
+	}
+	for _, item := range strings.Split(got, ",") {
+		if strings.EqualFold(strings.TrimSpace(item), f.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// documentMetadata returns a document's front matter, or else the metadata
+// its parser attached to the chunks (e.g. an OpenAPI spec's title and version).
+func documentMetadata(content string, chunks []domain.Chunk) map[string]string {
+	if meta := parser.ParseFrontMatter(content).Metadata; len(meta) > 0 {
+		return meta
+	}
+	if len(chunks) > 0 {
+		return chunks[0].Metadata
+	}
+	return nil
+}
+
+// matchesFilters reports whether a document satisfies every filter.
+func matchesFilters(index *domain.Index, filters []Filter) bool {
+	for _, f := range filters {
+		if !f.matches(index.Metadata) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package indexer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/search"
+	"github.com/bad33ndj3/mcp-md-index/internal/testutil"
+)
+
+func TestParseFilters(t *testing.T) {
+	filters, err := ParseFilters([]string{"Tags: kafka", "deprecated:false"})
+	if err != nil {
+		t.Fatalf("ParseFilters: %v", err)
+	}
+	if filters[0] != (Filter{Key: "tags", Value: "kafka"}) || filters[1] != (Filter{Key: "deprecated", Value: "false"}) {
+		t.Errorf("filters = %+v", filters)
+	}
+
+	for _, bad := range []string{"tags", ":kafka", "tags:"} {
+		if _, err := ParseFilters([]string{bad}); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestFilter_Matches(t *testing.T) {
+	meta := map[string]string{"tags": "kafka, streaming", "deprecated": "true"}
+
+	tests := []struct {
+		filter Filter
+		want   bool
+	}{
+		{Filter{"tags", "Kafka"}, true},
+		{Filter{"tags", "streaming"}, true},
+		{Filter{"tags", "nats"}, false},
+		{Filter{"deprecated", "false"}, false},
+		{Filter{"owner", "false"}, true}, // missing key counts as false
+		{Filter{"owner", "platform"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.matches(meta); got != tt.want {
+			t.Errorf("%+v.matches = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestQueryAndList_FilterByMetadata(t *testing.T) {
+	reader := testutil.NewMockReader()
+	reader.Files["docs/kafka.md"] = "---\ntags: [kafka]\n---\n# Kafka"
+	reader.Files["docs/old.md"] = "---\ntags: [kafka]\ndeprecated: true\n---\n# Old"
+
+	indexer := New(testutil.NewMockCache(), testutil.MockParser{}, testutil.MockSearcher{}, reader, testutil.NewMockClock(time.Time{}), nil)
+	for path := range reader.Files {
+		if _, err := indexer.Load(path); err != nil {
+			t.Fatalf("Load %s: %v", path, err)
+		}
+	}
+
+	filters, _ := ParseFilters([]string{"tags:kafka", "deprecated:false"})
+	docs := indexer.List(filters)
+	if len(docs) != 1 || docs[0].Path != "docs/kafka.md" {
+		t.Fatalf("List = %+v, want only docs/kafka.md", docs)
+	}
+	if docs[0].Metadata["tags"] != "kafka" {
+		t.Errorf("Metadata = %v", docs[0].Metadata)
+	}
+
+	result, err := indexer.Query("", "docs/old.md", "kafka", 500, search.Options{}, filters)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if !strings.Contains(result, "does not match") {
+		t.Errorf("expected filtered-out document, got %q", result)
+	}
+}
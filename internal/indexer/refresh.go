@@ -0,0 +1,195 @@
+package indexer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
+)
+
+// DefaultSiteTTL is how long a fetched site document stays fresh.
+const DefaultSiteTTL = 24 * time.Hour
+
+// WithSiteTTL sets how long fetched site documents stay fresh before they are
+// revalidated (default: 24h); 0 means they never go stale.
+func WithSiteTTL(d time.Duration) Option {
+	return func(idx *Indexer) {
+		idx.siteTTL = d
+	}
+}
+
+// SiteLoadOptions controls LoadSite.
+type SiteLoadOptions struct {
+	Force bool // Re-fetch even if cached and fresh
+
+	// TTL overrides the default TTL for this site: positive durations set
+	// it, negative ones make the site never go stale, 0 keeps the site's
+	// current TTL (or the default for new sites).
+	TTL time.Duration
+}
+
+// fetchInfo records a fetch made now.
+func (idx *Indexer) fetchInfo(v fetcher.Validators, ttl time.Duration, extra map[string]string) *domain.FetchInfo {
+	return &domain.FetchInfo{
+		ETag:         v.ETag,
+		LastModified: v.LastModified,
+		FetchedAt:    idx.clock.Now(),
+		TTL:          ttl,
+		Metadata:     extra,
+	}
+}
+
+// siteTTLFor resolves a SiteLoadOptions.TTL against a site's current TTL.
+func (idx *Indexer) siteTTLFor(override time.Duration, current *domain.FetchInfo) time.Duration {
+	switch {
+	case override > 0:
+		return override
+	case override < 0:
+		return 0
+	case current != nil:
+		return current.TTL
+	}
+	return idx.siteTTL
+}
+
+// siteFetch returns how a site document was fetched. Documents cached before
+// fetch info was recorded count as fetched when indexed, with the default TTL.
+func (idx *Indexer) siteFetch(index *domain.Index) *domain.FetchInfo {
+	if index.Fetch != nil {
+		return index.Fetch
+	}
+	return &domain.FetchInfo{FetchedAt: index.IndexedAt, TTL: idx.siteTTL}
+}
+
+// stale reports whether a site document's TTL has passed.
+func (idx *Indexer) stale(index *domain.Index) bool {
+	if index.SourceURL == "" {
+		return false
+	}
+	info := idx.siteFetch(index)
+	return info.TTL > 0 && idx.clock.Now().After(info.FetchedAt.Add(info.TTL))
+}
+
+// fetchSite fetches a page, conditionally if the fetcher supports it.
+func (idx *Indexer) fetchSite(ctx context.Context, urlStr string, v fetcher.Validators) (*fetcher.Response, error) {
+	if r, ok := idx.fetcher.(fetcher.Revalidator); ok {
+		return r.FetchConditional(ctx, urlStr, v)
+	}
+	markdown, err := idx.fetcher.FetchAsMarkdown(urlStr)
+	if err != nil {
+		return nil, err
+	}
+	return &fetcher.Response{Markdown: markdown}, nil
+}
+
+// revalidate checks a cached site document with the server. On a 304, or if
+// the content hash is unchanged, the existing index is kept and only its
+// fetch info is updated; otherwise the page is re-indexed.
+func (idx *Indexer) revalidate(ctx context.Context, cached *domain.Index, ttl time.Duration) (*SiteLoadResult, error) {
+	prev := idx.siteFetch(cached)
+	resp, err := idx.fetchSite(ctx, cached.SourceURL, fetcher.Validators{ETag: prev.ETag, LastModified: prev.LastModified})
+	if err != nil {
+		return nil, fmt.Errorf("revalidate site: %w", err)
+	}
+	info := idx.fetchInfo(resp.Validators, idx.siteTTLFor(ttl, prev), prev.Metadata)
+
+	if !resp.NotModified {
+		hash := sha256.Sum256([]byte(resp.Markdown))
+		if hex.EncodeToString(hash[:]) != cached.FileHash {
+			return idx.indexSite(cached.SourceURL, resp.Markdown, info)
+		}
+	}
+
+	// Unchanged: keep the index (and its vectors, which saving rewrites)
+	if err := idx.cache.LoadVectors(cached); err != nil {
+		return nil, fmt.Errorf("load vectors: %w", err)
+	}
+	cached.Fetch = info
+	if err := idx.cache.SaveToDisk(cached); err != nil {
+		return nil, fmt.Errorf("save cache: %w", err)
+	}
+	result := cachedSiteResult(cached)
+	result.Revalidated = true
+	return result, nil
+}
+
+// setSiteTTL changes a cached site document's TTL (see SiteLoadOptions.TTL).
+func (idx *Indexer) setSiteTTL(index *domain.Index, override time.Duration) error {
+	prev := idx.siteFetch(index)
+	ttl := idx.siteTTLFor(override, prev)
+	if index.Fetch != nil && prev.TTL == ttl {
+		return nil
+	}
+	info := *prev
+	info.TTL = ttl
+	if err := idx.cache.LoadVectors(index); err != nil {
+		return fmt.Errorf("load vectors: %w", err)
+	}
+	index.Fetch = &info
+	if err := idx.cache.SaveToDisk(index); err != nil {
+		return fmt.Errorf("save cache: %w", err)
+	}
+	return nil
+}
+
+// cachedSiteResult describes a site document served from the cache.
+func cachedSiteResult(index *domain.Index) *SiteLoadResult {
+	return &SiteLoadResult{
+		DocID:     index.DocID,
+		URL:       index.SourceURL,
+		NumChunks: index.NumChunks,
+		FromCache: true,
+		IndexedAt: index.IndexedAt,
+	}
+}
+
+// RefreshResult summarizes a RefreshSites run.
+type RefreshResult struct {
+	Pages  []*SiteLoadResult // Revalidated documents; FromCache means unchanged
+	Fresh  int               // Site documents skipped because they are still fresh
+	Failed int
+	Errors []string
+}
+
+// RefreshSites revalidates every loaded site document whose TTL has passed
+// (every site document if all is set), re-indexing the ones that changed.
+func (idx *Indexer) RefreshSites(ctx context.Context, all bool) (*RefreshResult, error) {
+	if idx.fetcher == nil {
+		return nil, errors.New("site loading not configured (no fetcher)")
+	}
+
+	var due []*domain.Index
+	result := &RefreshResult{}
+	for _, docID := range idx.cache.List() {
+		index, err := idx.cache.Get(docID)
+		if err != nil || index.SourceURL == "" {
+			continue
+		}
+		if all || idx.stale(index) {
+			due = append(due, index)
+		} else {
+			result.Fresh++
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].SourceURL < due[j].SourceURL })
+
+	for _, index := range due {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		r, err := idx.revalidate(ctx, index, 0)
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", index.SourceURL, err))
+			continue
+		}
+		result.Pages = append(result.Pages, r)
+	}
+	return result, nil
+}
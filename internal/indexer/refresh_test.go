@@ -0,0 +1,160 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
+	"github.com/bad33ndj3/mcp-md-index/internal/parser"
+	"github.com/bad33ndj3/mcp-md-index/internal/search"
+	"github.com/bad33ndj3/mcp-md-index/internal/testutil"
+)
+
+// revalidatingSite serves one page with an ETag and counts full and
+// not-modified responses.
+type revalidatingSite struct {
+	mu             sync.Mutex
+	etag, body     string
+	full, notModif int
+}
+
+func (s *revalidatingSite) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+		if r.Header.Get("If-None-Match") == s.etag {
+			s.notModif++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	s.full++
+	fmt.Fprintf(w, "<html><body><h1>Streams</h1><p>%s</p></body></html>", s.body)
+}
+
+func (s *revalidatingSite) set(etag, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.etag, s.body = etag, body
+}
+
+func (s *revalidatingSite) counts() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.full, s.notModif
+}
+
+func TestLoadSite_RevalidatesAfterTTL(t *testing.T) {
+	site := &revalidatingSite{etag: `"v1"`, body: "Streams store messages."}
+	srv := httptest.NewServer(site)
+	defer srv.Close()
+
+	clk := testutil.NewMockClock(time.Time{})
+	idx := New(testutil.NewMockCache(), parser.NewDefaultRegistry(parser.NewCommonMarkParser()), search.NewBM25Searcher(), OSFileReader{}, &clk, fetcher.NewHTTPFetcher(), WithSiteTTL(time.Hour))
+	url := srv.URL + "/streams"
+
+	first, err := idx.LoadSite(url, SiteLoadOptions{})
+	if err != nil || first.FromCache {
+		t.Fatalf("LoadSite = %+v, %v", first, err)
+	}
+
+	// Fresh: served from the cache without a request
+	if r, err := idx.LoadSite(url, SiteLoadOptions{}); err != nil || !r.FromCache || r.Revalidated {
+		t.Errorf("fresh load = %+v, %v", r, err)
+	}
+	if full, nm := site.counts(); full != 1 || nm != 0 {
+		t.Errorf("requests after fresh load: %d full, %d not modified", full, nm)
+	}
+
+	// Stale: a 304 keeps the index
+	clk.Time = clk.Time.Add(2 * time.Hour)
+	if docs := idx.List(nil); len(docs) != 1 || !docs[0].Stale || docs[0].Fetch.ETag != `"v1"` {
+		t.Fatalf("List = %+v", docs)
+	}
+	r, err := idx.LoadSite(url, SiteLoadOptions{})
+	if err != nil || !r.FromCache || !r.Revalidated || !r.IndexedAt.Equal(first.IndexedAt) {
+		t.Errorf("revalidated load = %+v, %v", r, err)
+	}
+	if full, nm := site.counts(); full != 1 || nm != 1 {
+		t.Errorf("requests after revalidation: %d full, %d not modified", full, nm)
+	}
+	if docs := idx.List(nil); docs[0].Stale || !docs[0].Fetch.FetchedAt.Equal(clk.Time) {
+		t.Errorf("revalidated doc = %+v", docs[0].Fetch)
+	}
+
+	// Same content under a new ETag: still unchanged
+	clk.Time = clk.Time.Add(2 * time.Hour)
+	site.set(`"v2"`, "Streams store messages.")
+	if r, err := idx.LoadSite(url, SiteLoadOptions{}); err != nil || !r.Revalidated {
+		t.Errorf("same content = %+v, %v", r, err)
+	}
+	if docs := idx.List(nil); docs[0].Fetch.ETag != `"v2"` {
+		t.Errorf("etag = %q, want \"v2\"", docs[0].Fetch.ETag)
+	}
+
+	// New content: re-indexed
+	clk.Time = clk.Time.Add(2 * time.Hour)
+	site.set(`"v3"`, "Streams replicate messages.")
+	r, err = idx.LoadSite(url, SiteLoadOptions{})
+	if err != nil || r.FromCache || r.Revalidated {
+		t.Fatalf("changed content = %+v, %v", r, err)
+	}
+	out, err := idx.Query(r.DocID, "", "replicate", 500, search.Options{}, nil)
+	if err != nil || !strings.Contains(out, "Streams replicate messages.") {
+		t.Errorf("query after update: %q, %v", out, err)
+	}
+
+	// A negative TTL makes the site never go stale
+	if _, err := idx.LoadSite(url, SiteLoadOptions{TTL: -1}); err != nil {
+		t.Fatal(err)
+	}
+	clk.Time = clk.Time.Add(24 * time.Hour)
+	if docs := idx.List(nil); docs[0].Stale || docs[0].Fetch.TTL != 0 {
+		t.Errorf("never-stale doc = %+v", docs[0].Fetch)
+	}
+}
+
+func TestRefreshSites(t *testing.T) {
+	site := &revalidatingSite{etag: `"v1"`, body: "Old."}
+	srv := httptest.NewServer(site)
+	defer srv.Close()
+
+	clk := testutil.NewMockClock(time.Time{})
+	idx := New(testutil.NewMockCache(), parser.NewDefaultRegistry(parser.NewCommonMarkParser()), search.NewBM25Searcher(), OSFileReader{}, &clk, fetcher.NewHTTPFetcher(), WithSiteTTL(time.Hour))
+
+	// A crawled page and a page loaded later with a longer TTL
+	crawled := srv.URL + "/docs/a"
+	if _, err := idx.indexSite(crawled, "# Old", idx.fetchInfo(fetcher.Validators{ETag: `"v0"`}, time.Hour, map[string]string{"crawl": srv.URL + "/docs/"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.LoadSite(srv.URL+"/docs/b", SiteLoadOptions{TTL: 48 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	clk.Time = clk.Time.Add(2 * time.Hour)
+	result, err := idx.RefreshSites(context.Background(), false)
+	if err != nil {
+		t.Fatalf("RefreshSites: %v", err)
+	}
+	if len(result.Pages) != 1 || result.Pages[0].FromCache || result.Fresh != 1 || result.Failed != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+
+	// The re-indexed page keeps its crawl metadata
+	filters, _ := ParseFilters([]string{"crawl:" + srv.URL + "/docs/"})
+	docs := idx.List(filters)
+	if len(docs) != 1 || docs[0].SourceURL != crawled || docs[0].Fetch.ETag != `"v1"` {
+		t.Errorf("crawl group = %+v", docs)
+	}
+
+	if result, err := idx.RefreshSites(context.Background(), true); err != nil || len(result.Pages) != 2 || !result.Pages[0].Revalidated {
+		t.Errorf("refresh all = %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,136 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
+)
+
+// SiteListOptions controls LoadSiteList.
+type SiteListOptions struct {
+	fetcher.ListOptions
+	Force bool // Re-fetch every page, even cached ones
+}
+
+// SiteListResult summarizes loading the pages of a sitemap or llms.txt.
+type SiteListResult struct {
+	URL    string
+	Format string // fetcher.ListSitemap, fetcher.ListLLMs or fetcher.ListLLMsFull
+	Title  string // llms.txt title
+
+	// Document is the llms.txt or llms-full.txt file itself (nil for sitemaps)
+	Document *SiteLoadResult
+
+	Pages      []*SiteLoadResult
+	FromCache  int           // Pages served from the cache
+	Failed     int           // Pages that could not be fetched or indexed
+	Errors     []string      // Error messages for failed pages
+	Disallowed int           // Pages skipped because robots.txt disallows them
+	Truncated  int           // Listed pages over the max_pages limit
+	CrawlDelay time.Duration // Delay robots.txt asked for between requests
+}
+
+// LoadSiteList reads a sitemap.xml (or sitemap index) or llms.txt file and
+// indexes every page it lists, each as its own document. Every page is tagged
+// with metadata naming the list ("sitemap" or "llms" set to listURL), so the
+// pages can be queried or listed as a group; llms.txt pages also get the
+// "section" they are listed under, and "optional" for the Optional section.
+// The llms.txt file itself is indexed too, and an llms-full.txt file, which
+// holds the documentation itself, is indexed as one document.
+//
+// Cached pages are reused unless opts.Force is set or their sitemap
+// <lastmod> is newer than when they were fetched; pages without a lastmod are
+// re-fetched once their TTL has passed.
+func (idx *Indexer) LoadSiteList(ctx context.Context, listURL string, opts SiteListOptions) (*SiteListResult, error) {
+	if listURL == "" {
+		return nil, errors.New("url is required")
+	}
+	lister, ok := idx.fetcher.(fetcher.Lister)
+	if !ok {
+		return nil, errors.New("site lists not configured (fetcher cannot read sitemaps)")
+	}
+
+	listing, err := lister.List(ctx, listURL, opts.ListOptions)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", listURL, err)
+	}
+	result := &SiteListResult{URL: listURL, Format: listing.Format, Title: listing.Title, Truncated: listing.Truncated}
+
+	key := "sitemap"
+	if listing.Format != fetcher.ListSitemap {
+		key = "llms"
+	}
+	if listing.Markdown != "" {
+		info := idx.fetchInfo(fetcher.Validators{}, idx.siteTTL, map[string]string{key: listURL})
+		if result.Document, err = idx.indexSite(listURL, listing.Markdown, info); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reuse cached pages that haven't changed since they were indexed
+	entries := make(map[string]fetcher.ListEntry, len(listing.Entries))
+	var urls []string
+	for _, e := range listing.Entries {
+		entries[e.URL] = e
+		if !opts.Force {
+			if cached, ok := idx.cachedSite(e.URL); ok && idx.listedUnchanged(cached, e) {
+				result.FromCache++
+				result.Pages = append(result.Pages, cachedSiteResult(cached))
+				continue
+			}
+		}
+		urls = append(urls, e.URL)
+	}
+	if len(urls) == 0 {
+		return result, nil
+	}
+
+	stats, err := lister.FetchPages(ctx, urls, func(page fetcher.Page) {
+		if page.Err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", page.URL, page.Err))
+			return
+		}
+		info := idx.fetchInfo(page.Validators, idx.siteTTL, listMetadata(key, listURL, entries[page.URL]))
+		r, err := idx.indexSite(page.URL, page.Markdown, info)
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", page.URL, err))
+			return
+		}
+		result.Pages = append(result.Pages, r)
+	})
+	if stats != nil {
+		result.Disallowed = stats.Disallowed
+		result.CrawlDelay = stats.CrawlDelay
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch pages of %s: %w", listURL, err)
+	}
+	return result, nil
+}
+
+// listedUnchanged reports whether a cached page is current: its <lastmod> is
+// not newer than its last fetch or, without a lastmod, it is not stale.
+func (idx *Indexer) listedUnchanged(cached *domain.Index, e fetcher.ListEntry) bool {
+	if e.LastMod.IsZero() {
+		return !idx.stale(cached)
+	}
+	return !e.LastMod.After(idx.siteFetch(cached).FetchedAt)
+}
+
+// listMetadata is the metadata added to a page loaded from a list.
+func listMetadata(key, listURL string, e fetcher.ListEntry) map[string]string {
+	meta := map[string]string{key: listURL}
+	if e.Section != "" {
+		meta["section"] = e.Section
+	}
+	if e.Optional {
+		meta["optional"] = "true"
+	}
+	return meta
+}
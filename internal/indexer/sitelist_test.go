@@ -0,0 +1,96 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
+	"github.com/bad33ndj3/mcp-md-index/internal/parser"
+	"github.com/bad33ndj3/mcp-md-index/internal/search"
+	"github.com/bad33ndj3/mcp-md-index/internal/testutil"
+)
+
+func TestLoadSiteList_RefreshesByLastMod(t *testing.T) {
+	var fetches int32
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			// The mock clock indexes at 2024-01-01: only /docs/b changed since
+			fmt.Fprintf(w, `<urlset><url><loc>%[1]s/docs/a</loc><lastmod>2023-06-01</lastmod></url><url><loc>%[1]s/docs/b</loc><lastmod>2024-02-01</lastmod></url></urlset>`, srv.URL)
+		case "/docs/a", "/docs/b":
+			atomic.AddInt32(&fetches, 1)
+			fmt.Fprintf(w, "<html><body><h1>Page %s</h1></body></html>", r.URL.Path)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	idx := New(testutil.NewMockCache(), parser.NewDefaultRegistry(parser.NewCommonMarkParser()), search.NewBM25Searcher(), OSFileReader{}, testutil.NewMockClock(time.Time{}), fetcher.NewHTTPFetcher())
+	listURL := srv.URL + "/sitemap.xml"
+
+	result, err := idx.LoadSiteList(context.Background(), listURL, SiteListOptions{})
+	if err != nil {
+		t.Fatalf("LoadSiteList: %v", err)
+	}
+	if len(result.Pages) != 2 || result.FromCache != 0 || result.Document != nil || atomic.LoadInt32(&fetches) != 2 {
+		t.Fatalf("first load: %+v, %d fetches", result, atomic.LoadInt32(&fetches))
+	}
+
+	result, err = idx.LoadSiteList(context.Background(), listURL, SiteListOptions{})
+	if err != nil {
+		t.Fatalf("LoadSiteList: %v", err)
+	}
+	if len(result.Pages) != 2 || result.FromCache != 1 || atomic.LoadInt32(&fetches) != 3 {
+		t.Errorf("second load: %+v, %d fetches", result, atomic.LoadInt32(&fetches))
+	}
+
+	filters, _ := ParseFilters([]string{"sitemap:" + listURL})
+	if docs := idx.List(filters); len(docs) != 2 {
+		t.Errorf("sitemap group has %d docs", len(docs))
+	}
+}
+
+func TestLoadSiteList_LLMsTxt(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/llms.txt":
+			fmt.Fprint(w, "# NATS\n\n> Messaging docs.\n\n## Concepts\n\n- [Streams](/streams.md): Storage\n\n## Optional\n\n- [History](/history.md)\n")
+		case "/streams.md", "/history.md":
+			w.Header().Set("Content-Type", "text/markdown")
+			fmt.Fprintf(w, "# %s\n\nContent of %s.\n", r.URL.Path, r.URL.Path)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	idx := New(testutil.NewMockCache(), parser.NewDefaultRegistry(parser.NewCommonMarkParser()), search.NewBM25Searcher(), OSFileReader{}, testutil.NewMockClock(time.Time{}), fetcher.NewHTTPFetcher())
+	listURL := srv.URL + "/llms.txt"
+
+	result, err := idx.LoadSiteList(context.Background(), listURL, SiteListOptions{})
+	if err != nil {
+		t.Fatalf("LoadSiteList: %v", err)
+	}
+	if result.Format != fetcher.ListLLMs || result.Title != "NATS" || result.Document == nil || len(result.Pages) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+
+	for filter, want := range map[string]int{
+		"llms:" + listURL:  3, // The llms.txt file and both pages
+		"section:Concepts": 1,
+		"optional:true":    1,
+	} {
+		filters, _ := ParseFilters([]string{filter})
+		if docs := idx.List(filters); len(docs) != want {
+			t.Errorf("filter %s: %d docs, want %d", filter, len(docs), want)
+		}
+	}
+}
@@ -6,10 +6,15 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
 	"github.com/bad33ndj3/mcp-md-index/internal/indexer"
+	"github.com/bad33ndj3/mcp-md-index/internal/queue"
+	"github.com/bad33ndj3/mcp-md-index/internal/search"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -24,17 +29,70 @@ type QueryArgs struct {
 	Path      string `json:"path,omitempty" jsonschema_description:"Path to the markdown file (used to derive doc_id if doc_id omitted)"`
 	Prompt    string `json:"prompt" jsonschema_description:"Short query prompt (e.g. 'consumer')"`
 	MaxTokens int    `json:"max_tokens,omitempty" jsonschema_description:"Approx max tokens to return (default 500)"`
+
+	// Retrieval overrides; only take effect when embeddings are enabled
+	Mode       string   `json:"mode,omitempty" jsonschema_description:"Retrieval mode: 'bm25' (exact keywords and identifiers), 'semantic' (conceptual questions) or 'hybrid' (default)"`
+	Fusion     string   `json:"fusion,omitempty" jsonschema_description:"Hybrid fusion method: 'rrf' or 'weighted' (default: server setting)"`
+	BM25Weight *float64 `json:"bm25_weight,omitempty" jsonschema_description:"BM25 share for weighted fusion, 0.0-1.0; embeddings get the rest"`
+	RRFK       int      `json:"rrf_k,omitempty" jsonschema_description:"K constant for RRF fusion (default: server setting)"`
+
+	Filters []string `json:"filters,omitempty" jsonschema_description:"Front matter filters as key:value, all must match (e.g. ['tags:kafka', 'deprecated:false'])"`
+}
+
+// ListArgs defines the arguments for the docs_list tool.
+type ListArgs struct {
+	Filters []string `json:"filters,omitempty" jsonschema_description:"Front matter filters as key:value, all must match (e.g. ['tags:kafka'])"`
 }
 
 // SiteLoadsArgs defines the arguments for the site_loads tool.
 type SiteLoadsArgs struct {
 	URLs  []string `json:"urls" jsonschema_description:"URLs of websites to fetch and convert to markdown"`
 	Force bool     `json:"force,omitempty" jsonschema_description:"Force re-fetch even if cached (default: false)"`
+	TTL   string   `json:"ttl,omitempty" jsonschema_description:"How long these sites stay fresh before they are revalidated, e.g. '1h' or '7d'; '0' never (default: the server's -site-ttl)"`
+}
+
+// SiteRefreshArgs defines the arguments for the site_refresh tool.
+type SiteRefreshArgs struct {
+	All bool `json:"all,omitempty" jsonschema_description:"Revalidate every site document, not only stale ones (default: false)"`
+}
+
+// SiteCrawlArgs defines the arguments for the site_crawl tool.
+type SiteCrawlArgs struct {
+	URL      string   `json:"url" jsonschema_description:"Start URL of the crawl, e.g. 'https://docs.nats.io/nats-concepts/'"`
+	MaxDepth *int     `json:"max_depth,omitempty" jsonschema_description:"Link hops to follow from the start page; 0 fetches only the start page (default: 2)"`
+	MaxPages int      `json:"max_pages,omitempty" jsonschema_description:"Maximum pages to fetch (default: 50)"`
+	Include  []string `json:"include,omitempty" jsonschema_description:"Only crawl pages whose path matches one of these globs, e.g. '/docs/**' ('*' stays within a path segment)"`
+	Exclude  []string `json:"exclude,omitempty" jsonschema_description:"Skip pages whose path matches one of these globs, e.g. '/docs/**/changelog'"`
+	Scope    string   `json:"scope,omitempty" jsonschema_description:"'prefix' (default): stay under the start URL's directory; 'host': any page on the same host"`
+}
+
+// SiteLoadListArgs defines the arguments for the site_load_list tool.
+type SiteLoadListArgs struct {
+	URL      string   `json:"url" jsonschema_description:"URL of a sitemap.xml (or sitemap index), llms.txt or llms-full.txt file"`
+	MaxPages int      `json:"max_pages,omitempty" jsonschema_description:"Maximum listed pages to load (default: 200)"`
+	Include  []string `json:"include,omitempty" jsonschema_description:"Only load pages whose path matches one of these globs, e.g. '/docs/**' ('*' stays within a path segment)"`
+	Exclude  []string `json:"exclude,omitempty" jsonschema_description:"Skip pages whose path matches one of these globs, e.g. '/blog/**'"`
+	Force    bool     `json:"force,omitempty" jsonschema_description:"Re-fetch every page even if cached and unchanged (default: false)"`
 }
 
 // LoadGlobArgs defines the arguments for the docs_load_glob tool.
 type LoadGlobArgs struct {
-	Pattern string `json:"pattern" jsonschema_description:"Glob pattern to match markdown files (e.g. 'docs/**/*.md', '*.md')"`
+	Pattern string `json:"pattern" jsonschema_description:"Glob pattern to match documentation files (e.g. 'docs/**/*', 'docs/**/*.rst')"`
+}
+
+// LoadGoArgs defines the arguments for the docs_load_go tool.
+type LoadGoArgs struct {
+	Dir string `json:"dir" jsonschema_description:"Local Go module or package directory (e.g. '.' or 'pkg/client'); all packages below it are indexed"`
+}
+
+// LoadManArgs defines the arguments for the docs_load_man tool.
+type LoadManArgs struct {
+	Name string `json:"name" jsonschema_description:"Man page name, e.g. 'nats' or 'git-commit'; 'git-commit(1)' or 'git-commit.1' selects one section"`
+}
+
+// StatusArgs defines the arguments for the docs_status tool.
+type StatusArgs struct {
+	RetryFailed bool `json:"retry_failed,omitempty" jsonschema_description:"Re-queue failed embedding jobs (default: false)"`
 }
 
 // Handlers wraps the indexer and provides MCP tool handlers.
@@ -86,7 +144,7 @@ func (h *Handlers) DocsLoad(ctx context.Context, req *mcp.CallToolRequest, args
 }
 
 // DocsLoadGlob handles the docs_load_glob tool call.
-// It loads multiple markdown files matching a glob pattern.
+// It loads multiple documentation files matching a glob pattern.
 func (h *Handlers) DocsLoadGlob(ctx context.Context, req *mcp.CallToolRequest, args LoadGlobArgs) (*mcp.CallToolResult, any, error) {
 	if strings.TrimSpace(args.Pattern) == "" {
 		h.logger.Error("docs_load_glob: pattern is required")
@@ -105,6 +163,7 @@ func (h *Handlers) DocsLoadGlob(ctx context.Context, req *mcp.CallToolRequest, a
 		"pattern", args.Pattern,
 		"loaded", result.Loaded,
 		"cached", result.Cached,
+		"skipped", result.Skipped,
 		"failed", result.Failed,
 	)
 
@@ -115,6 +174,9 @@ func (h *Handlers) DocsLoadGlob(ctx context.Context, req *mcp.CallToolRequest, a
 	}
 
 	msg := fmt.Sprintf("Loaded %d files (%d cached), %d chunks total", result.Loaded, result.Cached, totalChunks)
+	if result.Skipped > 0 {
+		msg += fmt.Sprintf(", %d unsupported skipped", result.Skipped)
+	}
 	if result.Failed > 0 {
 		msg += fmt.Sprintf(", %d failed", result.Failed)
 	}
@@ -124,6 +186,83 @@ func (h *Handlers) DocsLoadGlob(ctx context.Context, req *mcp.CallToolRequest, a
 	}, nil, nil
 }
 
+// DocsLoadGo handles the docs_load_go tool call.
+// It indexes Go doc comments of every package under a local directory.
+func (h *Handlers) DocsLoadGo(ctx context.Context, req *mcp.CallToolRequest, args LoadGoArgs) (*mcp.CallToolResult, any, error) {
+	dir := strings.TrimSpace(args.Dir)
+	if dir == "" {
+		h.logger.Error("docs_load_go: dir is required")
+		return nil, nil, fmt.Errorf("dir is required")
+	}
+
+	h.logger.Debug("docs_load_go: loading packages", "dir", dir)
+
+	result, err := h.indexer.LoadGo(dir)
+	if err != nil {
+		h.logger.Error("docs_load_go: failed", "dir", dir, "error", err)
+		return nil, nil, err
+	}
+
+	h.logger.Info("docs_load_go: success",
+		"dir", dir,
+		"packages", len(result.Packages),
+		"cached", result.Cached,
+		"failed", result.Failed,
+	)
+
+	totalChunks := 0
+	var sb strings.Builder
+	for _, pkg := range result.Packages {
+		totalChunks += pkg.NumChunks
+		sb.WriteString(fmt.Sprintf("- %s\n  doc_id: %s\n  chunks: %d\n", pkg.ImportPath, pkg.DocID, pkg.NumChunks))
+	}
+	for _, e := range result.Errors {
+		sb.WriteString(fmt.Sprintf("- error: %s\n", e))
+	}
+
+	header := fmt.Sprintf("Loaded %d Go packages (%d cached), %d chunks total", len(result.Packages), result.Cached, totalChunks)
+	if result.Failed > 0 {
+		header += fmt.Sprintf(", %d failed", result.Failed)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: header + "\n\n" + sb.String()}},
+	}, nil, nil
+}
+
+// DocsLoadMan handles the docs_load_man tool call.
+// It finds installed man pages by name and indexes each section found.
+func (h *Handlers) DocsLoadMan(ctx context.Context, req *mcp.CallToolRequest, args LoadManArgs) (*mcp.CallToolResult, any, error) {
+	name := strings.TrimSpace(args.Name)
+	if name == "" {
+		h.logger.Error("docs_load_man: name is required")
+		return nil, nil, fmt.Errorf("name is required")
+	}
+
+	h.logger.Debug("docs_load_man: loading pages", "name", name)
+
+	results, err := h.indexer.LoadMan(name)
+	if err != nil {
+		h.logger.Error("docs_load_man: failed", "name", name, "error", err)
+		return nil, nil, err
+	}
+
+	h.logger.Info("docs_load_man: success", "name", name, "pages", len(results))
+
+	var sb strings.Builder
+	for _, r := range results {
+		status := "indexed"
+		if r.FromCache {
+			status = "cached"
+		}
+		sb.WriteString(fmt.Sprintf("- %s (%s)\n  doc_id: %s\n  chunks: %d\n", r.Path, status, r.DocID, r.NumChunks))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Loaded %d man pages for %s\n\n%s", len(results), name, sb.String())}},
+	}, nil, nil
+}
+
 // DocsQuery handles the docs_query tool call.
 // It searches an indexed document and returns token-bounded excerpts.
 // If no doc_id or path is provided, searches across all loaded documents.
@@ -137,24 +276,38 @@ func (h *Handlers) DocsQuery(ctx context.Context, req *mcp.CallToolRequest, args
 		return nil, nil, fmt.Errorf("prompt is required")
 	}
 
+	opts := search.Options{
+		Mode:       strings.ToLower(strings.TrimSpace(args.Mode)),
+		Fusion:     strings.ToLower(strings.TrimSpace(args.Fusion)),
+		BM25Weight: args.BM25Weight,
+		RRFK:       args.RRFK,
+	}
+
+	filters, err := indexer.ParseFilters(args.Filters)
+	if err != nil {
+		h.logger.Error("docs_query: invalid filters", "error", err)
+		return nil, nil, err
+	}
+
 	var answer string
-	var err error
 
 	// If no doc_id or path, search all documents
 	if docID == "" && path == "" {
 		h.logger.Debug("docs_query: searching all documents",
 			"prompt", prompt,
 			"max_tokens", args.MaxTokens,
+			"mode", opts.Mode,
 		)
-		answer, err = h.indexer.QueryAll(prompt, args.MaxTokens)
+		answer, err = h.indexer.QueryAll(prompt, args.MaxTokens, opts, filters)
 	} else {
 		h.logger.Debug("docs_query: searching specific document",
 			"doc_id", docID,
 			"path", path,
 			"prompt", prompt,
 			"max_tokens", args.MaxTokens,
+			"mode", opts.Mode,
 		)
-		answer, err = h.indexer.Query(docID, path, prompt, args.MaxTokens)
+		answer, err = h.indexer.Query(docID, path, prompt, args.MaxTokens, opts, filters)
 	}
 
 	if err != nil {
@@ -180,39 +333,251 @@ func (h *Handlers) SiteLoads(ctx context.Context, req *mcp.CallToolRequest, args
 		return nil, nil, fmt.Errorf("urls is required (provide at least one URL)")
 	}
 
-	h.logger.Debug("site_loads: fetching sites", "count", len(args.URLs), "force", args.Force)
+	ttl, err := parseTTL(args.TTL)
+	if err != nil {
+		h.logger.Error("site_loads: invalid ttl", "ttl", args.TTL, "error", err)
+		return nil, nil, err
+	}
 
-	var sb strings.Builder
-	loaded, cached, failed := 0, 0, 0
+	h.logger.Debug("site_loads: fetching sites", "count", len(args.URLs), "force", args.Force, "ttl", ttl)
 
+	var urls []string
 	for _, url := range args.URLs {
-		url = strings.TrimSpace(url)
-		if url == "" {
-			continue
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
 		}
+	}
 
-		result, err := h.indexer.LoadSite(url, args.Force)
-		if err != nil {
-			h.logger.Error("site_loads: failed to load", "url", url, "error", err)
-			failed++
-			sb.WriteString(fmt.Sprintf("- FAILED: %s (%v)\n", url, err))
+	var sb strings.Builder
+	loaded, cached := 0, 0
+	failures := make(map[fetcher.ErrorKind]int)
+
+	for _, o := range h.indexer.LoadSites(ctx, urls, indexer.SiteLoadOptions{Force: args.Force, TTL: ttl}) {
+		if o.Err != nil {
+			kind := fetcher.KindOf(o.Err)
+			h.logger.Error("site_loads: failed to load", "url", o.URL, "kind", kind, "error", o.Err)
+			failures[kind]++
+			sb.WriteString(fmt.Sprintf("- FAILED [%s]: %s (%v)\n", kind, o.URL, o.Err))
 			continue
 		}
 
 		loaded++
-		if result.FromCache {
+		if o.Result.FromCache {
 			cached++
 		}
-		sb.WriteString(fmt.Sprintf("- %s (chunks: %d)\n", url, result.NumChunks))
+		if o.Result.Revalidated {
+			sb.WriteString(fmt.Sprintf("- %s (chunks: %d, revalidated)\n", o.URL, o.Result.NumChunks))
+		} else {
+			sb.WriteString(fmt.Sprintf("- %s (chunks: %d)\n", o.URL, o.Result.NumChunks))
+		}
 	}
 
+	failed := 0
+	for _, n := range failures {
+		failed += n
+	}
 	h.logger.Info("site_loads: complete",
 		"loaded", loaded,
 		"cached", cached,
 		"failed", failed,
 	)
 
-	header := fmt.Sprintf("Loaded %d sites (%d from cache, %d failed)\n\n", loaded, cached, failed)
+	header := fmt.Sprintf("Loaded %d sites (%d from cache, %d failed%s)\n\n", loaded, cached, failed, formatFailures(failures))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: header + sb.String()}},
+	}, nil, nil
+}
+
+// formatFailures summarizes failures by kind, e.g. ": 2 timeout, 1 not_found"
+// (most frequent first), or "" without failures.
+func formatFailures(failures map[fetcher.ErrorKind]int) string {
+	if len(failures) == 0 {
+		return ""
+	}
+	kinds := make([]fetcher.ErrorKind, 0, len(failures))
+	for k := range failures {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if failures[kinds[i]] != failures[kinds[j]] {
+			return failures[kinds[i]] > failures[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+	parts := make([]string, len(kinds))
+	for i, k := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", failures[k], k)
+	}
+	return ": " + strings.Join(parts, ", ")
+}
+
+// SiteRefresh handles the site_refresh tool call.
+// It revalidates stale site documents and re-indexes the ones that changed.
+func (h *Handlers) SiteRefresh(ctx context.Context, req *mcp.CallToolRequest, args SiteRefreshArgs) (*mcp.CallToolResult, any, error) {
+	h.logger.Debug("site_refresh: revalidating", "all", args.All)
+
+	result, err := h.indexer.RefreshSites(ctx, args.All)
+	if err != nil {
+		h.logger.Error("site_refresh: failed", "error", err)
+		return nil, nil, err
+	}
+
+	updated := 0
+	var sb strings.Builder
+	for _, p := range result.Pages {
+		status := "unchanged"
+		if !p.FromCache {
+			status = "updated"
+			updated++
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s (chunks: %d)\n", p.URL, status, p.NumChunks))
+	}
+	for _, e := range result.Errors {
+		sb.WriteString(fmt.Sprintf("- FAILED: %s\n", e))
+	}
+
+	h.logger.Info("site_refresh: complete",
+		"checked", len(result.Pages)+result.Failed,
+		"updated", updated,
+		"failed", result.Failed,
+		"fresh", result.Fresh,
+	)
+
+	header := fmt.Sprintf("Revalidated %d site documents (%d updated, %d unchanged, %d failed); %d still fresh\n\n",
+		len(result.Pages)+result.Failed, updated, len(result.Pages)-updated, result.Failed, result.Fresh)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: header + sb.String()}},
+	}, nil, nil
+}
+
+// SiteCrawl handles the site_crawl tool call.
+// It crawls a site from a start URL and indexes each page as its own document.
+func (h *Handlers) SiteCrawl(ctx context.Context, req *mcp.CallToolRequest, args SiteCrawlArgs) (*mcp.CallToolResult, any, error) {
+	startURL := strings.TrimSpace(args.URL)
+	if startURL == "" {
+		h.logger.Error("site_crawl: url is required")
+		return nil, nil, fmt.Errorf("url is required")
+	}
+
+	maxDepth := fetcher.DefaultCrawlDepth
+	if args.MaxDepth != nil {
+		maxDepth = *args.MaxDepth
+	}
+	h.logger.Debug("site_crawl: crawling", "url", startURL, "max_depth", maxDepth, "max_pages", args.MaxPages)
+
+	result, err := h.indexer.CrawlSite(ctx, startURL, fetcher.CrawlOptions{
+		MaxDepth: args.MaxDepth,
+		MaxPages: args.MaxPages,
+		Include:  args.Include,
+		Exclude:  args.Exclude,
+		Scope:    args.Scope,
+	})
+	if err != nil {
+		h.logger.Error("site_crawl: failed", "url", startURL, "error", err)
+		return nil, nil, err
+	}
+
+	h.logger.Info("site_crawl: complete",
+		"url", startURL,
+		"pages", len(result.Pages),
+		"failed", result.Failed,
+		"disallowed", result.Disallowed,
+	)
+
+	totalChunks := 0
+	var sb strings.Builder
+	for _, p := range result.Pages {
+		totalChunks += p.NumChunks
+		sb.WriteString(fmt.Sprintf("- %s (chunks: %d)\n", p.URL, p.NumChunks))
+	}
+	for _, e := range result.Errors {
+		sb.WriteString(fmt.Sprintf("- FAILED: %s\n", e))
+	}
+
+	header := fmt.Sprintf("Crawled %d pages from %s, %d chunks total", len(result.Pages), startURL, totalChunks)
+	if result.Failed > 0 {
+		header += fmt.Sprintf(", %d failed", result.Failed)
+	}
+	if result.Disallowed > 0 {
+		header += fmt.Sprintf(", %d links disallowed by robots.txt", result.Disallowed)
+	}
+	if result.CrawlDelay > 0 {
+		header += fmt.Sprintf(" (crawl-delay %s)", result.CrawlDelay)
+	}
+	header += fmt.Sprintf("\n\nQuery or list these pages with filters: [\"crawl:%s\"]\n\n", startURL)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: header + sb.String()}},
+	}, nil, nil
+}
+
+// SiteLoadList handles the site_load_list tool call.
+// It loads every page listed by a sitemap or llms.txt file.
+func (h *Handlers) SiteLoadList(ctx context.Context, req *mcp.CallToolRequest, args SiteLoadListArgs) (*mcp.CallToolResult, any, error) {
+	listURL := strings.TrimSpace(args.URL)
+	if listURL == "" {
+		h.logger.Error("site_load_list: url is required")
+		return nil, nil, fmt.Errorf("url is required")
+	}
+
+	h.logger.Debug("site_load_list: loading", "url", listURL, "max_pages", args.MaxPages, "force", args.Force)
+
+	result, err := h.indexer.LoadSiteList(ctx, listURL, indexer.SiteListOptions{
+		ListOptions: fetcher.ListOptions{
+			MaxPages: args.MaxPages,
+			Include:  args.Include,
+			Exclude:  args.Exclude,
+		},
+		Force: args.Force,
+	})
+	if err != nil {
+		h.logger.Error("site_load_list: failed", "url", listURL, "error", err)
+		return nil, nil, err
+	}
+
+	h.logger.Info("site_load_list: complete",
+		"url", listURL,
+		"format", result.Format,
+		"pages", len(result.Pages),
+		"cached", result.FromCache,
+		"failed", result.Failed,
+	)
+
+	totalChunks := 0
+	var sb strings.Builder
+	if d := result.Document; d != nil {
+		totalChunks += d.NumChunks
+		sb.WriteString(fmt.Sprintf("- %s (chunks: %d)\n", d.URL, d.NumChunks))
+	}
+	for _, p := range result.Pages {
+		totalChunks += p.NumChunks
+		sb.WriteString(fmt.Sprintf("- %s (chunks: %d)\n", p.URL, p.NumChunks))
+	}
+	for _, e := range result.Errors {
+		sb.WriteString(fmt.Sprintf("- FAILED: %s\n", e))
+	}
+
+	name := result.Format
+	if result.Title != "" {
+		name += " \"" + result.Title + "\""
+	}
+	header := fmt.Sprintf("Loaded %d pages from %s %s, %d chunks total (%d from cache, %d failed)",
+		len(result.Pages), name, listURL, totalChunks, result.FromCache, result.Failed)
+	if result.Truncated > 0 {
+		header += fmt.Sprintf(", %d more listed pages over max_pages", result.Truncated)
+	}
+	if result.Disallowed > 0 {
+		header += fmt.Sprintf(", %d pages disallowed by robots.txt", result.Disallowed)
+	}
+	if result.CrawlDelay > 0 {
+		header += fmt.Sprintf(" (crawl-delay %s)", result.CrawlDelay)
+	}
+	key := "sitemap"
+	if result.Format != fetcher.ListSitemap {
+		key = "llms"
+	}
+	header += fmt.Sprintf("\n\nQuery or list these pages with filters: [\"%s:%s\"]\n\n", key, listURL)
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: header + sb.String()}},
 	}, nil, nil
@@ -220,11 +585,22 @@ func (h *Handlers) SiteLoads(ctx context.Context, req *mcp.CallToolRequest, args
 
 // DocsList handles the docs_list tool call.
 // It returns a list of all currently cached documents.
-func (h *Handlers) DocsList(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
-	h.logger.Debug("docs_list: listing cached documents")
+func (h *Handlers) DocsList(ctx context.Context, req *mcp.CallToolRequest, args ListArgs) (*mcp.CallToolResult, any, error) {
+	h.logger.Debug("docs_list: listing cached documents", "filters", args.Filters)
+
+	filters, err := indexer.ParseFilters(args.Filters)
+	if err != nil {
+		h.logger.Error("docs_list: invalid filters", "error", err)
+		return nil, nil, err
+	}
 
-	docs := h.indexer.List()
+	docs := h.indexer.List(filters)
 
+	if len(docs) == 0 && len(filters) > 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No loaded documents match the filters."}},
+		}, nil, nil
+	}
 	if len(docs) == 0 {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: "No documents currently loaded. Use docs_load or site_load first."}},
@@ -241,6 +617,15 @@ func (h *Handlers) DocsList(ctx context.Context, req *mcp.CallToolRequest, args
 		}
 		sb.WriteString(fmt.Sprintf("  path: %s\n", doc.Path))
 		sb.WriteString(fmt.Sprintf("  chunks: %d\n", doc.NumChunks))
+		if doc.Embeddings != "" {
+			sb.WriteString(fmt.Sprintf("  embeddings: %s\n", doc.Embeddings))
+		}
+		if len(doc.Metadata) > 0 {
+			sb.WriteString(fmt.Sprintf("  metadata: %s\n", formatMetadata(doc.Metadata)))
+		}
+		if doc.Fetch != nil {
+			sb.WriteString(fmt.Sprintf("  freshness: %s\n", formatFreshness(doc)))
+		}
 		sb.WriteString(fmt.Sprintf("  indexed_at: %s\n\n", doc.IndexedAt.Format(time.RFC3339)))
 	}
 
@@ -250,3 +635,128 @@ func (h *Handlers) DocsList(ctx context.Context, req *mcp.CallToolRequest, args
 		Content: []mcp.Content{&mcp.TextContent{Text: sb.String()}},
 	}, nil, nil
 }
+
+// DocsStatus handles the docs_status tool call.
+// It reports background embedding jobs grouped by state.
+func (h *Handlers) DocsStatus(ctx context.Context, req *mcp.CallToolRequest, args StatusArgs) (*mcp.CallToolResult, any, error) {
+	h.logger.Debug("docs_status: listing embedding jobs", "retry_failed", args.RetryFailed)
+
+	if !h.indexer.EmbeddingsEnabled() {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Embeddings are disabled (start the server with -experimental-embeddings). All queries use BM25."}},
+		}, nil, nil
+	}
+
+	var sb strings.Builder
+	if args.RetryFailed {
+		n := h.indexer.RetryFailedEmbeddings()
+		sb.WriteString(fmt.Sprintf("Re-queued %d failed jobs.\n\n", n))
+	}
+
+	jobs := h.indexer.EmbeddingJobs()
+	counts := make(map[queue.State]int)
+	for _, job := range jobs {
+		counts[job.State]++
+	}
+	sb.WriteString(fmt.Sprintf("Embedding jobs: %d pending, %d running, %d failed, %d ready\n",
+		counts[queue.StatePending], counts[queue.StateRunning], counts[queue.StateFailed], counts[queue.StateReady]))
+
+	for _, job := range jobs {
+		sb.WriteString(fmt.Sprintf("\n- doc_id: %s\n", job.DocID))
+		if job.Path != "" {
+			sb.WriteString(fmt.Sprintf("  path: %s\n", job.Path))
+		}
+		sb.WriteString(fmt.Sprintf("  state: %s\n", job.State))
+		if job.Total > 0 {
+			sb.WriteString(fmt.Sprintf("  progress: %d/%d chunks\n", job.Done, job.Total))
+		}
+		if job.State != queue.StateReady && job.Attempts > 0 {
+			sb.WriteString(fmt.Sprintf("  attempts: %d\n", job.Attempts))
+		}
+		if job.LastError != "" && job.State != queue.StateReady {
+			sb.WriteString(fmt.Sprintf("  last_error: %s\n", job.LastError))
+		}
+		if job.State == queue.StatePending && !job.NextAttempt.IsZero() {
+			sb.WriteString(fmt.Sprintf("  next_attempt: %s\n", job.NextAttempt.Format(time.RFC3339)))
+		}
+	}
+
+	h.logger.Info("docs_status: success", "jobs", len(jobs))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: sb.String()}},
+	}, nil, nil
+}
+
+// formatFreshness describes whether a site document is due for revalidation,
+// e.g. "stale (fetched 2024-01-01T00:00:00Z, ttl 1d)".
+func formatFreshness(doc indexer.DocInfo) string {
+	state := "fresh"
+	if doc.Stale {
+		state = "stale"
+	}
+	fetched := doc.Fetch.FetchedAt.Format(time.RFC3339)
+	if doc.Fetch.TTL == 0 {
+		return fmt.Sprintf("never stale (fetched %s)", fetched)
+	}
+	return fmt.Sprintf("%s (fetched %s, ttl %s)", state, fetched, formatTTL(doc.Fetch.TTL))
+}
+
+// parseTTL parses a ttl argument: a Go duration, optionally in whole days
+// ("7d"); "0" means never stale and "" the default.
+func parseTTL(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ttl %q (e.g. '12h' or '7d')", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid ttl %q (e.g. '12h' or '7d')", s)
+		}
+	}
+	switch {
+	case d < 0:
+		return 0, fmt.Errorf("invalid ttl %q (must not be negative)", s)
+	case d == 0:
+		return -1, nil // Never stale
+	}
+	return d, nil
+}
+
+// formatTTL renders a TTL compactly: "7d", "1h", "1h30m".
+func formatTTL(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// formatMetadata renders front matter as "key: value; ..." in key order.
+func formatMetadata(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + ": " + meta[k]
+	}
+	return strings.Join(parts, "; ")
+}
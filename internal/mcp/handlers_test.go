@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+	"github.com/bad33ndj3/mcp-md-index/internal/fetcher"
 	"github.com/bad33ndj3/mcp-md-index/internal/indexer"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -31,8 +32,9 @@ func (m *mockCache) Set(docID string, idx *domain.Index) { m.mem[docID] = idx }
 func (m *mockCache) LoadFromDisk(docID string) (*domain.Index, error) {
 	return nil, errors.New("not found")
 }
-func (m *mockCache) SaveToDisk(idx *domain.Index) error { return nil }
-func (m *mockCache) MarkdownPath(docID string) string   { return "/mock/" + docID + ".md" }
+func (m *mockCache) SaveToDisk(idx *domain.Index) error  { return nil }
+func (m *mockCache) LoadVectors(idx *domain.Index) error { return nil }
+func (m *mockCache) MarkdownPath(docID string) string    { return "/mock/" + docID + ".md" }
 func (m *mockCache) SaveMarkdown(docID string, content string) (string, error) {
 	return m.MarkdownPath(docID), nil
 }
@@ -177,3 +179,55 @@ func TestDocsQuery_ErrorsWithoutPrompt(t *testing.T) {
 		t.Error("Expected error for empty prompt")
 	}
 }
+
+func TestDocsStatus_ReportsEmbeddingsDisabled(t *testing.T) {
+	handlers, _ := createTestHandlers()
+
+	result, _, err := handlers.DocsStatus(context.Background(), nil, StatusArgs{})
+	if err != nil {
+		t.Fatalf("DocsStatus: %v", err)
+	}
+
+	text := getTextFromResult(result)
+	if !strings.Contains(text, "disabled") {
+		t.Errorf("Expected disabled notice, got: %s", text)
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"0", -1},
+		{"90m", 90 * time.Minute},
+		{"7d", 7 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got, err := parseTTL(tt.in); err != nil || got != tt.want {
+			t.Errorf("parseTTL(%q) = %s, %v; want %s", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"soon", "-1h", "xd"} {
+		if _, err := parseTTL(bad); err == nil {
+			t.Errorf("parseTTL(%q): expected an error", bad)
+		}
+	}
+
+	for d, want := range map[time.Duration]string{24 * time.Hour: "1d", time.Hour: "1h", 90 * time.Minute: "1h30m", 90 * time.Second: "1m30s"} {
+		if got := formatTTL(d); got != want {
+			t.Errorf("formatTTL(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestFormatFailures(t *testing.T) {
+	if got := formatFailures(nil); got != "" {
+		t.Errorf("no failures = %q", got)
+	}
+	got := formatFailures(map[fetcher.ErrorKind]int{fetcher.KindNotFound: 1, fetcher.KindTimeout: 2, fetcher.KindForbidden: 1})
+	if want := ": 2 timeout, 1 forbidden, 1 not_found"; got != want {
+		t.Errorf("formatFailures = %q, want %q", got, want)
+	}
+}
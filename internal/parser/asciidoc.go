@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// AsciiDocParser splits AsciiDoc by section titles ("== Section"). The
+// document title ("= Title") is the root heading, listing and literal blocks
+// become code blocks and are never split, and |=== table rows are extracted.
+type AsciiDocParser struct {
+	// MaxLinesPerChunk is the hard limit before forcing a new chunk (default: 120)
+	MaxLinesPerChunk int
+
+	// MinLinesPerChunk is the minimum before a heading triggers a new chunk (default: 12)
+	MinLinesPerChunk int
+}
+
+// NewAsciiDocParser creates an AsciiDoc parser with sensible defaults.
+func NewAsciiDocParser() *AsciiDocParser {
+	return &AsciiDocParser{
+		MaxLinesPerChunk: 120,
+		MinLinesPerChunk: 12,
+	}
+}
+
+// Parse splits an AsciiDoc file into chunks.
+func (p *AsciiDocParser) Parse(path, content string) ([]domain.Chunk, map[string]int) {
+	lines := strings.Split(content, "\n")
+	chunks := chunkLines(path, lines, analyzeAsciiDoc(lines), FrontMatter{}, p.MinLinesPerChunk, p.MaxLinesPerChunk)
+	return chunks, documentFrequency(chunks)
+}
+
+var (
+	// adocSectionRe matches "= Title" through "====== Title".
+	adocSectionRe = regexp.MustCompile(`^(={1,6})\s+(.+?)(?:\s+=+)?\s*$`)
+
+	// adocSourceRe matches a [source,lang] block attribute line.
+	adocSourceRe = regexp.MustCompile(`^\[(?:source)?,\s*([\w+#.-]+)`)
+
+	// adocDelimiterRe matches block delimiters: ----, ...., ++++, ////, |===, etc.
+	adocDelimiterRe = regexp.MustCompile(`^(-{4,}|\.{4,}|\+{4,}|/{4,}|\*{4,}|={4,}|_{4,}|--|\|={3,})$`)
+)
+
+// analyzeAsciiDoc finds section titles, delimited blocks and table rows.
+func analyzeAsciiDoc(lines []string) blockInfo {
+	info := newBlockInfo()
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t\r")
+		ln := i + 1
+
+		if m := adocDelimiterRe.FindStringSubmatch(line); m != nil {
+			end := adocBlockEnd(lines, i, m[1])
+			switch m[1][0] {
+			case '-', '.':
+				if m[1] == "--" {
+					continue // Open block: ordinary content
+				}
+				lang := ""
+				if i > 0 {
+					if s := adocSourceRe.FindStringSubmatch(strings.TrimSpace(lines[i-1])); s != nil {
+						lang = s[1]
+					}
+				}
+				info.code[ln] = domain.CodeBlock{Language: lang, Code: strings.Join(lines[i+1:end], "\n"), Line: ln}
+				info.protect(ln+1, end+1)
+				i = end
+			case '+', '/':
+				// Passthrough and comment blocks
+				info.protect(ln+1, end+1)
+				i = end
+			case '|':
+				for j := i + 1; j < end; j++ {
+					if t := strings.TrimSpace(lines[j]); strings.HasPrefix(t, "|") {
+						if cells := splitCells(t); len(cells) > 0 {
+							info.rows[j+1] = domain.TableRow{Cells: cells, Line: j + 1}
+						}
+					}
+				}
+				info.protect(ln+1, end+1)
+				i = end
+			}
+			// Example, sidebar and quote blocks hold ordinary content
+			continue
+		}
+
+		if m := adocSectionRe.FindStringSubmatch(line); m != nil {
+			info.headings[ln] = headingInfo{level: len(m[1]) - 1, title: m[2], endLine: ln}
+		}
+	}
+	return info
+}
+
+// adocBlockEnd returns the index of the line closing the block opened at i,
+// or len(lines) if the block is never closed.
+func adocBlockEnd(lines []string, i int, delim string) int {
+	for j := i + 1; j < len(lines); j++ {
+		if strings.TrimRight(lines[j], " \t\r") == delim {
+			return j
+		}
+	}
+	return len(lines)
+}
+
+// looksLikeAsciiDoc reports whether content starts with an AsciiDoc document
+// title or uses AsciiDoc section titles.
+func looksLikeAsciiDoc(content string) bool {
+	for _, line := range strings.SplitN(content, "\n", 50) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if m := adocSectionRe.FindStringSubmatch(line); m != nil && len(m[1]) <= 2 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAsciiDocParser_SectionsBlocksAndTables(t *testing.T) {
+	content := strings.Join([]string{
+		"= NATS Guide",
+		":toc:",
+		"",
+		"== Install",
+		"",
+		"[source,go]",
+		"----",
+		"== not a heading",
+		"nc, _ := nats.Connect(url)",
+		"----",
+		"",
+		"=== Options",
+		"",
+		"|===",
+		"| Name | Type",
+		"| timeout | duration",
+		"|===",
+		"",
+		"== Usage",
+		"text",
+	}, "\n")
+
+	p := NewAsciiDocParser()
+	p.MinLinesPerChunk = 1
+	chunks, _ := p.Parse("guide.adoc", content)
+
+	var paths []string
+	for _, c := range chunks {
+		paths = append(paths, strings.Join(c.HeadingPath, " > "))
+	}
+	want := []string{"NATS Guide", "NATS Guide > Install", "NATS Guide > Install > Options", "NATS Guide > Usage"}
+	if strings.Join(paths, "|") != strings.Join(want, "|") {
+		t.Fatalf("heading paths = %v, want %v", paths, want)
+	}
+
+	install := chunks[1]
+	if install.StartLine != 4 || install.EndLine != 11 {
+		t.Errorf("install lines = %d-%d, want 4-11", install.StartLine, install.EndLine)
+	}
+	if len(install.CodeBlocks) != 1 || install.CodeBlocks[0].Language != "go" || install.CodeBlocks[0].Line != 7 {
+		t.Errorf("listing block: %+v", install.CodeBlocks)
+	}
+	if rows := chunks[2].TableRows; len(rows) != 2 || rows[1].Line != 16 || strings.Join(rows[1].Cells, ",") != "timeout,duration" {
+		t.Errorf("table rows: %+v", rows)
+	}
+}
@@ -0,0 +1,477 @@
+package parser
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	gmtext "github.com/yuin/goldmark/text"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// CommonMarkParser splits markdown using a CommonMark/GFM syntax tree instead
+// of line regexes. Headings, code blocks and tables are recognized wherever the
+// spec allows them (setext headings, ~~~ and long fences, indented code,
+// headings in lists and blockquotes), and no split happens inside code or
+// HTML blocks. Chunking rules and output match MarkdownParser.
+type CommonMarkParser struct {
+	// MaxLinesPerChunk is the hard limit before forcing a new chunk (default: 120)
+	MaxLinesPerChunk int
+
+	// MinLinesPerChunk is the minimum before a heading triggers a new chunk (default: 12)
+	MinLinesPerChunk int
+
+	md goldmark.Markdown
+}
+
+// NewCommonMarkParser creates an AST-based parser with sensible defaults.
+func NewCommonMarkParser() *CommonMarkParser {
+	return &CommonMarkParser{
+		MaxLinesPerChunk: 120,
+		MinLinesPerChunk: 12,
+		md:               goldmark.New(goldmark.WithExtensions(extension.GFM)),
+	}
+}
+
+// blockInfo is what the syntax tree tells us about the document, by line.
+type blockInfo struct {
+	headings  map[int]headingInfo      // Start line -> heading
+	code      map[int]domain.CodeBlock // Start line -> code block
+	rows      map[int]domain.TableRow  // Line -> table row
+	protected map[int]bool             // Lines inside code/HTML blocks: never split here
+}
+
+func newBlockInfo() blockInfo {
+	return blockInfo{
+		headings:  make(map[int]headingInfo),
+		code:      make(map[int]domain.CodeBlock),
+		rows:      make(map[int]domain.TableRow),
+		protected: make(map[int]bool),
+	}
+}
+
+// protect marks lines from..to (inclusive) as unsplittable.
+func (info blockInfo) protect(from, to int) {
+	for ln := from; ln <= to; ln++ {
+		info.protected[ln] = true
+	}
+}
+
+// headingInfo describes one heading; setext headings span several lines.
+type headingInfo struct {
+	level   int
+	title   string
+	endLine int
+}
+
+// Parse splits a markdown file into chunks.
+// Each chunk corresponds roughly to a heading and its content.
+func (p *CommonMarkParser) Parse(path, content string) ([]domain.Chunk, map[string]int) {
+	lines := strings.Split(content, "\n")
+	fm := ParseFrontMatter(content)
+	info := p.analyze(lines, fm.Lines)
+
+	chunks := chunkLines(path, lines, info, fm, p.MinLinesPerChunk, p.MaxLinesPerChunk)
+	return chunks, documentFrequency(chunks)
+}
+
+// analyze parses the document and indexes its blocks by line. The first skip
+// lines (front matter) are blanked so they aren't read as markdown.
+// A top-level fence that is never closed would turn the rest of the file into
+// code; such a fence is treated as plain text and the document re-parsed.
+func (p *CommonMarkParser) analyze(lines []string, skip int) blockInfo {
+	md := p.md
+	if md == nil {
+		md = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	}
+
+	body := append(make([]string, skip, len(lines)), lines[skip:]...)
+	src := []byte(strings.Join(body, "\n"))
+	for attempt := 0; ; attempt++ {
+		doc := md.Parser().Parse(gmtext.NewReader(src))
+		idx := newLineIndex(src)
+
+		open := unterminatedFence(doc, src, idx)
+		if open == 0 || attempt >= len(lines) {
+			return collectBlocks(doc, src, idx)
+		}
+		src = escapeLine(src, idx, open)
+	}
+}
+
+// collectBlocks walks the syntax tree and records headings, code and tables.
+func collectBlocks(doc ast.Node, src []byte, idx lineIndex) blockInfo {
+	info := newBlockInfo()
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Heading:
+			lines := n.Lines()
+			if lines.Len() == 0 {
+				return ast.WalkSkipChildren, nil
+			}
+			start := idx.line(lines.At(0).Start)
+			end := idx.line(lines.At(lines.Len() - 1).Start)
+			if isSetextUnderline(idx.text(src, end+1)) {
+				end++
+			}
+			title := strings.TrimSpace(plainText(n, src))
+			if title != "" {
+				info.headings[start] = headingInfo{level: n.Level, title: title, endLine: end}
+			}
+			return ast.WalkSkipChildren, nil
+
+		case *ast.FencedCodeBlock:
+			open, last := fenceLines(n, src, idx)
+			if open == 0 {
+				return ast.WalkSkipChildren, nil
+			}
+			info.protect(open, last+1) // Include the closing fence
+			info.code[open] = domain.CodeBlock{
+				Language: string(n.Language(src)),
+				Code:     blockText(n.Lines(), src),
+				Line:     open,
+			}
+			return ast.WalkSkipChildren, nil
+
+		case *ast.CodeBlock:
+			lines := n.Lines()
+			if lines.Len() == 0 {
+				return ast.WalkSkipChildren, nil
+			}
+			start := idx.line(lines.At(0).Start)
+			info.protect(start, idx.line(lines.At(lines.Len()-1).Start))
+			info.code[start] = domain.CodeBlock{
+				Code: strings.TrimRight(blockText(lines, src), "\n"),
+				Line: start,
+			}
+			return ast.WalkSkipChildren, nil
+
+		case *ast.HTMLBlock:
+			lines := n.Lines()
+			if lines.Len() == 0 {
+				return ast.WalkSkipChildren, nil
+			}
+			end := idx.line(lines.At(lines.Len() - 1).Start)
+			if n.HasClosure() {
+				end = idx.line(n.ClosureLine.Start)
+			}
+			info.protect(idx.line(lines.At(0).Start), end)
+			return ast.WalkSkipChildren, nil
+
+		case *east.TableHeader, *east.TableRow:
+			var cells []string
+			line := 0
+			for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+				if line == 0 {
+					line = firstTextLine(c, idx)
+				}
+				if cell := strings.TrimSpace(plainText(c, src)); cell != "" {
+					cells = append(cells, cell)
+				}
+			}
+			if line > 0 && len(cells) > 0 {
+				info.rows[line] = domain.TableRow{Cells: cells, Line: line}
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return info
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Chunk Building
+// ─────────────────────────────────────────────────────────────────────────────
+
+// chunkLines splits a document into chunks using the blocks its parser found.
+// Front matter lines are skipped and its title becomes the root heading.
+// A new chunk starts at a heading once minLines are buffered, after maxLines,
+// or after 4+ blank lines, but never inside a protected block.
+func chunkLines(path string, lines []string, info blockInfo, fm FrontMatter, minLines, maxLines int) []domain.Chunk {
+	if maxLines == 0 {
+		maxLines = 120
+	}
+	if minLines == 0 {
+		minLines = 12
+	}
+
+	b := newChunkBuilder(DocIDForPath(path), path, filepath.Base(path))
+	b.start = fm.Lines + 1
+	if title := fm.Title(); title != "" {
+		b.title = title
+		b.headings.pushRoot(title)
+	}
+	blankRun := 0
+
+	for i := fm.Lines; i < len(lines); i++ {
+		ln := i + 1
+
+		if h, ok := info.headings[ln]; ok {
+			if len(b.buf) >= minLines {
+				b.flush(ln - 1)
+			}
+			b.headings.push(h.level, h.title)
+			b.title = h.title
+
+			// Keep multi-line (setext, underlined) headings together
+			for ; ln <= h.endLine; ln++ {
+				b.add(ln, lines[ln-1], info)
+			}
+			i = h.endLine - 1
+			blankRun = 0
+			continue
+		}
+
+		b.add(ln, lines[i], info)
+		if info.protected[ln] {
+			continue
+		}
+
+		if strings.TrimSpace(lines[i]) == "" {
+			blankRun++
+		} else {
+			blankRun = 0
+		}
+
+		// Force split if we hit max lines or 4+ blank lines in a row
+		if len(b.buf) >= maxLines || blankRun >= 4 {
+			b.flush(ln)
+			blankRun = 0
+		}
+	}
+
+	if len(b.buf) > 0 {
+		b.flush(len(lines))
+	}
+
+	applyFrontMatter(b.chunks, fm)
+	return b.chunks
+}
+
+// chunkBuilder accumulates lines and the blocks starting on them into chunks.
+type chunkBuilder struct {
+	docID, path string
+	title       string
+	start       int
+	buf         []string
+	headings    *headingStack
+	codeBlocks  []domain.CodeBlock
+	tableRows   []domain.TableRow
+	chunks      []domain.Chunk
+}
+
+func newChunkBuilder(docID, path, title string) *chunkBuilder {
+	return &chunkBuilder{
+		docID:    docID,
+		path:     path,
+		title:    title,
+		start:    1,
+		buf:      make([]string, 0, 256),
+		headings: &headingStack{},
+	}
+}
+
+// add appends a line and any code block or table row that starts on it.
+func (b *chunkBuilder) add(ln int, line string, info blockInfo) {
+	b.buf = append(b.buf, line)
+	if cb, ok := info.code[ln]; ok {
+		b.codeBlocks = append(b.codeBlocks, cb)
+	}
+	if row, ok := info.rows[ln]; ok {
+		b.tableRows = append(b.tableRows, row)
+	}
+}
+
+// flush saves the buffered lines (ending at endLine) as a chunk.
+func (b *chunkBuilder) flush(endLine int) {
+	if chunk, ok := newChunk(b.docID, b.path, b.title, b.headings.path(), b.start, endLine, b.buf, b.codeBlocks, b.tableRows); ok {
+		b.chunks = append(b.chunks, chunk)
+	}
+	b.buf = b.buf[:0]
+	b.start = endLine + 1
+	b.codeBlocks = nil
+	b.tableRows = nil
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Syntax Tree Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// lineIndex maps byte offsets to 1-indexed line numbers.
+type lineIndex []int // Byte offset where each line starts
+
+func newLineIndex(src []byte) lineIndex {
+	idx := lineIndex{0}
+	for i, c := range src {
+		if c == '\n' {
+			idx = append(idx, i+1)
+		}
+	}
+	return idx
+}
+
+// line returns the line containing offset.
+func (idx lineIndex) line(offset int) int {
+	return sort.Search(len(idx), func(i int) bool { return idx[i] > offset })
+}
+
+// text returns line ln without its newline ("" if out of range).
+func (idx lineIndex) text(src []byte, ln int) string {
+	if ln < 1 || ln > len(idx) {
+		return ""
+	}
+	end := len(src)
+	if ln < len(idx) {
+		end = idx[ln] - 1
+	}
+	return strings.TrimRight(string(src[idx[ln-1]:end]), "\r")
+}
+
+// plainText concatenates the text content of inline descendants.
+func plainText(n ast.Node, src []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch c := c.(type) {
+		case *ast.Text:
+			sb.Write(c.Value(src))
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(c.Value)
+		case *ast.AutoLink:
+			sb.Write(c.URL(src))
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
+// firstTextLine returns the line of the first text inside n (0 if none).
+func firstTextLine(n ast.Node, idx lineIndex) int {
+	line := 0
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			line = idx.line(t.Segment.Start)
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return line
+}
+
+// blockText joins the raw lines of a code block.
+func blockText(lines *gmtext.Segments, src []byte) string {
+	var buf bytes.Buffer
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		buf.Write(seg.Value(src))
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// fenceLines returns the opening fence line and the last line before the
+// closing fence (the opening line itself if the block is empty).
+// open is 0 if the fence position can't be determined.
+func fenceLines(n *ast.FencedCodeBlock, src []byte, idx lineIndex) (open, last int) {
+	lines := n.Lines()
+	switch {
+	case n.Info != nil:
+		open = idx.line(n.Info.Segment.Start)
+	case lines.Len() > 0:
+		open = idx.line(lines.At(0).Start) - 1
+	default:
+		return 0, 0
+	}
+	last = open
+	if lines.Len() > 0 {
+		last = idx.line(lines.At(lines.Len() - 1).Start)
+	}
+	return open, last
+}
+
+// unterminatedFence returns the opening line of the first top-level fenced
+// code block that has no closing fence, or 0 if every fence is closed.
+func unterminatedFence(doc ast.Node, src []byte, idx lineIndex) int {
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		fcb, ok := n.(*ast.FencedCodeBlock)
+		if !ok {
+			continue
+		}
+		open, last := fenceLines(fcb, src, idx)
+		if open == 0 {
+			continue
+		}
+		char, count := fenceMarker(idx.text(src, open))
+		if count == 0 {
+			continue
+		}
+		if c, k := fenceMarker(idx.text(src, last+1)); c != char || k < count || !isBareFence(idx.text(src, last+1)) {
+			return open
+		}
+	}
+	return 0
+}
+
+// fenceMarker returns the fence character and run length that starts a line
+// (after up to three spaces of indentation), or a zero count if none.
+func fenceMarker(line string) (byte, int) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || trimmed == "" || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return 0, 0
+	}
+	c := trimmed[0]
+	k := 0
+	for k < len(trimmed) && trimmed[k] == c {
+		k++
+	}
+	if k < 3 {
+		return 0, 0
+	}
+	return c, k
+}
+
+// isBareFence reports whether a line is only a fence (a valid closing fence).
+func isBareFence(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed != "" && strings.Trim(trimmed, string(trimmed[0])) == ""
+}
+
+// isSetextUnderline reports whether a line is a setext heading underline.
+func isSetextUnderline(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || len(line)-len(strings.TrimLeft(line, " ")) > 3 {
+		return false
+	}
+	return strings.Trim(trimmed, "=") == "" || strings.Trim(trimmed, "-") == ""
+}
+
+// escapeLine backslash-escapes the first non-space character of line ln,
+// so an unclosed fence is read as text. Line numbers are unchanged.
+func escapeLine(src []byte, idx lineIndex, ln int) []byte {
+	start := idx[ln-1]
+	for start < len(src) && src[start] == ' ' {
+		start++
+	}
+	out := make([]byte, 0, len(src)+1)
+	out = append(out, src[:start]...)
+	out = append(out, '\\')
+	return append(out, src[start:]...)
+}
@@ -0,0 +1,178 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseOneLinePerChunk parses with MinLinesPerChunk=1 so every heading starts a chunk.
+func parseOneLinePerChunk(content string) []string {
+	p := NewCommonMarkParser()
+	p.MinLinesPerChunk = 1
+	chunks, _ := p.Parse("doc.md", content)
+	titles := make([]string, len(chunks))
+	for i, c := range chunks {
+		titles[i] = c.Title
+	}
+	return titles
+}
+
+func TestCommonMarkParser_Fences(t *testing.T) {
+	content := strings.Join([]string{
+		"# Intro",
+		"",
+		"~~~python",
+		"# not a heading",
+		"~~~",
+		"",
+		"````markdown",
+		"```go",
+		"# still not a heading",
+		"```",
+		"````",
+		"",
+		"```go title=\"main.go\"",
+		"package main",
+		"```",
+		"",
+		"# Next",
+	}, "\n")
+
+	p := NewCommonMarkParser()
+	p.MinLinesPerChunk = 1
+	chunks, _ := p.Parse("doc.md", content)
+
+	if len(chunks) != 2 || chunks[0].Title != "Intro" || chunks[1].Title != "Next" {
+		t.Fatalf("unexpected chunks: %+v", chunks)
+	}
+
+	code := chunks[0].CodeBlocks
+	if len(code) != 3 {
+		t.Fatalf("expected 3 code blocks, got %+v", code)
+	}
+	if code[0].Language != "python" || code[0].Line != 3 || code[0].Code != "# not a heading" {
+		t.Errorf("tilde fence: %+v", code[0])
+	}
+	if code[1].Language != "markdown" || !strings.Contains(code[1].Code, "```go") {
+		t.Errorf("four-backtick fence: %+v", code[1])
+	}
+	if code[2].Language != "go" || code[2].Code != "package main" {
+		t.Errorf("info string: %+v", code[2])
+	}
+}
+
+func TestCommonMarkParser_SetextAndNestedHeadings(t *testing.T) {
+	content := strings.Join([]string{
+		"Guide",
+		"=====",
+		"",
+		"Intro text.",
+		"",
+		"Setup",
+		"-----",
+		"",
+		"> ## Quoted",
+		"> text",
+		"",
+		"- ### In a list",
+		"  item text",
+	}, "\n")
+
+	got := parseOneLinePerChunk(content)
+	want := []string{"Guide", "Setup", "Quoted", "In a list"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("titles = %v, want %v", got, want)
+	}
+}
+
+func TestCommonMarkParser_SetextHeadingPath(t *testing.T) {
+	p := NewCommonMarkParser()
+	p.MinLinesPerChunk = 1
+	chunks, _ := p.Parse("doc.md", "Guide\n=====\n\ntext\n\nSetup\n-----\n\nmore")
+
+	last := chunks[len(chunks)-1]
+	if strings.Join(last.HeadingPath, " > ") != "Guide > Setup" {
+		t.Errorf("heading path = %v", last.HeadingPath)
+	}
+	if last.StartLine != 6 {
+		t.Errorf("setext heading chunk should start at its text line, got %d", last.StartLine)
+	}
+}
+
+func TestCommonMarkParser_UnterminatedFenceDoesNotSwallowDocument(t *testing.T) {
+	content := strings.Join([]string{
+		"# One",
+		"",
+		"```go",
+		"func broken() {",
+		"",
+		"# Two",
+		"",
+		"text",
+	}, "\n")
+
+	got := parseOneLinePerChunk(content)
+	if strings.Join(got, "|") != "One|Two" {
+		t.Errorf("titles = %v, want [One Two]", got)
+	}
+}
+
+func TestCommonMarkParser_IndentedCodeHTMLAndTables(t *testing.T) {
+	content := strings.Join([]string{
+		"# API",
+		"",
+		"    # indented, not a heading",
+		"    x := 1",
+		"",
+		"<div>",
+		"",
+		"# inside html? no: blank line ends the block",
+		"",
+		"</div>",
+		"",
+		"| Name | Type |",
+		"|------|------|",
+		"| `id` | int  |",
+		"",
+		"[ref]: https://example.com \"Title\"",
+		"",
+		"See [ref].",
+	}, "\n")
+
+	p := NewCommonMarkParser()
+	chunks, _ := p.Parse("doc.md", content)
+	if len(chunks) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(chunks))
+	}
+	c := chunks[0]
+
+	if len(c.CodeBlocks) != 1 || c.CodeBlocks[0].Line != 3 || !strings.Contains(c.CodeBlocks[0].Code, "x := 1") {
+		t.Errorf("indented code: %+v", c.CodeBlocks)
+	}
+	if len(c.TableRows) != 2 {
+		t.Fatalf("expected header + 1 row, got %+v", c.TableRows)
+	}
+	if c.TableRows[0].Line != 12 || strings.Join(c.TableRows[0].Cells, ",") != "Name,Type" {
+		t.Errorf("header row: %+v", c.TableRows[0])
+	}
+	if c.TableRows[1].Line != 14 || strings.Join(c.TableRows[1].Cells, ",") != "id,int" {
+		t.Errorf("body row: %+v", c.TableRows[1])
+	}
+	if !strings.Contains(c.Text, "[ref]: https://example.com") {
+		t.Error("link reference definitions should stay in the chunk text")
+	}
+}
+
+func TestCommonMarkParser_NoSplitInsideCode(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("# Big\n\n```\n")
+	for i := 0; i < 10; i++ {
+		sb.WriteString("line\n\n\n\n\n") // 4+ blank lines would split outside code
+	}
+	sb.WriteString("```\n")
+
+	chunks, _ := NewCommonMarkParser().Parse("doc.md", sb.String())
+	if len(chunks) != 1 || len(chunks[0].CodeBlocks) != 1 {
+		t.Errorf("expected the code block to stay in one chunk, got %d chunks", len(chunks))
+	}
+}
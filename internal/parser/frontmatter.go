@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// FrontMatter is metadata parsed from the top of a document.
+type FrontMatter struct {
+	// Metadata maps lowercased keys to values. Lists are joined with ", ",
+	// nested tables are flattened with dotted keys (e.g. "owner.team").
+	Metadata map[string]string
+
+	// Lines is how many lines the front matter block occupies, including its
+	// delimiters. Document content starts on line Lines+1.
+	Lines int
+}
+
+// Title returns the "title" metadata value, if any.
+func (fm FrontMatter) Title() string {
+	return fm.Metadata["title"]
+}
+
+// ParseFrontMatter extracts a YAML (---) or TOML (+++) front matter block from
+// the start of content. It returns a zero FrontMatter if there is none or it
+// doesn't parse, in which case the block is treated as ordinary text.
+func ParseFrontMatter(content string) FrontMatter {
+	lines := strings.Split(content, "\n")
+	if len(lines) < 2 {
+		return FrontMatter{}
+	}
+
+	open := strings.TrimRight(lines[0], " \t\r")
+	if open != "---" && open != "+++" {
+		return FrontMatter{}
+	}
+
+	end := 0
+	for i := 1; i < len(lines); i++ {
+		l := strings.TrimRight(lines[i], " \t\r")
+		if l == open || (open == "---" && l == "...") {
+			end = i
+			break
+		}
+	}
+	if end == 0 {
+		return FrontMatter{}
+	}
+
+	body := strings.Join(lines[1:end], "\n")
+	raw := make(map[string]any)
+	if open == "+++" {
+		if _, err := toml.Decode(body, &raw); err != nil {
+			return FrontMatter{}
+		}
+	} else {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(body), &doc); err != nil {
+			return FrontMatter{}
+		}
+		if len(doc.Content) > 0 {
+			m, ok := yamlValue(doc.Content[0]).(map[string]any)
+			if !ok {
+				return FrontMatter{}
+			}
+			raw = m
+		}
+	}
+
+	meta := make(map[string]string)
+	flattenMetadata(meta, "", raw)
+	return FrontMatter{Metadata: meta, Lines: end + 1}
+}
+
+// yamlValue converts a YAML node to maps, slices and strings. Scalars keep
+// their source text so "version: 2.10" isn't read as the float 2.1.
+func yamlValue(n *yaml.Node) any {
+	switch n.Kind {
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			m[n.Content[i].Value] = yamlValue(n.Content[i+1])
+		}
+		return m
+	case yaml.SequenceNode:
+		items := make([]any, len(n.Content))
+		for i, c := range n.Content {
+			items[i] = yamlValue(c)
+		}
+		return items
+	case yaml.AliasNode:
+		return yamlValue(n.Alias)
+	case yaml.ScalarNode:
+		if n.Tag == "!!null" {
+			return nil
+		}
+		return n.Value
+	default:
+		return nil
+	}
+}
+
+// flattenMetadata converts decoded front matter values to strings.
+func flattenMetadata(out map[string]string, prefix string, m map[string]any) {
+	for k, v := range m {
+		key := strings.ToLower(strings.TrimSpace(k))
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := v.(map[string]any); ok {
+			flattenMetadata(out, key, nested)
+			continue
+		}
+		if s, ok := metadataValue(v); ok {
+			out[key] = s
+		}
+	}
+}
+
+// metadataValue formats a scalar or list; ok is false for empty values.
+func metadataValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		s := strings.TrimSpace(v)
+		return s, s != ""
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format("2006-01-02"), true
+		}
+		return v.Format(time.RFC3339), true
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := metadataValue(item); ok {
+				items = append(items, s)
+			}
+		}
+		return strings.Join(items, ", "), len(items) > 0
+	case map[string]any:
+		// Lists of tables: not useful as a filterable value
+		return "", false
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// applyFrontMatter gives every chunk the document's metadata.
+func applyFrontMatter(chunks []domain.Chunk, fm FrontMatter) {
+	if len(fm.Metadata) == 0 {
+		return
+	}
+	for i := range chunks {
+		chunks[i].Metadata = fm.Metadata
+	}
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		lines   int
+	}{
+		{
+			name:    "yaml",
+			content: "---\ntitle: JetStream\ntags: [kafka, streaming]\nversion: 2.10\ndeprecated: false\nowner:\n  team: platform\n---\n# Body",
+			want:    map[string]string{"title": "JetStream", "tags": "kafka, streaming", "version": "2.10", "deprecated": "false", "owner.team": "platform"},
+			lines:   8,
+		},
+		{
+			name:    "toml",
+			content: "+++\ntitle = \"Guide\"\ndate = 2024-05-01\ntags = [\"a\", \"b\"]\n+++\ntext",
+			want:    map[string]string{"title": "Guide", "date": "2024-05-01", "tags": "a, b"},
+			lines:   5,
+		},
+		{
+			name:    "yaml closed with dots",
+			content: "---\nTitle: Upper\n...\ntext",
+			want:    map[string]string{"title": "Upper"},
+			lines:   3,
+		},
+		{
+			name:    "thematic break is not front matter",
+			content: "---\n\nJust a rule.",
+		},
+		{
+			name:    "invalid yaml is left as text",
+			content: "---\ntitle: [unclosed\n---\ntext",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fm := ParseFrontMatter(tt.content)
+			if fm.Lines != tt.lines {
+				t.Errorf("Lines = %d, want %d", fm.Lines, tt.lines)
+			}
+			if len(fm.Metadata) != len(tt.want) {
+				t.Fatalf("Metadata = %v, want %v", fm.Metadata, tt.want)
+			}
+			for k, v := range tt.want {
+				if fm.Metadata[k] != v {
+					t.Errorf("Metadata[%q] = %q, want %q", k, fm.Metadata[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestParsers_UseFrontMatterTitleAsRootHeading(t *testing.T) {
+	content := "---\ntitle: JetStream\ntags: kafka\n---\n# JetStream\n\nIntro.\n\n## Consumers\n\nAck wait."
+
+	legacy := NewMarkdownParser()
+	legacy.MinLinesPerChunk = 1
+	commonmark := NewCommonMarkParser()
+	commonmark.MinLinesPerChunk = 1
+
+	for name, p := range map[string]Parser{"legacy": legacy, "commonmark": commonmark} {
+		t.Run(name, func(t *testing.T) {
+			chunks, _ := p.Parse("doc.md", content)
+			if len(chunks) != 2 {
+				t.Fatalf("expected 2 chunks, got %+v", chunks)
+			}
+			if strings.Contains(chunks[0].Text, "tags:") || chunks[0].StartLine != 5 {
+				t.Errorf("front matter should not be chunk text: start=%d %q", chunks[0].StartLine, chunks[0].Text)
+			}
+			if got := strings.Join(chunks[1].HeadingPath, " > "); got != "JetStream > Consumers" {
+				t.Errorf("heading path = %q, want %q", got, "JetStream > Consumers")
+			}
+			if chunks[1].Metadata["tags"] != "kafka" {
+				t.Errorf("chunk metadata = %v", chunks[1].Metadata)
+			}
+		})
+	}
+}
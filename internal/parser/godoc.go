@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	goparser "go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+	"github.com/bad33ndj3/mcp-md-index/internal/text"
+)
+
+// GoPackage is the documentation of one Go package, ready to index.
+type GoPackage struct {
+	Name       string
+	ImportPath string
+	Chunks     []domain.Chunk
+	DocFreq    map[string]int
+}
+
+// ParseGoPackage extracts the documentation of the Go package in dir using
+// go/doc. files maps each file path (including _test.go files, which supply
+// examples) to its source. The package overview and every exported
+// type, function and method become a chunk with the declaration as a code
+// block, its examples, a heading path like "pkg › Type › Method" and a source
+// range in the declaring file.
+func ParseGoPackage(dir, importPath string, files map[string]string) (*GoPackage, error) {
+	fset := token.NewFileSet()
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	asts := make([]*ast.File, 0, len(paths))
+	for _, path := range paths {
+		f, err := goparser.ParseFile(fset, path, files[path], goparser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+		}
+		asts = append(asts, f)
+	}
+
+	// go/doc clears doc comments and function bodies from the AST;
+	// remember where declarations start and end
+	b := &goDocBuilder{docID: DocIDForPath(dir), fset: fset, docStart: make(map[ast.Node]token.Pos), declEnd: make(map[ast.Node]token.Pos)}
+	var pkgFile *ast.File
+	for _, f := range asts {
+		if f.Doc != nil && pkgFile == nil && !strings.HasSuffix(fset.File(f.Pos()).Name(), "_test.go") {
+			pkgFile = f
+			b.docStart[f] = f.Doc.Pos()
+		}
+		for _, d := range f.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				if d.Doc != nil {
+					b.docStart[d] = d.Doc.Pos()
+				}
+				b.declEnd[d] = d.End()
+			case *ast.GenDecl:
+				if d.Doc != nil {
+					b.docStart[d] = d.Doc.Pos()
+				}
+			}
+		}
+	}
+	if pkgFile == nil && len(asts) > 0 {
+		pkgFile = asts[0]
+	}
+
+	pkg, err := doc.NewFromFiles(fset, asts, importPath)
+	if err != nil {
+		return nil, fmt.Errorf("read docs: %w", err)
+	}
+	b.pkg = pkg
+
+	if pkgFile != nil {
+		b.overview(pkgFile)
+	}
+	b.values(pkg.Consts, "Constants")
+	b.values(pkg.Vars, "Variables")
+	for _, f := range pkg.Funcs {
+		b.function(f, nil)
+	}
+	for _, t := range pkg.Types {
+		b.typ(t)
+	}
+
+	return &GoPackage{
+		Name:       pkg.Name,
+		ImportPath: importPath,
+		Chunks:     b.chunks,
+		DocFreq:    documentFrequency(b.chunks),
+	}, nil
+}
+
+// goDocBuilder turns go/doc declarations into chunks.
+type goDocBuilder struct {
+	docID    string
+	fset     *token.FileSet
+	pkg      *doc.Package
+	docStart map[ast.Node]token.Pos // Declaration -> start of its doc comment
+	declEnd  map[ast.Node]token.Pos // Function -> end of its body
+	chunks   []domain.Chunk
+}
+
+// overview adds the package doc comment and package-level examples, linked
+// to the file holding the package comment.
+func (b *goDocBuilder) overview(f *ast.File) {
+	sig := "package " + b.pkg.Name
+	if b.pkg.ImportPath != "" {
+		sig += ` // import "` + b.pkg.ImportPath + `"`
+	}
+	start := f.Package
+	if pos, ok := b.docStart[f]; ok {
+		start = pos
+	}
+	b.add(f, b.fset.Position(start).Line, b.fset.Position(f.Name.End()).Line, []string{b.pkg.Name}, sig, b.pkg.Doc, b.pkg.Examples)
+}
+
+// values adds one chunk per exported const or var group.
+func (b *goDocBuilder) values(values []*doc.Value, kind string) {
+	for _, v := range values {
+		names := strings.Join(v.Names, ", ")
+		start, end := b.lines(v.Decl)
+		b.add(v.Decl, start, end, []string{b.pkg.Name, kind + ": " + names}, b.source(v.Decl), v.Doc, nil)
+	}
+}
+
+// typ adds a type, then its constants, variables, constructors and methods.
+func (b *goDocBuilder) typ(t *doc.Type) {
+	start, end := b.lines(t.Decl)
+	b.add(t.Decl, start, end, []string{b.pkg.Name, t.Name}, b.source(t.Decl), t.Doc, t.Examples)
+
+	for _, v := range append(t.Consts, t.Vars...) {
+		start, end := b.lines(v.Decl)
+		b.add(v.Decl, start, end, []string{b.pkg.Name, t.Name, strings.Join(v.Names, ", ")}, b.source(v.Decl), v.Doc, nil)
+	}
+	for _, f := range t.Funcs {
+		b.function(f, t)
+	}
+	for _, m := range t.Methods {
+		b.function(m, t)
+	}
+}
+
+// function adds a function or method; t is its type (receiver or the type
+// a constructor returns), or nil for other functions.
+func (b *goDocBuilder) function(f *doc.Func, t *doc.Type) {
+	path := []string{b.pkg.Name, f.Name}
+	if t != nil {
+		path = []string{b.pkg.Name, t.Name, f.Name}
+	}
+
+	// Signature only: print the declaration without its body
+	decl := *f.Decl
+	decl.Body = nil
+	decl.Doc = nil
+	start, end := b.lines(f.Decl)
+	b.add(f.Decl, start, end, path, b.source(&decl), f.Doc, f.Examples)
+}
+
+// add appends a chunk for a declaration at start..end in its file.
+func (b *goDocBuilder) add(node ast.Node, start, end int, headingPath []string, sig, comment string, examples []*doc.Example) {
+	file := b.fset.Position(node.Pos()).Filename
+
+	code := []domain.CodeBlock{{Language: "go", Code: sig, Line: start}}
+	var sb strings.Builder
+	sb.WriteString("```go\n" + sig + "\n```\n")
+	if comment = strings.TrimSpace(comment); comment != "" {
+		sb.WriteString("\n" + comment + "\n")
+	}
+	for _, ex := range examples {
+		src := b.example(ex)
+		name := "Example"
+		if ex.Suffix != "" {
+			name += " (" + ex.Suffix + ")"
+		}
+		sb.WriteString("\n" + name + ":\n```go\n" + src + "\n```\n")
+		code = append(code, domain.CodeBlock{Language: "go", Code: src, Line: b.fset.Position(ex.Code.Pos()).Line})
+	}
+
+	txt := strings.TrimSpace(sb.String())
+	b.chunks = append(b.chunks, domain.Chunk{
+		ChunkID:     fmt.Sprintf("%s:%s:%d-%d", b.docID, filepath.Base(file), start, end),
+		DocID:       b.docID,
+		Path:        file,
+		Title:       headingPath[len(headingPath)-1],
+		HeadingPath: headingPath,
+		StartLine:   start,
+		EndLine:     end,
+		Text:        txt,
+		Terms:       text.NormalizeTerms(txt),
+		CodeBlocks:  code,
+		HasCode:     true,
+	})
+}
+
+// example renders an example's body and its expected output.
+func (b *goDocBuilder) example(ex *doc.Example) string {
+	src := b.source(ex.Code)
+	if block, ok := ex.Code.(*ast.BlockStmt); ok {
+		// Print the statements, not the braces around them
+		var stmts []string
+		for _, s := range block.List {
+			stmts = append(stmts, b.source(s))
+		}
+		src = strings.Join(stmts, "\n")
+	}
+	if ex.Output != "" {
+		src += "\n// Output:\n// " + strings.ReplaceAll(strings.TrimSpace(ex.Output), "\n", "\n// ")
+	}
+	return src
+}
+
+// lines returns the line range of a declaration, including its doc comment
+// and body.
+func (b *goDocBuilder) lines(node ast.Node) (int, int) {
+	start, end := node.Pos(), node.End()
+	if pos, ok := b.docStart[node]; ok {
+		start = pos
+	}
+	if pos, ok := b.declEnd[node]; ok {
+		end = pos
+	}
+	return b.fset.Position(start).Line, b.fset.Position(end).Line
+}
+
+// source prints an AST node as Go source.
+func (b *goDocBuilder) source(node any) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, b.fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
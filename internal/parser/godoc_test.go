@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGoPackage(t *testing.T) {
+	files := map[string]string{
+		"/mod/client/client.go": `// Package client talks to the server.
+package client
+
+// Client is a connection.
+type Client struct {
+	Addr string // Server address
+	conn int
+}
+
+// New creates a Client.
+func New(addr string) *Client {
+	return &Client{Addr: addr}
+}
+
+// Send writes a message.
+func (c *Client) Send(msg string) error {
+	return nil
+}
+
+func (c *Client) internal() {}
+`,
+		"/mod/client/example_test.go": `package client_test
+
+import (
+	"fmt"
+
+	"example.com/mod/client"
+)
+
+func ExampleClient_Send() {
+	c := client.New("localhost")
+	fmt.Println(c.Send("hi"))
+	// Output: <nil>
+}
+`,
+	}
+
+	pkg, err := ParseGoPackage("/mod/client", "example.com/mod/client", files)
+	if err != nil {
+		t.Fatalf("ParseGoPackage: %v", err)
+	}
+
+	byPath := make(map[string]int)
+	for i, c := range pkg.Chunks {
+		byPath[strings.Join(c.HeadingPath, " › ")] = i
+	}
+	for _, want := range []string{"client", "client › Client", "client › Client › New", "client › Client › Send"} {
+		if _, ok := byPath[want]; !ok {
+			t.Errorf("missing chunk %q (have %v)", want, byPath)
+		}
+	}
+	if len(pkg.Chunks) != 4 {
+		t.Errorf("expected 4 chunks (unexported methods hidden), got %d", len(pkg.Chunks))
+	}
+
+	overview := pkg.Chunks[byPath["client"]]
+	if !strings.Contains(overview.Text, `package client // import "example.com/mod/client"`) || overview.StartLine != 1 {
+		t.Errorf("overview: line %d %q", overview.StartLine, overview.Text)
+	}
+
+	send := pkg.Chunks[byPath["client › Client › Send"]]
+	if send.Path != "/mod/client/client.go" || send.StartLine != 15 || send.EndLine != 18 {
+		t.Errorf("Send source = %s#L%d-L%d, want client.go#L15-L18", send.Path, send.StartLine, send.EndLine)
+	}
+	if len(send.CodeBlocks) != 2 || send.CodeBlocks[0].Code != "func (c *Client) Send(msg string) error" {
+		t.Fatalf("Send code blocks: %+v", send.CodeBlocks)
+	}
+	if ex := send.CodeBlocks[1].Code; !strings.Contains(ex, `c := client.New("localhost")`) || !strings.Contains(ex, "// Output:\n// <nil>") {
+		t.Errorf("example = %q", ex)
+	}
+
+	typ := pkg.Chunks[byPath["client › Client"]]
+	if strings.Contains(typ.Text, "conn int") {
+		t.Errorf("unexported field should be hidden: %q", typ.Text)
+	}
+}
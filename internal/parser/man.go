@@ -0,0 +1,327 @@
+package parser
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// ManParser splits Unix man pages (troff with the man(7) macros) by their
+// .SH and .SS sections. Each source line is rendered to plain text, so line
+// numbers still point into the page: font and special-character escapes are
+// resolved, .TP/.IP entries (option lists) become "`--flag arg`" lines that
+// are also extracted as table rows, and .nf/.EX blocks (examples) become code
+// blocks. Heading paths look like "nats-stream(1) › OPTIONS".
+type ManParser struct {
+	// MaxLinesPerChunk is the hard limit before forcing a new chunk (default: 120)
+	MaxLinesPerChunk int
+
+	// MinLinesPerChunk is the minimum before a heading triggers a new chunk (default: 12)
+	MinLinesPerChunk int
+}
+
+// NewManParser creates a man page parser with sensible defaults.
+func NewManParser() *ManParser {
+	return &ManParser{
+		MaxLinesPerChunk: 120,
+		MinLinesPerChunk: 12,
+	}
+}
+
+// Parse splits a man page into chunks. The page name and section from .TH
+// (or else the file name) become the root heading and "title", "section"
+// metadata.
+func (p *ManParser) Parse(path, content string) ([]domain.Chunk, map[string]int) {
+	page := renderMan(strings.Split(content, "\n"))
+
+	name, section := page.name, page.section
+	if name == "" {
+		name, section = ManPageName(path)
+	}
+	meta := map[string]string{"format": "man", "title": name}
+	if section != "" {
+		meta["title"] = name + "(" + section + ")"
+		meta["section"] = section
+	}
+
+	chunks := chunkLines(path, page.lines, page.info, FrontMatter{Metadata: meta}, p.MinLinesPerChunk, p.MaxLinesPerChunk)
+	return chunks, documentFrequency(chunks)
+}
+
+var (
+	// manPageRe matches the .TH title line that starts every man(7) page.
+	manPageRe = regexp.MustCompile(`(?m)^\.TH[ \t]`)
+
+	// manEscapeRe matches troff escapes: fonts, sizes, strings and special characters.
+	manEscapeRe = regexp.MustCompile(`\\(?:[fF*](?:\[[^\]]*\]|\(..|.)|s[-+]?\d+|\(..|\[[^\]]*\]|.)`)
+
+	// manSectionExtRe matches man page file extensions such as ".1" or ".3pm".
+	manSectionExtRe = regexp.MustCompile(`^\.([1-9][a-z]*|n)$`)
+)
+
+// looksLikeMan reports whether content is a man(7) page.
+func looksLikeMan(content string) bool {
+	return manPageRe.MatchString(content)
+}
+
+// ManPageName derives a page's name and section from its file name, e.g.
+// "nats-stream.1.gz" → ("nats-stream", "1"). Files without a section
+// extension yield their base name and "".
+func ManPageName(path string) (string, string) {
+	base := strings.TrimSuffix(filepath.Base(path), ".gz")
+	ext := filepath.Ext(base)
+	if manSectionExtRe.MatchString(ext) {
+		return strings.TrimSuffix(base, ext), ext[1:]
+	}
+	return base, ""
+}
+
+// manPage is a man page rendered line by line.
+type manPage struct {
+	name, section string
+	lines         []string
+	info          blockInfo
+}
+
+// manRenderer renders macro and text lines, tracking option entries.
+type manRenderer struct {
+	page *manPage
+
+	tagNext bool // The next text line is a .TP tag
+	bullet  bool // The next text line starts a bulleted .IP item
+	row     []string
+	rowLine int
+}
+
+// renderMan renders man(7) source to one plain-text line per source line and
+// finds its sections, option entries and no-fill (example) blocks.
+func renderMan(src []string) *manPage {
+	r := &manRenderer{page: &manPage{lines: make([]string, len(src)), info: newBlockInfo()}}
+	info := r.page.info
+
+	for i := 0; i < len(src); i++ {
+		ln := i + 1
+		line := strings.TrimRight(src[i], "\r")
+		if !isManRequest(line) {
+			r.text(ln, manInline(line))
+			continue
+		}
+
+		macro, args := splitManRequest(line)
+		switch macro {
+		case "TH":
+			if len(args) > 0 {
+				r.page.name = args[0]
+				if r.page.name == strings.ToUpper(r.page.name) {
+					r.page.name = strings.ToLower(r.page.name)
+				}
+			}
+			if len(args) > 1 {
+				r.page.section = args[1]
+			}
+		case "SH", "SS":
+			r.endEntry()
+			level, end := 1, ln
+			if macro == "SS" {
+				level = 2
+			}
+			title := manInline(strings.Join(args, " "))
+			if title == "" && i+1 < len(src) {
+				// Old style: the title is on the next line
+				i++
+				end = i + 1
+				title = manInline(src[i])
+			}
+			if title != "" {
+				info.headings[ln] = headingInfo{level: level, title: title, endLine: end}
+				r.page.lines[end-1] = title
+			}
+		case "TP", "TQ":
+			r.endEntry()
+			r.tagNext = true
+		case "IP":
+			r.endEntry()
+			tag := ""
+			if len(args) > 0 {
+				tag = manInline(args[0])
+			}
+			switch tag {
+			case "", "•", "-", "*", "o":
+				r.bullet = tag != ""
+			default:
+				r.startEntry(ln, tag)
+			}
+		case "PP", "P", "LP", "HP":
+			r.endEntry()
+		case "nf", "EX":
+			i = r.noFill(src, i)
+		case "de", "ig", "am":
+			// Macro definitions and ignored blocks run to ".."
+			for i+1 < len(src) && strings.TrimSpace(src[i+1]) != ".." {
+				i++
+			}
+			i++
+		case "UR", "MT":
+			if len(args) > 0 {
+				r.text(ln, args[0])
+			}
+		default:
+			if text := manFontMacro(macro, args); text != "" {
+				r.text(ln, text)
+			}
+		}
+	}
+	r.endEntry()
+	return r.page
+}
+
+// text renders a line of running text, which may be a pending tag or the
+// first line of a bulleted item.
+func (r *manRenderer) text(ln int, s string) {
+	switch {
+	case s == "":
+	case r.tagNext:
+		r.tagNext = false
+		r.startEntry(ln, s)
+		return
+	case r.bullet:
+		r.bullet = false
+		s = "- " + s
+	case r.row != nil:
+		r.row[1] = strings.TrimSpace(r.row[1] + " " + s)
+	}
+	r.page.lines[ln-1] = s
+}
+
+// startEntry renders an option tag and starts collecting its description.
+func (r *manRenderer) startEntry(ln int, tag string) {
+	r.endEntry()
+	r.page.lines[ln-1] = "`" + tag + "`"
+	r.row = []string{tag, ""}
+	r.rowLine = ln
+}
+
+// endEntry saves the current option entry as a table row.
+func (r *manRenderer) endEntry() {
+	if r.row != nil {
+		r.page.info.rows[r.rowLine] = domain.TableRow{Cells: r.row, Line: r.rowLine}
+	}
+	r.row = nil
+	r.tagNext = false
+	r.bullet = false
+}
+
+// noFill renders the .nf/.EX block starting at line index i as a fenced code
+// block and returns the index of its closing .fi/.EE.
+func (r *manRenderer) noFill(src []string, i int) int {
+	r.endEntry()
+	start := i
+	var code []string
+	for i++; i < len(src); i++ {
+		line := strings.TrimRight(src[i], "\r")
+		if isManRequest(line) {
+			macro, args := splitManRequest(line)
+			if macro == "fi" || macro == "EE" {
+				break
+			}
+			line = manFontMacro(macro, args)
+		} else {
+			line = manInline(line)
+		}
+		r.page.lines[i] = line
+		code = append(code, line)
+	}
+	end := i
+	if end == len(src) {
+		end-- // Unterminated: the block runs to the end of the page
+	} else {
+		r.page.lines[end] = "```"
+	}
+	r.page.lines[start] = "```"
+	r.page.info.code[start+1] = domain.CodeBlock{Code: strings.Join(code, "\n"), Line: start + 1}
+	r.page.info.protect(start+1, end+1)
+	return end
+}
+
+// isManRequest reports whether a line is a control line (".B", "'\"").
+func isManRequest(line string) bool {
+	return strings.HasPrefix(line, ".") || strings.HasPrefix(line, `'\"`)
+}
+
+// splitManRequest splits a control line into its macro name and arguments;
+// double-quoted arguments may contain spaces. Comments yield no macro.
+func splitManRequest(line string) (string, []string) {
+	line = strings.TrimSpace(line[1:])
+	if strings.HasPrefix(line, `\"`) || line == "" {
+		return "", nil
+	}
+	macro, rest, _ := strings.Cut(line, " ")
+
+	var args []string
+	rest = stripManComment(rest)
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		if rest[0] == '"' {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				args = append(args, rest[1:])
+				break
+			}
+			args = append(args, rest[1:end+1])
+			rest = rest[end+2:]
+			continue
+		}
+		arg, next, _ := strings.Cut(rest, " ")
+		args = append(args, arg)
+		rest = next
+	}
+	return macro, args
+}
+
+// manFontMacro renders the font macros (.B, .I, .BR, ...) and returns ""
+// for any other request.
+func manFontMacro(macro string, args []string) string {
+	switch macro {
+	case "B", "I", "SM", "SB":
+		return manInline(strings.Join(args, " "))
+	case "BR", "BI", "IB", "IR", "RB", "RI":
+		// Alternating fonts: the arguments are joined without spaces
+		return manInline(strings.Join(args, ""))
+	}
+	return ""
+}
+
+// manChars maps troff special characters to text.
+var manChars = map[string]string{
+	`\-`: "-", `\e`: `\`, `\\`: `\`, `\.`: ".", `\'`: "'", "\\`": "`",
+	`\ `: " ", `\~`: " ", `\0`: " ",
+	`\(em`: "—", `\[em]`: "—", `\(en`: "–", `\[en]`: "–", `\(bu`: "•", `\[bu]`: "•",
+	`\(aq`: "'", `\[aq]`: "'", `\(dq`: `"`, `\[dq]`: `"`, `\(lq`: `"`, `\(rq`: `"`,
+	`\(oq`: "'", `\(cq`: "'", `\(co`: "©", `\(rs`: `\`, `\(ti`: "~", `\(ha`: "^",
+	`\(mi`: "-", `\(hy`: "-", `\(da`: "↓", `\(ua`: "↑", `\(->`: "→", `\(<-`: "←",
+}
+
+// manInline resolves the escapes in a line of text; fonts, sizes and unknown
+// escapes are dropped.
+func manInline(s string) string {
+	s = stripManComment(s)
+	s = manEscapeRe.ReplaceAllStringFunc(s, func(esc string) string {
+		return manChars[esc]
+	})
+	return strings.TrimRight(strings.TrimSuffix(s, `\`), " \t")
+}
+
+// stripManComment removes a trailing \" comment.
+func stripManComment(s string) string {
+	for i := 0; i+1 < len(s); i++ {
+		if s[i] != '\\' {
+			continue
+		}
+		if s[i+1] == '"' {
+			return s[:i]
+		}
+		i++ // Skip the escaped character (e.g. "\\")
+	}
+	return s
+}
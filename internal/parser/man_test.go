@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const natsStreamMan = `.\" Generated by hand
+.TH NATS-STREAM 1 "2024-01-01" "nats 0.1" "NATS Manual"
+.SH NAME
+nats\-stream \- manage JetStream streams
+.SH OPTIONS
+.TP
+\fB\-\-purge\fR \fIsubject\fR
+Purge all messages of
+.I subject
+from the stream.
+.TP
+.B \-\-force
+Skip the confirmation prompt.
+.SH EXAMPLES
+.PP
+Purge one subject:
+.nf
+nats stream purge ORDERS \-\-purge orders.new
+.fi
+`
+
+func TestManParser_SectionsOptionsExamples(t *testing.T) {
+	p := NewManParser()
+	p.MinLinesPerChunk = 1
+	chunks, _ := p.Parse("/usr/share/man/man1/nats-stream.1", natsStreamMan)
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+
+	opts := chunks[1]
+	if got := strings.Join(opts.HeadingPath, " › "); got != "nats-stream(1) › OPTIONS" {
+		t.Errorf("heading path = %q", got)
+	}
+	if opts.StartLine != 5 || opts.EndLine != 13 {
+		t.Errorf("options lines = %d-%d, want 5-13", opts.StartLine, opts.EndLine)
+	}
+	if !strings.Contains(opts.Text, "`--purge subject`\nPurge all messages of\nsubject\nfrom the stream.") {
+		t.Errorf("options text:\n%s", opts.Text)
+	}
+	if len(opts.TableRows) != 2 {
+		t.Fatalf("rows = %+v", opts.TableRows)
+	}
+	if row := opts.TableRows[0]; strings.Join(row.Cells, "|") != "--purge subject|Purge all messages of subject from the stream." || row.Line != 7 {
+		t.Errorf("purge row = %+v", row)
+	}
+	if row := opts.TableRows[1]; row.Cells[0] != "--force" || row.Line != 12 {
+		t.Errorf("force row = %+v", row)
+	}
+
+	examples := chunks[2]
+	if len(examples.CodeBlocks) != 1 || examples.CodeBlocks[0].Code != "nats stream purge ORDERS --purge orders.new" || examples.CodeBlocks[0].Line != 17 {
+		t.Errorf("code blocks = %+v", examples.CodeBlocks)
+	}
+	if examples.Metadata["title"] != "nats-stream(1)" || examples.Metadata["section"] != "1" {
+		t.Errorf("metadata = %v", examples.Metadata)
+	}
+	if strings.Contains(chunks[0].Text, "Generated") || strings.Contains(chunks[0].Text, ".TH") {
+		t.Errorf("comments and requests should not be indexed:\n%s", chunks[0].Text)
+	}
+}
+
+func TestManPageName(t *testing.T) {
+	tests := []struct{ path, name, section string }{
+		{"/usr/share/man/man1/nats-stream.1.gz", "nats-stream", "1"},
+		{"perl.3pm", "perl", "3pm"},
+		{"node.js", "node.js", ""},
+	}
+	for _, tt := range tests {
+		if name, section := ManPageName(tt.path); name != tt.name || section != tt.section {
+			t.Errorf("ManPageName(%q) = %q, %q", tt.path, name, section)
+		}
+	}
+}
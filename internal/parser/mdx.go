@@ -0,0 +1,340 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// MDXParser indexes MDX documents (Docusaurus, Nextra) by rewriting them to
+// plain markdown and handing the result to a markdown parser:
+//   - import/export statements and {/* comments */} are removed
+//   - <Tabs>/<TabItem> and Nextra <Tab> become headings like "Tab: Go"
+//   - admonitions (<Admonition type="warning">, <Callout>, :::tip) become a
+//     "**Warning:** title" line followed by their content
+//   - other components are unwrapped, keeping their children
+//
+// The rewrite keeps one output line per input line, so line numbers and
+// source links still point into the original .mdx file.
+type MDXParser struct {
+	// Markdown parses the rewritten document
+	Markdown Parser
+}
+
+// NewMDXParser creates an MDX parser on top of a markdown parser.
+func NewMDXParser(markdown Parser) *MDXParser {
+	return &MDXParser{Markdown: markdown}
+}
+
+// Parse rewrites MDX to markdown and parses it.
+func (p *MDXParser) Parse(path, content string) ([]domain.Chunk, map[string]int) {
+	return p.Markdown.Parse(path, MDXToMarkdown(content))
+}
+
+// MDXToMarkdown rewrites MDX into markdown line by line (see MDXParser).
+func MDXToMarkdown(content string) string {
+	lines := strings.Split(content, "\n")
+	skip := ParseFrontMatter(content).Lines
+
+	r := &mdxRewriter{lines: lines, out: make([]string, len(lines))}
+	copy(r.out[:skip], lines[:skip])
+	for i := skip; i < len(lines); {
+		i = r.rewrite(i)
+	}
+	return strings.Join(r.out, "\n")
+}
+
+var (
+	// esmRe matches the start of an import or export statement.
+	esmRe = regexp.MustCompile(`^(import|export)[\s{*]`)
+
+	// jsxOpenRe and jsxCloseRe match component tags (capitalised, unlike HTML).
+	jsxOpenRe  = regexp.MustCompile(`^<([A-Z][\w.]*)`)
+	jsxCloseRe = regexp.MustCompile(`^</([A-Z][\w.]*)\s*>`)
+
+	// jsxAttrRe matches name="v", name='v' and name={expr} attributes.
+	jsxAttrRe = regexp.MustCompile(`(\w+)=(?:"([^"]*)"|'([^']*)'|\{([^}]*)\})`)
+
+	// quotedRe matches string literals, e.g. in items={['Go', 'Rust']}.
+	quotedRe = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+	// directiveRe matches Docusaurus admonitions: ":::tip", ":::note[Title]", ":::info Title".
+	directiveRe = regexp.MustCompile(`^:::(\w+)(?:\[(.*)\]|\s+(.*))?$`)
+)
+
+// admonitions are components rendered as callouts; the value is the default
+// type when the component has no type attribute.
+var admonitions = map[string]string{
+	"Admonition": "note",
+	"Callout":    "note",
+	"Note":       "note",
+	"Tip":        "tip",
+	"Info":       "info",
+	"Warning":    "warning",
+	"Caution":    "caution",
+	"Danger":     "danger",
+}
+
+// mdxComponent is an open JSX element being unwrapped.
+type mdxComponent struct {
+	name   string
+	indent int      // indentation of its children (-1 until seen)
+	items  []string // Nextra <Tabs items={[...]}> labels for its <Tab>s
+	next   int      // index of the next unlabelled <Tab>
+}
+
+// mdxRewriter holds state while rewriting MDX line by line.
+type mdxRewriter struct {
+	lines []string
+	out   []string
+
+	open         []*mdxComponent
+	headingLevel int // level of the last markdown heading outside tabs
+	inTab        bool
+	fence        byte // fence character while inside a code block
+	fenceLen     int
+}
+
+// rewrite converts the line at i (and any continuation lines) and returns
+// the index of the next line to process.
+func (r *mdxRewriter) rewrite(i int) int {
+	line := r.dedent(r.lines[i])
+	trimmed := strings.TrimSpace(line)
+
+	// Code is copied as-is, even if it looks like JSX
+	if r.fence != 0 {
+		if c, n := fenceMarker(line); c == r.fence && n >= r.fenceLen && isBareFence(line) {
+			r.fence = 0
+		}
+		r.out[i] = line
+		return i + 1
+	}
+	if c, n := fenceMarker(line); n > 0 {
+		r.fence, r.fenceLen = c, n
+		r.out[i] = line
+		return i + 1
+	}
+
+	switch {
+	case len(r.open) == 0 && esmRe.MatchString(line):
+		return r.skipESM(i)
+	case strings.HasPrefix(trimmed, "{/*"):
+		return r.skipUntil(i, "*/}")
+	case strings.HasPrefix(trimmed, ":::"):
+		if m := directiveRe.FindStringSubmatch(trimmed); m != nil {
+			r.out[i] = admonitionLabel(m[1], m[2]+m[3])
+		}
+		return i + 1
+	case jsxCloseRe.MatchString(trimmed):
+		r.close(jsxCloseRe.FindStringSubmatch(trimmed)[1])
+		return i + 1
+	case jsxOpenRe.MatchString(trimmed):
+		return r.openTag(i, trimmed)
+	}
+
+	if level := headingLevel(trimmed); level > 0 && !r.inTab {
+		r.headingLevel = level
+	}
+	r.out[i] = line
+	return i + 1
+}
+
+// dedent strips the indentation of the innermost open component's children,
+// since MDX has no indented code blocks and nested content is often indented.
+func (r *mdxRewriter) dedent(line string) string {
+	if len(r.open) == 0 || strings.TrimSpace(line) == "" {
+		return line
+	}
+	lead := len(line) - len(strings.TrimLeft(line, " \t"))
+	c := r.open[len(r.open)-1]
+	if c.indent < 0 {
+		c.indent = lead
+	}
+	return line[min(lead, c.indent):]
+}
+
+// skipESM blanks an import/export statement, which may span several lines.
+// Like any MDX block it ends at a blank line at the latest, so a stray prose
+// line starting with "import" can't swallow the rest of the document.
+func (r *mdxRewriter) skipESM(i int) int {
+	isImport := strings.HasPrefix(r.lines[i], "import")
+	depth := 0
+	for ; i < len(r.lines); i++ {
+		line := r.lines[i]
+		if strings.TrimSpace(line) == "" {
+			return i
+		}
+		depth += strings.Count(line, "{") + strings.Count(line, "(") + strings.Count(line, "[")
+		depth -= strings.Count(line, "}") + strings.Count(line, ")") + strings.Count(line, "]")
+		if depth > 0 {
+			continue
+		}
+		// "import {\n a,\n} from 'x'" isn't finished until the module specifier
+		if isImport && !strings.ContainsAny(line, `"'`) && !strings.HasSuffix(strings.TrimSpace(line), ";") && i+1 < len(r.lines) {
+			continue
+		}
+		return i + 1
+	}
+	return i
+}
+
+// skipUntil blanks lines up to and including the one containing end.
+func (r *mdxRewriter) skipUntil(i int, end string) int {
+	for ; i < len(r.lines); i++ {
+		if strings.Contains(r.lines[i], end) {
+			return i + 1
+		}
+	}
+	return i
+}
+
+// openTag handles a component's opening tag, which may span several lines.
+func (r *mdxRewriter) openTag(i int, trimmed string) int {
+	tag, rest, last := r.readTag(i, trimmed)
+	name := jsxOpenRe.FindStringSubmatch(tag)[1]
+	attrs := parseJSXAttrs(tag)
+	selfClosing := strings.HasSuffix(tag, "/>")
+
+	c := &mdxComponent{name: name, indent: -1}
+	label := ""
+	switch {
+	case name == "Tabs":
+		if items, ok := attrs["items"]; ok {
+			for _, m := range quotedRe.FindAllStringSubmatch(items, -1) {
+				c.items = append(c.items, m[1]+m[2])
+			}
+		}
+	case name == "TabItem" || name == "Tab":
+		label = r.tabHeading(attrs)
+		r.inTab = !selfClosing
+	case admonitions[name] != "":
+		kind := attrs["type"]
+		if kind == "" {
+			kind = admonitions[name]
+		}
+		label = admonitionLabel(kind, attrs["title"])
+	}
+	if !selfClosing {
+		r.open = append(r.open, c)
+	}
+
+	// Content after the tag on the same line, e.g. <TabItem value="go">text</TabItem>
+	rest = strings.TrimSpace(rest)
+	if closing := "</" + name + ">"; !selfClosing && strings.HasSuffix(rest, closing) {
+		rest = strings.TrimSuffix(rest, closing)
+		r.close(name)
+	}
+	r.out[i] = strings.TrimSpace(label + " " + rest)
+	return last + 1
+}
+
+// readTag collects a tag up to its closing '>', skipping quoted strings and
+// {expressions}. It returns the tag, any text after it on the final line,
+// and the index of that line.
+func (r *mdxRewriter) readTag(i int, first string) (tag, rest string, last int) {
+	var sb strings.Builder
+	depth := 0
+	var quote byte
+	line := first
+	for j := i; j < len(r.lines); j++ {
+		if j > i {
+			line = strings.TrimSpace(r.lines[j])
+			sb.WriteByte(' ')
+		}
+		for k := 0; k < len(line); k++ {
+			ch := line[k]
+			sb.WriteByte(ch)
+			switch {
+			case quote != 0:
+				if ch == quote {
+					quote = 0
+				}
+			case ch == '"' || ch == '\'' || ch == '`':
+				quote = ch
+			case ch == '{':
+				depth++
+			case ch == '}':
+				depth--
+			case ch == '>' && depth == 0:
+				return sb.String(), line[k+1:], j
+			}
+		}
+	}
+	// Unterminated tag: treat the first line as the whole tag
+	return first, "", i
+}
+
+// tabHeading returns a markdown heading for a tab, one level below the
+// section containing the tabs.
+func (r *mdxRewriter) tabHeading(attrs map[string]string) string {
+	label := attrs["label"]
+	if label == "" {
+		label = attrs["value"]
+	}
+	if label == "" {
+		// Nextra: labels come from the enclosing <Tabs items={[...]}>
+		for k := len(r.open) - 1; k >= 0; k-- {
+			if tabs := r.open[k]; tabs.name == "Tabs" {
+				if tabs.next < len(tabs.items) {
+					label = tabs.items[tabs.next]
+				}
+				tabs.next++
+				break
+			}
+		}
+	}
+	if label == "" {
+		label = "Tab"
+	} else {
+		label = "Tab: " + label
+	}
+	level := min(r.headingLevel+1, 6)
+	return strings.Repeat("#", level) + " " + label
+}
+
+// close pops the named component and anything left open inside it.
+func (r *mdxRewriter) close(name string) {
+	for k := len(r.open) - 1; k >= 0; k-- {
+		if r.open[k].name == name {
+			r.open = r.open[:k]
+			break
+		}
+	}
+	r.inTab = false
+	for _, c := range r.open {
+		if c.name == "TabItem" || c.name == "Tab" {
+			r.inTab = true
+		}
+	}
+}
+
+// parseJSXAttrs extracts string and {expression} attribute values.
+func parseJSXAttrs(tag string) map[string]string {
+	attrs := make(map[string]string)
+	for _, m := range jsxAttrRe.FindAllStringSubmatch(tag, -1) {
+		attrs[m[1]] = m[2] + m[3] + m[4]
+	}
+	return attrs
+}
+
+// admonitionLabel renders an admonition header, e.g. "**Warning:** Breaking change".
+func admonitionLabel(kind, title string) string {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if kind == "" {
+		kind = "note"
+	}
+	label := "**" + strings.ToUpper(kind[:1]) + kind[1:] + ":**"
+	if title = strings.TrimSpace(title); title != "" {
+		label += " " + title
+	}
+	return label
+}
+
+// headingLevel returns the level of an ATX heading line, or 0.
+func headingLevel(line string) int {
+	if m := headingRe.FindStringSubmatch(line); m != nil {
+		return len(m[1])
+	}
+	return 0
+}
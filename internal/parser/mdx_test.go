@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMDXToMarkdown(t *testing.T) {
+	content := strings.Join([]string{
+		"---",
+		"title: Install",
+		"---",
+		"import Tabs from '@theme/Tabs';",
+		"import {",
+		"  TabItem,",
+		"} from '@theme/TabItem';",
+		"export const meta = {",
+		"  sidebar: true,",
+		"};",
+		"",
+		"## Install",
+		"",
+		"{/* hidden note */}",
+		"<Tabs groupId=\"lang\">",
+		"  <TabItem value=\"go\" label=\"Go\">",
+		"    ```go",
+		"    <Tabs> stays in code",
+		"    ```",
+		"  </TabItem>",
+		"  <TabItem",
+		"    value=\"rust\">",
+		"    Run cargo add.",
+		"  </TabItem>",
+		"</Tabs>",
+		"",
+		"<Admonition type=\"warning\" title=\"Breaking\">",
+		"Versions before 2.0 are unsupported.",
+		"</Admonition>",
+		"",
+		":::tip[Faster]",
+		"Use the cache.",
+		":::",
+	}, "\n")
+
+	want := strings.Join([]string{
+		"---",
+		"title: Install",
+		"---",
+		"", "", "", "", "", "", "",
+		"",
+		"## Install",
+		"",
+		"",
+		"",
+		"### Tab: Go",
+		"```go",
+		"<Tabs> stays in code",
+		"```",
+		"",
+		"### Tab: rust",
+		"",
+		"Run cargo add.",
+		"",
+		"",
+		"",
+		"**Warning:** Breaking",
+		"Versions before 2.0 are unsupported.",
+		"",
+		"",
+		"**Tip:** Faster",
+		"Use the cache.",
+		"",
+	}, "\n")
+
+	if got := MDXToMarkdown(content); got != want {
+		t.Errorf("MDXToMarkdown mismatch:\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
+
+func TestMDXToMarkdown_StrayImportLine(t *testing.T) {
+	// Not a valid import: it must not swallow the rest of the document
+	content := "import is a keyword in many languages\n\n## Modules\n\nGo imports packages."
+	got := MDXToMarkdown(content)
+	if !strings.Contains(got, "## Modules") || !strings.Contains(got, "Go imports packages.") {
+		t.Errorf("content after the import line was dropped:\n%s", got)
+	}
+}
+
+func TestMDXParser_NextraTabsAndLineNumbers(t *testing.T) {
+	content := strings.Join([]string{
+		"import { Tabs } from 'nextra/components'",
+		"",
+		"# Setup",
+		"",
+		"<Tabs items={['npm', 'pnpm']}>",
+		"<Tab>",
+		"npm install nats",
+		"</Tab>",
+		"<Tab>",
+		"pnpm add nats",
+		"</Tab>",
+		"</Tabs>",
+	}, "\n")
+
+	md := NewCommonMarkParser()
+	md.MinLinesPerChunk = 1
+	chunks, _ := NewMDXParser(md).Parse("doc.mdx", content)
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %+v", chunks)
+	}
+	last := chunks[2]
+	if got := strings.Join(last.HeadingPath, " > "); got != "Setup > Tab: pnpm" {
+		t.Errorf("heading path = %q", got)
+	}
+	if last.StartLine != 9 || !strings.Contains(last.Text, "pnpm add nats") {
+		t.Errorf("chunk = %d %q", last.StartLine, last.Text)
+	}
+	if strings.Contains(chunks[0].Text, "import") {
+		t.Errorf("import should be stripped: %q", chunks[0].Text)
+	}
+}
@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// NotebookParser indexes Jupyter notebooks (.ipynb) by rendering their cells
+// as one markdown document and handing it to a markdown parser:
+//   - markdown cells are kept as-is, so their headings drive the heading path
+//   - code cells become fenced code blocks in the kernel's language
+//   - with Outputs set, text outputs (streams, results, errors) follow their
+//     cell as quoted blocks
+//
+// Line numbers are mapped back to the notebook's JSON, so a source link
+// points at the lines of the cells an excerpt came from.
+type NotebookParser struct {
+	// Markdown parses the rendered document
+	Markdown Parser
+
+	// Outputs includes text outputs of code cells
+	Outputs bool
+}
+
+// NewNotebookParser creates a notebook parser on top of a markdown parser.
+// Outputs are left out.
+func NewNotebookParser(markdown Parser) *NotebookParser {
+	return &NotebookParser{Markdown: markdown}
+}
+
+// Parse renders a notebook and parses it. Content that isn't a notebook
+// yields no chunks.
+func (p *NotebookParser) Parse(path, content string) ([]domain.Chunk, map[string]int) {
+	nb, ok := RenderNotebook(content, p.Outputs)
+	if !ok {
+		return nil, map[string]int{}
+	}
+
+	chunks, docFreq := p.Markdown.Parse(path, nb.Markdown)
+	meta := map[string]string{"format": "jupyter", "language": nb.Language}
+	docID := DocIDForPath(path)
+	seen := make(map[string]bool, len(chunks))
+	for i := range chunks {
+		c := &chunks[i]
+		virtual := c.StartLine
+		c.StartLine, c.EndLine = nb.line(c.StartLine), nb.line(c.EndLine)
+		for j := range c.CodeBlocks {
+			c.CodeBlocks[j].Line = nb.line(c.CodeBlocks[j].Line)
+		}
+		for j := range c.TableRows {
+			c.TableRows[j].Line = nb.line(c.TableRows[j].Line)
+		}
+
+		// Sections within a single-string cell map to the same lines
+		c.ChunkID = fmt.Sprintf("%s:%d-%d", docID, c.StartLine, c.EndLine)
+		if seen[c.ChunkID] {
+			c.ChunkID += fmt.Sprintf(":%d", virtual)
+		}
+		seen[c.ChunkID] = true
+		c.Metadata = meta
+	}
+	return chunks, docFreq
+}
+
+// Notebook is a notebook rendered as markdown.
+type Notebook struct {
+	Markdown string
+	Language string // Kernel language, e.g. "python"
+	Lines    []int  // Notebook line of each markdown line
+}
+
+// line maps a 1-indexed markdown line to its notebook line.
+func (nb *Notebook) line(n int) int {
+	if n >= 1 && n <= len(nb.Lines) {
+		return nb.Lines[n-1]
+	}
+	return n
+}
+
+// RenderNotebook renders a notebook's cells as markdown (see NotebookParser),
+// reporting false if content isn't a notebook.
+func RenderNotebook(content string, outputs bool) (*Notebook, bool) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil || len(doc.Content) == 0 {
+		return nil, false
+	}
+	root := (*specNode)(doc.Content[0])
+	cells := root.get("cells")
+	if cells == nil || cells.Kind != yaml.SequenceNode {
+		return nil, false
+	}
+
+	r := &notebookRenderer{nb: &Notebook{Language: "python"}}
+	if lang := root.get("metadata", "kernelspec", "language").scalar(); lang != "" {
+		r.nb.Language = lang
+	} else if lang := root.get("metadata", "language_info", "name").scalar(); lang != "" {
+		r.nb.Language = lang
+	}
+
+	for _, cell := range cells.items() {
+		source := cell.get("source")
+		switch cell.get("cell_type").scalar() {
+		case "markdown":
+			r.text(source, "")
+		case "code":
+			lines := notebookText(source)
+			if len(lines) == 0 {
+				continue
+			}
+			fence := codeFence(lines)
+			r.emit(fence+r.nb.Language, cell.Line)
+			r.text(source, "")
+			r.emit(fence, r.last)
+			if outputs {
+				r.outputs(cell.get("outputs"))
+			}
+		default: // raw
+			continue
+		}
+		r.emit("", r.last)
+	}
+
+	r.nb.Markdown = strings.Join(r.out, "\n")
+	return r.nb, true
+}
+
+// notebookRenderer builds the markdown and its line mapping.
+type notebookRenderer struct {
+	nb   *Notebook
+	out  []string
+	last int // Notebook line of the last emitted line
+}
+
+// emit appends a markdown line that came from notebook line n.
+func (r *notebookRenderer) emit(s string, n int) {
+	r.out = append(r.out, s)
+	r.nb.Lines = append(r.nb.Lines, n)
+	r.last = n
+}
+
+// text emits a multiline string field, each line prefixed with prefix.
+func (r *notebookRenderer) text(n *specNode, prefix string) {
+	for _, l := range notebookText(n) {
+		r.emit(prefix+l.text, l.line)
+	}
+}
+
+// outputs emits a code cell's text outputs as quoted blocks.
+func (r *notebookRenderer) outputs(outputs *specNode) {
+	for _, out := range outputs.items() {
+		var text *specNode
+		switch out.get("output_type").scalar() {
+		case "stream":
+			text = out.get("text")
+		case "execute_result", "display_data":
+			text = out.get("data", "text/plain")
+		case "error":
+			r.emit("", out.Line)
+			r.emit("> "+out.get("ename").scalar()+": "+out.get("evalue").scalar(), out.Line)
+			continue
+		}
+		lines := notebookText(text)
+		if len(lines) == 0 {
+			continue
+		}
+		r.emit("", out.Line)
+		for _, l := range lines {
+			r.emit("> "+quoteSafe(l.text), l.line)
+		}
+	}
+}
+
+// notebookLine is one line of a cell's text and the notebook line it's on.
+type notebookLine struct {
+	text string
+	line int
+}
+
+// codeFence returns a backtick fence longer than any backtick run in lines,
+// so code that builds markdown can't close its own block.
+func codeFence(lines []notebookLine) string {
+	longest := 0
+	for _, l := range lines {
+		run := 0
+		for _, c := range l.text {
+			if c != '`' {
+				run = 0
+				continue
+			}
+			run++
+			longest = max(longest, run)
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// notebookText splits a text field, stored either as a list of lines or a
+// single string, into lines.
+func notebookText(n *specNode) []notebookLine {
+	if n == nil {
+		return nil
+	}
+	parts := n.items()
+	if n.Kind == yaml.ScalarNode {
+		parts = []*specNode{n}
+	}
+
+	var lines []notebookLine
+	for _, part := range parts {
+		for _, l := range strings.Split(strings.TrimSuffix(part.Value, "\n"), "\n") {
+			lines = append(lines, notebookLine{text: strings.TrimRight(l, "\r"), line: part.Line})
+		}
+	}
+	// Drop trailing blank lines so cells don't end in empty fences
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1].text) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// quoteSafe escapes a leading character that would otherwise turn an output
+// line into a heading, fence or setext underline inside its quote.
+func quoteSafe(s string) string {
+	trimmed := strings.TrimLeft(s, " ")
+	if trimmed != "" && strings.ContainsRune("#=-`~", rune(trimmed[0])) {
+		return `\` + trimmed
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const runbookNotebook = `{
+ "cells": [
+  {
+   "cell_type": "markdown",
+   "metadata": {},
+   "source": [
+    "# Runbook\n",
+    "\n",
+    "Restart the consumer when lag grows."
+   ]
+  },
+  {
+   "cell_type": "code",
+   "execution_count": 1,
+   "metadata": {},
+   "outputs": [
+    {
+     "name": "stdout",
+     "output_type": "stream",
+     "text": [
+      "# lag: 42\n"
+     ]
+    }
+   ],
+   "source": [
+    "lag = consumer.lag()\n",
+    "print(f'# lag: {lag}')"
+   ]
+  },
+  {
+   "cell_type": "markdown",
+   "metadata": {},
+   "source": "## Rollback\n\nScale the deployment back down."
+  }
+ ],
+ "metadata": {
+  "kernelspec": {"display_name": "Python 3", "language": "python", "name": "python3"}
+ },
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+
+func TestNotebookParser_CellsAndLines(t *testing.T) {
+	md := NewCommonMarkParser()
+	md.MinLinesPerChunk = 1
+	chunks, _ := NewNotebookParser(md).Parse("runbook.ipynb", runbookNotebook)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d: %+v", len(chunks), chunks)
+	}
+
+	first := chunks[0]
+	if first.StartLine != 7 || first.EndLine != 27 {
+		t.Errorf("first chunk lines = %d-%d, want 7-27", first.StartLine, first.EndLine)
+	}
+	if len(first.CodeBlocks) != 1 {
+		t.Fatalf("code blocks = %+v", first.CodeBlocks)
+	}
+	if cb := first.CodeBlocks[0]; cb.Language != "python" || cb.Line != 12 || !strings.Contains(cb.Code, "lag = consumer.lag()") {
+		t.Errorf("code block = %+v", cb)
+	}
+	if strings.Contains(first.Text, "> ") {
+		t.Errorf("outputs should be left out by default:\n%s", first.Text)
+	}
+
+	rollback := chunks[1]
+	if got := strings.Join(rollback.HeadingPath, " › "); got != "Runbook › Rollback" {
+		t.Errorf("heading path = %q", got)
+	}
+	if rollback.StartLine != 33 || rollback.EndLine != 33 {
+		t.Errorf("rollback lines = %d-%d, want 33-33", rollback.StartLine, rollback.EndLine)
+	}
+	if rollback.Metadata["format"] != "jupyter" || rollback.Metadata["language"] != "python" {
+		t.Errorf("metadata = %v", rollback.Metadata)
+	}
+}
+
+func TestNotebookParser_Outputs(t *testing.T) {
+	p := &NotebookParser{Markdown: NewCommonMarkParser(), Outputs: true}
+	chunks, _ := p.Parse("runbook.ipynb", runbookNotebook)
+	if len(chunks) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(chunks))
+	}
+	if !strings.Contains(chunks[0].Text, `> \# lag: 42`) {
+		t.Errorf("output not quoted:\n%s", chunks[0].Text)
+	}
+	if len(chunks[0].CodeBlocks) != 1 {
+		t.Errorf("outputs must not become code blocks: %+v", chunks[0].CodeBlocks)
+	}
+}
+
+func TestNotebookParser_CodeWithFence(t *testing.T) {
+	// The cell builds markdown: its own fence must not close the code block
+	nb := `{"cells": [{"cell_type": "code", "metadata": {}, "outputs": [], "source": [
+  "report = \"\"\"\n",
+  "` + "```" + `\n",
+  "# Not a heading\n",
+  "` + "```" + `\n",
+  "\"\"\"\n"
+ ]}], "metadata": {}}`
+	md := NewCommonMarkParser()
+	md.MinLinesPerChunk = 1
+	chunks, _ := NewNotebookParser(md).Parse("report.ipynb", nb)
+	if len(chunks) != 1 || len(chunks[0].CodeBlocks) != 1 {
+		t.Fatalf("expected one chunk with one code block, got %+v", chunks)
+	}
+	if code := chunks[0].CodeBlocks[0].Code; !strings.Contains(code, "# Not a heading") {
+		t.Errorf("code block = %q, want the whole cell", code)
+	}
+	if len(chunks[0].HeadingPath) != 0 {
+		t.Errorf("code became a heading: %v", chunks[0].HeadingPath)
+	}
+}
+
+func TestNotebookParser_NotANotebook(t *testing.T) {
+	if chunks, _ := NewNotebookParser(NewCommonMarkParser()).Parse("x.ipynb", "# just markdown"); len(chunks) != 0 {
+		t.Errorf("expected no chunks, got %d", len(chunks))
+	}
+}
@@ -0,0 +1,518 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// OpenAPIParser turns an OpenAPI 3 or Swagger 2 specification (YAML or JSON)
+// into chunks: one per operation, with its summary, parameters, request and
+// response schemas and examples, and one per component schema, with its
+// fields as table rows. Heading paths look like "API › tag › POST /consumers"
+// and line ranges point into the spec.
+type OpenAPIParser struct{}
+
+// NewOpenAPIParser creates an OpenAPI parser.
+func NewOpenAPIParser() *OpenAPIParser {
+	return &OpenAPIParser{}
+}
+
+// httpMethods are the operation keys of a path item, in display order.
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openAPIRe matches the top-level version key of a spec.
+var openAPIRe = regexp.MustCompile(`(?m)^(?:openapi|swagger):|^\s*\{?\s*"(?:openapi|swagger)"\s*:`)
+
+// looksLikeOpenAPI reports whether content is an OpenAPI or Swagger spec.
+func looksLikeOpenAPI(content string) bool {
+	return openAPIRe.MatchString(content)
+}
+
+// Parse splits a specification into operation and schema chunks. Content
+// that isn't a valid spec yields no chunks.
+func (p *OpenAPIParser) Parse(path, content string) ([]domain.Chunk, map[string]int) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil || len(doc.Content) == 0 {
+		return nil, map[string]int{}
+	}
+
+	s := &specBuilder{docID: DocIDForPath(path), path: path, root: (*specNode)(doc.Content[0]), api: "API"}
+	if title := s.root.get("info", "title"); title != nil && title.Value != "" {
+		s.api = title.Value
+	}
+	s.meta = map[string]string{"format": "openapi", "title": s.api}
+	if v := s.root.get("info", "version"); v != nil {
+		s.meta["version"] = v.Value
+	}
+
+	s.operations()
+	s.schemas()
+	return s.chunks, documentFrequency(s.chunks)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Spec Walking
+// ─────────────────────────────────────────────────────────────────────────────
+
+// specBuilder walks a parsed spec and collects chunks.
+type specBuilder struct {
+	docID, path string
+	root        *specNode
+	api         string
+	meta        map[string]string
+	chunks      []domain.Chunk
+}
+
+// operations adds a chunk for every method of every path.
+func (s *specBuilder) operations() {
+	paths := s.root.get("paths")
+	if paths == nil {
+		return
+	}
+	for _, pathItem := range paths.pairs() {
+		route, item := pathItem.key.Value, s.resolve(pathItem.value)
+		shared := item.get("parameters")
+		for _, method := range httpMethods {
+			key, op := item.pair(method)
+			if op == nil {
+				continue
+			}
+			s.operation(strings.ToUpper(method)+" "+route, key, op, shared)
+		}
+	}
+}
+
+// operation renders one operation.
+func (s *specBuilder) operation(title string, key, op, shared *specNode) {
+	var sb strings.Builder
+	var code []domain.CodeBlock
+	var rows []domain.TableRow
+
+	sb.WriteString(title + "\n")
+	for _, field := range []string{"summary", "description"} {
+		if n := op.get(field); n != nil && strings.TrimSpace(n.Value) != "" {
+			sb.WriteString("\n" + strings.TrimSpace(n.Value) + "\n")
+		}
+	}
+	if id := op.get("operationId"); id != nil {
+		sb.WriteString("\nOperation ID: " + id.Value + "\n")
+	}
+	if op.get("deprecated").scalar() == "true" {
+		sb.WriteString("\nDeprecated.\n")
+	}
+
+	// Parameters: path-level first, then the operation's own
+	var params []*specNode
+	for _, list := range []*specNode{shared, op.get("parameters")} {
+		params = append(params, list.items()...)
+	}
+	if len(params) > 0 {
+		sb.WriteString("\nParameters:\n")
+		rows = append(rows, domain.TableRow{Cells: []string{"Name", "In", "Type", "Required", "Description"}, Line: params[0].Line})
+	}
+	for _, raw := range params {
+		param := s.resolve(raw)
+		name, in := param.get("name").scalar(), param.get("in").scalar()
+		if in == "body" {
+			// Swagger 2 request body
+			s.writeContent(&sb, &code, "Request body", param.get("schema"), nil)
+			continue
+		}
+		typ := s.schemaSummary(param.get("schema"))
+		if typ == "" {
+			typ = param.get("type").scalar()
+		}
+		required := param.get("required").scalar() == "true"
+		desc := oneLine(param.get("description").scalar())
+
+		sb.WriteString(fmt.Sprintf("- %s (%s, %s", name, in, orDash(typ)))
+		if required {
+			sb.WriteString(", required")
+		}
+		sb.WriteString(")")
+		if desc != "" {
+			sb.WriteString(": " + desc)
+		}
+		sb.WriteString("\n")
+		rows = append(rows, domain.TableRow{Cells: []string{name, in, orDash(typ), fmt.Sprint(required), orDash(desc)}, Line: raw.Line})
+	}
+
+	if body := s.resolve(op.get("requestBody")); body != nil {
+		s.writeMedia(&sb, &code, "Request body", body)
+	}
+
+	if responses := op.get("responses"); responses != nil {
+		sb.WriteString("\nResponses:\n")
+		for _, r := range responses.pairs() {
+			resp := s.resolve(r.value)
+			sb.WriteString("- " + r.key.Value)
+			if desc := oneLine(resp.get("description").scalar()); desc != "" {
+				sb.WriteString(": " + desc)
+			}
+			sb.WriteString("\n")
+			if resp.get("schema") != nil {
+				s.writeContent(&sb, &code, "  Response "+r.key.Value, resp.get("schema"), resp.get("examples"))
+			} else {
+				s.writeMedia(&sb, &code, "  Response "+r.key.Value, resp)
+			}
+		}
+	}
+
+	headingPath := []string{s.api}
+	if tags := op.get("tags").items(); len(tags) > 0 {
+		headingPath = append(headingPath, tags[0].Value)
+	}
+	headingPath = append(headingPath, title)
+	s.add(title, headingPath, key.Line, op.lastLine(), sb.String(), code, rows)
+}
+
+// writeMedia renders the schema and examples of each media type in an
+// OpenAPI 3 request body or response.
+func (s *specBuilder) writeMedia(sb *strings.Builder, code *[]domain.CodeBlock, label string, n *specNode) {
+	for _, media := range n.get("content").pairs() {
+		mt := media.value
+		examples := mt.get("example")
+		if examples == nil {
+			examples = mt.get("examples")
+		}
+		s.writeContent(sb, code, label+" ("+media.key.Value+")", mt.get("schema"), examples)
+	}
+}
+
+// writeContent renders a schema summary, its fields, and any examples.
+func (s *specBuilder) writeContent(sb *strings.Builder, code *[]domain.CodeBlock, label string, schema, examples *specNode) {
+	if summary := s.schemaSummary(schema); summary != "" {
+		sb.WriteString(label + ": " + summary + "\n")
+	}
+	if schema != nil && schema.get("$ref") == nil {
+		for _, f := range s.fields(schema) {
+			sb.WriteString(fmt.Sprintf("  - %s: %s\n", f.name, f.typ))
+		}
+	}
+	if examples == nil {
+		return
+	}
+
+	// "examples" maps names to {value: ...}; "example" is the value itself
+	values := []*specNode{examples}
+	if examples.Kind == yaml.MappingNode && examples.get("value") == nil && len(examples.pairs()) > 0 && examples.pairs()[0].value.get("value") != nil {
+		values = values[:0]
+		for _, ex := range examples.pairs() {
+			values = append(values, s.resolve(ex.value).get("value"))
+		}
+	}
+	for _, v := range values {
+		if src := v.json(); src != "" {
+			sb.WriteString("Example:\n```json\n" + src + "\n```\n")
+			*code = append(*code, domain.CodeBlock{Language: "json", Code: src, Line: v.Line})
+		}
+	}
+}
+
+// schemas adds a chunk per component schema (OpenAPI 3) or definition (Swagger 2).
+func (s *specBuilder) schemas() {
+	defs := s.root.get("components", "schemas")
+	if defs == nil {
+		defs = s.root.get("definitions")
+	}
+	for _, def := range defs.pairs() {
+		name, schema := def.key.Value, def.value
+
+		var sb strings.Builder
+		sb.WriteString("Schema " + name + "\n")
+		if desc := strings.TrimSpace(schema.get("description").scalar()); desc != "" {
+			sb.WriteString("\n" + desc + "\n")
+		}
+		if summary := s.schemaSummary(schema); summary != "" && summary != "object" {
+			sb.WriteString("\nType: " + summary + "\n")
+		}
+
+		var rows []domain.TableRow
+		if fields := s.fields(schema); len(fields) > 0 {
+			sb.WriteString("\n| Field | Type | Required | Description |\n|---|---|---|---|\n")
+			rows = append(rows, domain.TableRow{Cells: []string{"Field", "Type", "Required", "Description"}, Line: def.key.Line})
+			for _, f := range fields {
+				cells := []string{f.name, f.typ, fmt.Sprint(f.required), orDash(f.desc)}
+				sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+				rows = append(rows, domain.TableRow{Cells: cells, Line: f.line})
+			}
+		}
+
+		var code []domain.CodeBlock
+		if ex := schema.get("example"); ex != nil {
+			if src := ex.json(); src != "" {
+				sb.WriteString("\nExample:\n```json\n" + src + "\n```\n")
+				code = append(code, domain.CodeBlock{Language: "json", Code: src, Line: ex.Line})
+			}
+		}
+
+		s.add(name, []string{s.api, "Schemas", name}, def.key.Line, schema.lastLine(), sb.String(), code, rows)
+	}
+}
+
+// add appends a chunk.
+func (s *specBuilder) add(title string, headingPath []string, start, end int, body string, code []domain.CodeBlock, rows []domain.TableRow) {
+	if chunk, ok := newChunk(s.docID, s.path, title, headingPath, start, max(start, end), []string{body}, code, rows); ok {
+		chunk.Metadata = s.meta
+		s.chunks = append(s.chunks, chunk)
+	}
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Schemas
+// ─────────────────────────────────────────────────────────────────────────────
+
+// schemaField is one property of an object schema.
+type schemaField struct {
+	name, typ, desc string
+	required        bool
+	line            int
+}
+
+// fields lists an object schema's properties, including those of allOf members.
+func (s *specBuilder) fields(schema *specNode) []schemaField {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	required := map[string]bool{}
+	for _, r := range schema.get("required").items() {
+		required[r.Value] = true
+	}
+
+	var fields []schemaField
+	for _, prop := range schema.get("properties").pairs() {
+		p := s.resolve(prop.value)
+		fields = append(fields, schemaField{
+			name:     prop.key.Value,
+			typ:      orDash(s.schemaSummary(prop.value)),
+			desc:     oneLine(p.get("description").scalar()),
+			required: required[prop.key.Value],
+			line:     prop.key.Line,
+		})
+	}
+	for _, member := range schema.get("allOf").items() {
+		fields = append(fields, s.fields(member)...)
+	}
+	return fields
+}
+
+// schemaSummary describes a schema in a few words: a referenced name,
+// "array of X", or its type and format.
+func (s *specBuilder) schemaSummary(schema *specNode) string {
+	if schema == nil {
+		return ""
+	}
+	if ref := schema.get("$ref"); ref != nil {
+		return refName(ref.Value)
+	}
+	for _, combo := range []string{"oneOf", "anyOf", "allOf"} {
+		if members := schema.get(combo).items(); len(members) > 0 {
+			names := make([]string, len(members))
+			for i, m := range members {
+				names[i] = orDash(s.schemaSummary(m))
+			}
+			return combo + "(" + strings.Join(names, ", ") + ")"
+		}
+	}
+
+	typ := schema.get("type").scalar()
+	if typ == "" && schema.get("properties") != nil {
+		typ = "object"
+	}
+	switch typ {
+	case "array":
+		return "array of " + orDash(s.schemaSummary(schema.get("items")))
+	case "":
+		return ""
+	}
+	if f := schema.get("format").scalar(); f != "" {
+		typ += " (" + f + ")"
+	}
+	if values := schema.get("enum").items(); len(values) > 0 {
+		names := make([]string, len(values))
+		for i, v := range values {
+			names[i] = v.Value
+		}
+		typ += " enum: " + strings.Join(names, ", ")
+	}
+	return typ
+}
+
+// resolve follows a local $ref ("#/components/schemas/X"); other nodes are
+// returned as-is.
+func (s *specBuilder) resolve(n *specNode) *specNode {
+	for depth := 0; n != nil && depth < 8; depth++ {
+		ref := n.get("$ref")
+		if ref == nil || !strings.HasPrefix(ref.Value, "#/") {
+			return n
+		}
+		var keys []string
+		for _, k := range strings.Split(strings.TrimPrefix(ref.Value, "#/"), "/") {
+			keys = append(keys, strings.NewReplacer("~1", "/", "~0", "~").Replace(k))
+		}
+		target := s.root.get(keys...)
+		if target == nil {
+			return n
+		}
+		n = target
+	}
+	return n
+}
+
+// refName returns the last segment of a $ref.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// oneLine collapses whitespace so descriptions fit in a list item or table cell.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// orDash returns s, or "-" if it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// YAML Node Helpers
+// ─────────────────────────────────────────────────────────────────────────────
+
+// specNode adds nil-safe lookups to yaml.Node.
+type specNode yaml.Node
+
+// specPair is one key/value of a mapping.
+type specPair struct {
+	key, value *specNode
+}
+
+// get follows a path of mapping keys; nil if any is missing.
+func (n *specNode) get(keys ...string) *specNode {
+	for _, k := range keys {
+		_, n = n.pair(k)
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// pair returns the key and value nodes for k in a mapping.
+func (n *specNode) pair(k string) (key, value *specNode) {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == k {
+			return (*specNode)(n.Content[i]), (*specNode)(n.Content[i+1]).deref()
+		}
+	}
+	return nil, nil
+}
+
+// pairs returns a mapping's entries in document order.
+func (n *specNode) pairs() []specPair {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	out := make([]specPair, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		out = append(out, specPair{key: (*specNode)(n.Content[i]), value: (*specNode)(n.Content[i+1]).deref()})
+	}
+	return out
+}
+
+// items returns a sequence's elements.
+func (n *specNode) items() []*specNode {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	out := make([]*specNode, len(n.Content))
+	for i, c := range n.Content {
+		out[i] = (*specNode)(c).deref()
+	}
+	return out
+}
+
+// scalar returns a scalar's value, or "".
+func (n *specNode) scalar() string {
+	if n == nil || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return n.Value
+}
+
+// deref follows YAML aliases.
+func (n *specNode) deref() *specNode {
+	if n != nil && n.Kind == yaml.AliasNode && n.Alias != nil {
+		return (*specNode)(n.Alias)
+	}
+	return n
+}
+
+// lastLine returns the last line any part of the node is on.
+func (n *specNode) lastLine() int {
+	if n == nil {
+		return 0
+	}
+	last := n.Line
+	for _, c := range n.Content {
+		last = max(last, (*specNode)(c).lastLine())
+	}
+	return last
+}
+
+// json renders the node as indented JSON, for examples.
+func (n *specNode) json() string {
+	if n == nil {
+		return ""
+	}
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" {
+		return n.Value // Often already a JSON or text example
+	}
+	var v any
+	if err := (*yaml.Node)(n).Decode(&v); err != nil {
+		return ""
+	}
+	out, err := json.MarshalIndent(jsonValue(v), "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// jsonValue converts YAML-decoded maps with non-string keys so they can be
+// marshalled as JSON.
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = jsonValue(val)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = jsonValue(val)
+		}
+		return m
+	case []any:
+		for i := range v {
+			v[i] = jsonValue(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
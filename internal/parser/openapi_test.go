@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const petstoreSpec = `openapi: 3.0.3
+info:
+  title: Streams API
+  version: 2.1.0
+paths:
+  /streams/{stream}/consumers:
+    parameters:
+      - name: stream
+        in: path
+        required: true
+        schema:
+          type: string
+    post:
+      tags: [consumers]
+      summary: Create a consumer
+      operationId: createConsumer
+      parameters:
+        - $ref: '#/components/parameters/Timeout'
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/ConsumerConfig'
+            example:
+              durable_name: orders
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/ConsumerConfig'
+        '404':
+          description: Stream not found
+components:
+  parameters:
+    Timeout:
+      name: timeout
+      in: query
+      schema:
+        type: integer
+        format: int32
+  schemas:
+    ConsumerConfig:
+      description: Consumer settings.
+      required: [durable_name]
+      properties:
+        durable_name:
+          type: string
+          description: Durable name.
+        ack_policy:
+          type: string
+          enum: [none, all, explicit]
+`
+
+func TestOpenAPIParser_OperationsAndSchemas(t *testing.T) {
+	chunks, _ := NewOpenAPIParser().Parse("api.yaml", petstoreSpec)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+
+	op := chunks[0]
+	if got := strings.Join(op.HeadingPath, " › "); got != "Streams API › consumers › POST /streams/{stream}/consumers" {
+		t.Errorf("heading path = %q", got)
+	}
+	if op.StartLine != 13 || op.EndLine != 34 {
+		t.Errorf("operation lines = %d-%d, want 13-34", op.StartLine, op.EndLine)
+	}
+	for _, want := range []string{
+		"Create a consumer",
+		"- stream (path, string, required)",
+		"- timeout (query, integer (int32))",
+		"Request body (application/json): ConsumerConfig",
+		`"durable_name": "orders"`,
+		"- 404: Stream not found",
+	} {
+		if !strings.Contains(op.Text, want) {
+			t.Errorf("operation text missing %q:\n%s", want, op.Text)
+		}
+	}
+	if len(op.CodeBlocks) != 1 || op.CodeBlocks[0].Language != "json" {
+		t.Errorf("examples: %+v", op.CodeBlocks)
+	}
+	if op.Metadata["version"] != "2.1.0" || op.Metadata["format"] != "openapi" {
+		t.Errorf("metadata = %v", op.Metadata)
+	}
+
+	schema := chunks[1]
+	if got := strings.Join(schema.HeadingPath, " › "); got != "Streams API › Schemas › ConsumerConfig" || schema.StartLine != 44 {
+		t.Errorf("schema = %q at line %d", got, schema.StartLine)
+	}
+	if len(schema.TableRows) != 3 {
+		t.Fatalf("expected header + 2 field rows, got %+v", schema.TableRows)
+	}
+	if row := schema.TableRows[1]; strings.Join(row.Cells, ",") != "durable_name,string,true,Durable name." || row.Line != 48 {
+		t.Errorf("field row = %+v", row)
+	}
+	if !strings.Contains(schema.TableRows[2].Cells[1], "enum: none, all, explicit") {
+		t.Errorf("enum row = %+v", schema.TableRows[2])
+	}
+}
+
+func TestOpenAPIParser_SwaggerJSON(t *testing.T) {
+	spec := `{
+  "swagger": "2.0",
+  "info": {"title": "Legacy"},
+  "paths": {
+    "/items": {
+      "get": {
+        "parameters": [{"name": "limit", "in": "query", "type": "integer"}],
+        "responses": {"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Item"}}}}
+      }
+    }
+  },
+  "definitions": {"Item": {"properties": {"id": {"type": "string"}}}}
+}`
+	if !looksLikeOpenAPI(spec) {
+		t.Fatal("expected JSON spec to be sniffed")
+	}
+	chunks, _ := NewOpenAPIParser().Parse("api.json", spec)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if got := strings.Join(chunks[0].HeadingPath, " › "); got != "Legacy › GET /items" {
+		t.Errorf("heading path = %q", got)
+	}
+	if !strings.Contains(chunks[0].Text, "- limit (query, integer)") || !strings.Contains(chunks[0].Text, "Response 200: array of Item") {
+		t.Errorf("operation text:\n%s", chunks[0].Text)
+	}
+	if chunks[1].Title != "Item" || chunks[1].StartLine != 12 {
+		t.Errorf("definition chunk = %q at %d", chunks[1].Title, chunks[1].StartLine)
+	}
+}
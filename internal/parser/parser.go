@@ -23,8 +23,9 @@ type Parser interface {
 	Parse(path, content string) (chunks []domain.Chunk, docFreq map[string]int)
 }
 
-// MarkdownParser splits markdown files by headings and paragraph breaks.
-// It's the default implementation used in production.
+// MarkdownParser splits markdown files by headings and paragraph breaks,
+// recognizing them with line regexes. CommonMarkParser is the default in
+// production; this one remains available as the "legacy" parser.
 type MarkdownParser struct {
 	// MaxLinesPerChunk is the hard limit before forcing a new chunk (default: 120)
 	MaxLinesPerChunk int
@@ -85,10 +86,20 @@ func (h *headingStack) push(level int, title string) {
 		h.levels = h.levels[:len(h.levels)-1]
 		h.titles = h.titles[:len(h.titles)-1]
 	}
+	// A heading repeating the document title doesn't nest under it
+	if len(h.levels) == 1 && h.levels[0] == 0 && h.titles[0] == title {
+		return
+	}
 	h.levels = append(h.levels, level)
 	h.titles = append(h.titles, title)
 }
 
+// pushRoot sets a document title (from front matter) above all headings.
+func (h *headingStack) pushRoot(title string) {
+	h.levels = append(h.levels[:0], 0)
+	h.titles = append(h.titles[:0], title)
+}
+
 func (h *headingStack) path() []string {
 	if len(h.titles) == 0 {
 		return nil
@@ -104,6 +115,7 @@ func (h *headingStack) path() []string {
 func (p *MarkdownParser) Parse(path, content string) ([]domain.Chunk, map[string]int) {
 	lines := strings.Split(content, "\n")
 	docID := DocIDForPath(path)
+	fm := ParseFrontMatter(content)
 
 	// Defaults if not set
 	maxLines := p.MaxLinesPerChunk
@@ -117,12 +129,16 @@ func (p *MarkdownParser) Parse(path, content string) ([]domain.Chunk, map[string
 
 	// State for building chunks
 	curTitle := filepath.Base(path) // Use filename as initial title
-	curStart := 1                   // 1-indexed line number
+	curStart := fm.Lines + 1        // 1-indexed line number (after front matter)
 	curBuf := make([]string, 0, 256)
 	blankRun := 0 // Count consecutive blank lines
 
 	// Heading hierarchy for breadcrumb paths
 	headings := &headingStack{}
+	if title := fm.Title(); title != "" {
+		curTitle = title
+		headings.pushRoot(title)
+	}
 
 	// Code block extraction state
 	var codeBlocks []domain.CodeBlock
@@ -138,31 +154,10 @@ func (p *MarkdownParser) Parse(path, content string) ([]domain.Chunk, map[string
 
 	// flush saves the current buffer as a chunk
 	flush := func(endLine int) {
-		txt := strings.TrimSpace(strings.Join(curBuf, "\n"))
-		if txt == "" {
-			curBuf = curBuf[:0]
-			curStart = endLine + 1
-			codeBlocks = nil
-			tableRows = nil
-			return
+		if chunk, ok := newChunk(docID, path, curTitle, headings.path(), curStart, endLine, curBuf, codeBlocks, tableRows); ok {
+			chunks = append(chunks, chunk)
 		}
 
-		chunk := domain.Chunk{
-			ChunkID:     fmt.Sprintf("%s:%d-%d", docID, curStart, endLine),
-			DocID:       docID,
-			Path:        path,
-			Title:       curTitle,
-			HeadingPath: headings.path(),
-			StartLine:   curStart,
-			EndLine:     endLine,
-			Text:        txt,
-			Terms:       text.NormalizeTerms(txt), // Use shared package
-			CodeBlocks:  codeBlocks,
-			TableRows:   tableRows,
-			HasCode:     len(codeBlocks) > 0,
-		}
-		chunks = append(chunks, chunk)
-
 		curBuf = curBuf[:0]
 		curStart = endLine + 1
 		codeBlocks = nil
@@ -190,6 +185,9 @@ func (p *MarkdownParser) Parse(path, content string) ([]domain.Chunk, map[string
 	// Process each line
 	for i, line := range lines {
 		ln := i + 1 // 1-indexed line number
+		if ln <= fm.Lines {
+			continue // Front matter is metadata, not text
+		}
 
 		// Handle code block boundaries
 		if inCodeBlock {
@@ -269,8 +267,35 @@ func (p *MarkdownParser) Parse(path, content string) ([]domain.Chunk, map[string
 		flush(len(lines))
 	}
 
-	// Calculate document frequency (how many chunks contain each term)
-	// This is used in BM25 scoring - rare terms are more significant
+	applyFrontMatter(chunks, fm)
+	return chunks, documentFrequency(chunks)
+}
+
+// newChunk builds a chunk from buffered lines; ok is false if they are blank.
+func newChunk(docID, path, title string, headingPath []string, start, end int, lines []string, code []domain.CodeBlock, rows []domain.TableRow) (domain.Chunk, bool) {
+	txt := strings.TrimSpace(strings.Join(lines, "\n"))
+	if txt == "" {
+		return domain.Chunk{}, false
+	}
+	return domain.Chunk{
+		ChunkID:     fmt.Sprintf("%s:%d-%d", docID, start, end),
+		DocID:       docID,
+		Path:        path,
+		Title:       title,
+		HeadingPath: headingPath,
+		StartLine:   start,
+		EndLine:     end,
+		Text:        txt,
+		Terms:       text.NormalizeTerms(txt), // Use shared package
+		CodeBlocks:  code,
+		TableRows:   rows,
+		HasCode:     len(code) > 0,
+	}, true
+}
+
+// documentFrequency counts how many chunks contain each term.
+// This is used in BM25 scoring - rare terms are more significant
+func documentFrequency(chunks []domain.Chunk) map[string]int {
 	docFreq := make(map[string]int)
 	for _, c := range chunks {
 		seen := make(map[string]struct{})
@@ -282,6 +307,5 @@ func (p *MarkdownParser) Parse(path, content string) ([]domain.Chunk, map[string
 			docFreq[term]++
 		}
 	}
-
-	return chunks, docFreq
+	return docFreq
 }
@@ -0,0 +1,694 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// ProtoParser turns a Protocol Buffers (.proto) file into chunks: one per
+// message, enum, service, RPC and field. Every chunk is titled with its fully
+// qualified name (pkg.v1.Consumer.durable_name) and carries its leading
+// comment as the description; messages list their fields (number, type,
+// label) and enums their values as table rows. Heading paths look like
+// "pkg.v1 › Consumer › durable_name" and line ranges cover the declaration
+// and its leading comment.
+type ProtoParser struct{}
+
+// NewProtoParser creates a protobuf parser.
+func NewProtoParser() *ProtoParser {
+	return &ProtoParser{}
+}
+
+// protoScalars are the built-in field types, which are never qualified.
+var protoScalars = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true,
+	"uint64": true, "sint32": true, "sint64": true, "fixed32": true, "fixed64": true,
+	"sfixed32": true, "sfixed64": true, "bool": true, "string": true, "bytes": true,
+}
+
+// Parse splits a .proto file into declaration chunks. Declarations it can't
+// make sense of are skipped rather than failing the whole file.
+func (p *ProtoParser) Parse(path, content string) ([]domain.Chunk, map[string]int) {
+	toks, comments := lexProto(content)
+	pp := &protoParser{toks: toks, known: map[string]bool{}}
+	pp.attachComments(comments)
+	pp.file()
+
+	root := pp.pkg
+	if root == "" {
+		root = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	meta := map[string]string{"format": "protobuf"}
+	if pp.pkg != "" {
+		meta["package"] = pp.pkg
+	}
+	if pp.syntax != "" {
+		meta["syntax"] = pp.syntax
+	}
+
+	b := &protoBuilder{docID: DocIDForPath(path), path: path, root: root, meta: meta, known: pp.known}
+	for _, d := range pp.decls {
+		b.decl(d)
+	}
+	return b.chunks, documentFrequency(b.chunks)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Lexer
+// ─────────────────────────────────────────────────────────────────────────────
+
+// protoToken is an identifier, number, string literal or punctuation mark.
+type protoToken struct {
+	text string
+	line int
+}
+
+// protoComment is a // or /* */ comment. Trailing comments follow code on
+// the line they start on.
+type protoComment struct {
+	text       string
+	start, end int
+	trailing   bool
+}
+
+// lexProto splits a .proto file into tokens and comments.
+func lexProto(src string) ([]protoToken, []protoComment) {
+	var toks []protoToken
+	var comments []protoComment
+	line, lastTokLine := 1, 0
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			text := strings.TrimPrefix(strings.TrimPrefix(src[i:i+end], "//"), " ")
+			comments = append(comments, protoComment{text: strings.TrimRight(text, " \t\r"), start: line, end: line, trailing: lastTokLine == line})
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			body := src[i+2 : i+2+end]
+			start := line
+			line += strings.Count(body, "\n")
+			comments = append(comments, protoComment{text: blockCommentText(body), start: start, end: line, trailing: lastTokLine == start})
+			i += min(len(src)-i, end+4)
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(src))
+			toks = append(toks, protoToken{text: src[i:j], line: line})
+			lastTokLine = line
+			i = j
+		case isProtoIdent(c):
+			j := i
+			for j < len(src) && isProtoIdent(src[j]) {
+				j++
+			}
+			toks = append(toks, protoToken{text: src[i:j], line: line})
+			lastTokLine = line
+			i = j
+		default:
+			toks = append(toks, protoToken{text: string(c), line: line})
+			lastTokLine = line
+			i++
+		}
+	}
+	return toks, comments
+}
+
+// isProtoIdent reports whether c can be part of an identifier, a dotted
+// type name or a number.
+func isProtoIdent(c byte) bool {
+	return c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// blockCommentText strips the leading " * " that /* */ comments often put
+// on each line.
+func blockCommentText(body string) string {
+	lines := strings.Split(body, "\n")
+	for i, l := range lines {
+		l = strings.TrimSpace(l)
+		l = strings.TrimPrefix(l, "*")
+		lines[i] = strings.TrimPrefix(l, " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Parser
+// ─────────────────────────────────────────────────────────────────────────────
+
+// protoDecl is a message, enum, service, RPC or field declaration.
+type protoDecl struct {
+	kind       string   // "message", "enum", "service", "rpc" or "field"
+	fqn        string   // Fully qualified name
+	path       []string // Enclosing names, outermost first, ending with the name
+	scope      string   // Scope for resolving type names
+	start, end int      // Line range, including the leading comment
+	doc        string
+
+	fields []*protoField // Message fields or enum values
+	rpcs   []*protoDecl  // Service methods
+	field  *protoField   // Field declarations
+
+	request, response             string
+	streamRequest, streamResponse bool
+}
+
+// protoField is a message field or enum value.
+type protoField struct {
+	name, typ, number, label, doc string
+	line                          int
+	deprecated                    bool
+}
+
+// protoParser is a recursive-descent parser over lexed tokens. It only
+// understands declarations; options, reserved ranges and extensions are
+// skipped.
+type protoParser struct {
+	toks     []protoToken
+	pos      int
+	docs     map[int]protoComment // Line after a comment block -> the block
+	trailing map[int]string       // Line -> comment trailing code on it
+	pkg      string
+	syntax   string
+	decls    []*protoDecl
+	known    map[string]bool // Fully qualified message and enum names
+}
+
+// attachComments indexes comments so declarations can find theirs. Adjacent
+// leading comment lines merge into one block; a blank line detaches a block.
+func (p *protoParser) attachComments(comments []protoComment) {
+	p.docs = map[int]protoComment{}
+	p.trailing = map[int]string{}
+	for i := 0; i < len(comments); i++ {
+		c := comments[i]
+		if c.trailing {
+			p.trailing[c.start] = c.text
+			continue
+		}
+		block := c
+		for i+1 < len(comments) && !comments[i+1].trailing && comments[i+1].start == block.end+1 {
+			i++
+			block.text += "\n" + comments[i].text
+			block.end = comments[i].end
+		}
+		p.docs[block.end+1] = block
+	}
+}
+
+// peek returns the text of the token n positions ahead, or "" at the end.
+func (p *protoParser) peek(n int) string {
+	if p.pos+n < len(p.toks) {
+		return p.toks[p.pos+n].text
+	}
+	return ""
+}
+
+// next consumes and returns a token.
+func (p *protoParser) next() protoToken {
+	if p.pos >= len(p.toks) {
+		return protoToken{line: p.line()}
+	}
+	t := p.toks[p.pos]
+	p.pos++
+	return t
+}
+
+// line returns the line of the current token, or of the last one at the end.
+func (p *protoParser) line() int {
+	switch {
+	case p.pos < len(p.toks):
+		return p.toks[p.pos].line
+	case len(p.toks) > 0:
+		return p.toks[len(p.toks)-1].line
+	}
+	return 1
+}
+
+// accept consumes the current token if it is text.
+func (p *protoParser) accept(text string) bool {
+	if p.peek(0) == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// skipStatement skips to the end of a statement: past its ";" or its
+// balanced { } block. It returns the line it ended on.
+func (p *protoParser) skipStatement() int {
+	depth := 0
+	for p.pos < len(p.toks) {
+		t := p.next()
+		switch t.text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth <= 0 {
+				return t.line
+			}
+		case ";":
+			if depth == 0 {
+				return t.line
+			}
+		}
+	}
+	return p.line()
+}
+
+// leading returns the comment for a declaration starting on line, and the
+// line its range starts on.
+func (p *protoParser) leading(line int) (string, int) {
+	if c, ok := p.docs[line]; ok {
+		return c.text, c.start
+	}
+	return p.trailing[line], line
+}
+
+// file parses top-level statements.
+func (p *protoParser) file() {
+	for p.pos < len(p.toks) {
+		switch p.peek(0) {
+		case "syntax", "edition":
+			p.next()
+			if p.accept("=") {
+				p.syntax = strings.Trim(p.peek(0), `"'`)
+			}
+			p.skipStatement()
+		case "package":
+			p.next()
+			p.pkg = strings.Trim(p.next().text, ".")
+			p.skipStatement()
+		case "message":
+			p.message(p.pkg, nil)
+		case "enum":
+			p.enum(p.pkg, nil)
+		case "service":
+			p.service()
+		case ";":
+			p.next()
+		default: // import, option, extend
+			p.skipStatement()
+		}
+	}
+}
+
+// begin starts a declaration whose keyword is the current token.
+func (p *protoParser) begin(kind, scope string, parents []string) *protoDecl {
+	doc, start := p.leading(p.line())
+	p.next() // Keyword
+	name := p.next().text
+	d := &protoDecl{
+		kind:  kind,
+		fqn:   qualify(scope, name),
+		path:  append(append([]string(nil), parents...), name),
+		scope: scope,
+		start: start,
+		doc:   doc,
+	}
+	p.decls = append(p.decls, d)
+	return d
+}
+
+// message parses a message and everything nested in it.
+func (p *protoParser) message(scope string, parents []string) {
+	d := p.begin("message", scope, parents)
+	p.known[d.fqn] = true
+	d.scope = d.fqn
+	if !p.accept("{") {
+		d.end = p.skipStatement()
+		return
+	}
+	for p.pos < len(p.toks) {
+		switch p.peek(0) {
+		case "}":
+			d.end = p.next().line
+			return
+		case "message":
+			p.message(d.fqn, d.path)
+		case "enum":
+			p.enum(d.fqn, d.path)
+		case "oneof":
+			p.next()
+			label := "oneof " + p.next().text
+			if !p.accept("{") {
+				p.skipStatement()
+				continue
+			}
+			for p.pos < len(p.toks) && !p.accept("}") {
+				switch p.peek(0) {
+				case "option":
+					p.skipStatement()
+				case ";":
+					p.next()
+				default:
+					p.field(d, label)
+				}
+			}
+		case "option", "reserved", "extensions", "extend":
+			p.skipStatement()
+		case ";":
+			p.next()
+		default:
+			p.field(d, "")
+		}
+	}
+	d.end = p.line()
+}
+
+// field parses a field of msg; label is set for oneof members.
+func (p *protoParser) field(msg *protoDecl, label string) {
+	doc, start := p.leading(p.line())
+	line := p.line()
+	switch p.peek(0) {
+	case "optional", "required", "repeated":
+		label = p.next().text
+	}
+
+	typ := p.next().text
+	if typ == "map" && p.accept("<") {
+		var parts []string
+		for p.pos < len(p.toks) && p.peek(0) != ">" {
+			if t := p.next().text; t != "," {
+				parts = append(parts, t)
+			}
+		}
+		p.accept(">")
+		typ = "map<" + strings.Join(parts, ", ") + ">"
+	}
+	name := p.next().text
+	if typ == "group" || !p.accept("=") {
+		p.skipStatement() // Proto2 group or something unrecognized
+		return
+	}
+	f := &protoField{name: name, typ: typ, number: p.next().text, label: label, line: line}
+	if p.peek(0) == "[" {
+		f.deprecated = p.options()
+	}
+	end := p.skipStatement()
+	f.doc = doc
+	if f.doc == "" {
+		f.doc = p.trailing[end]
+	}
+	msg.fields = append(msg.fields, f)
+
+	p.decls = append(p.decls, &protoDecl{
+		kind:  "field",
+		fqn:   msg.fqn + "." + name,
+		path:  append(append([]string(nil), msg.path...), name),
+		scope: msg.fqn,
+		start: start,
+		end:   end,
+		doc:   f.doc,
+		field: f,
+	})
+}
+
+// options consumes a [ ... ] option list and reports whether it marks the
+// declaration deprecated.
+func (p *protoParser) options() bool {
+	deprecated := false
+	depth := 0
+	for p.pos < len(p.toks) {
+		t := p.next().text
+		switch t {
+		case "[":
+			depth++
+		case "]":
+			if depth--; depth == 0 {
+				return deprecated
+			}
+		case "deprecated":
+			deprecated = p.peek(0) == "=" && p.peek(1) == "true"
+		}
+	}
+	return deprecated
+}
+
+// enum parses an enum and its values.
+func (p *protoParser) enum(scope string, parents []string) {
+	d := p.begin("enum", scope, parents)
+	p.known[d.fqn] = true
+	if !p.accept("{") {
+		d.end = p.skipStatement()
+		return
+	}
+	for p.pos < len(p.toks) {
+		switch p.peek(0) {
+		case "}":
+			d.end = p.next().line
+			return
+		case "option", "reserved":
+			p.skipStatement()
+		case ";":
+			p.next()
+		default:
+			doc, _ := p.leading(p.line())
+			v := &protoField{line: p.line(), name: p.next().text}
+			if !p.accept("=") {
+				p.skipStatement()
+				continue
+			}
+			if p.accept("-") {
+				v.number = "-"
+			}
+			v.number += p.next().text
+			if p.peek(0) == "[" {
+				v.deprecated = p.options()
+			}
+			end := p.skipStatement()
+			if v.doc = doc; v.doc == "" {
+				v.doc = p.trailing[end]
+			}
+			d.fields = append(d.fields, v)
+		}
+	}
+	d.end = p.line()
+}
+
+// service parses a service and its RPCs.
+func (p *protoParser) service() {
+	d := p.begin("service", p.pkg, nil)
+	if !p.accept("{") {
+		d.end = p.skipStatement()
+		return
+	}
+	for p.pos < len(p.toks) {
+		switch p.peek(0) {
+		case "}":
+			d.end = p.next().line
+			return
+		case "rpc":
+			d.rpcs = append(d.rpcs, p.rpc(d))
+		case ";":
+			p.next()
+		default:
+			p.skipStatement()
+		}
+	}
+	d.end = p.line()
+}
+
+// rpc parses "rpc Name (stream Req) returns (stream Resp)" and its options.
+func (p *protoParser) rpc(svc *protoDecl) *protoDecl {
+	d := p.begin("rpc", p.pkg, svc.path)
+	d.fqn = svc.fqn + "." + d.path[len(d.path)-1]
+
+	if p.accept("(") {
+		d.streamRequest = p.accept("stream")
+		d.request = p.next().text
+		p.accept(")")
+	}
+	p.accept("returns")
+	if p.accept("(") {
+		d.streamResponse = p.accept("stream")
+		d.response = p.next().text
+		p.accept(")")
+	}
+	d.end = p.skipStatement()
+	if d.doc == "" {
+		d.doc = p.trailing[d.end]
+	}
+	return d
+}
+
+// qualify joins a scope and a name.
+func qualify(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Chunks
+// ─────────────────────────────────────────────────────────────────────────────
+
+// protoBuilder turns parsed declarations into chunks.
+type protoBuilder struct {
+	docID, path string
+	root        string
+	meta        map[string]string
+	known       map[string]bool
+	chunks      []domain.Chunk
+}
+
+// decl adds the chunk for one declaration.
+func (b *protoBuilder) decl(d *protoDecl) {
+	var sb strings.Builder
+	var code []domain.CodeBlock
+	var rows []domain.TableRow
+
+	sb.WriteString(d.kind + " " + d.fqn + "\n")
+	switch d.kind {
+	case "field":
+		f := d.field
+		sig := f.typ + " " + f.name + " = " + f.number + ";"
+		if f.label != "" && !strings.HasPrefix(f.label, "oneof ") {
+			sig = f.label + " " + sig
+		}
+		code = append(code, domain.CodeBlock{Language: "protobuf", Code: sig, Line: f.line})
+		sb.WriteString("\n```protobuf\n" + sig + "\n```\n")
+		sb.WriteString("\nMessage: " + strings.TrimSuffix(d.fqn, "."+f.name) + "\n")
+		sb.WriteString("Type: " + b.resolve(f.typ, d.scope) + "\n")
+		sb.WriteString("Number: " + f.number + "\n")
+		if f.label != "" {
+			sb.WriteString("Label: " + f.label + "\n")
+		}
+		if f.deprecated {
+			sb.WriteString("Deprecated: true\n")
+		}
+
+	case "rpc":
+		req, resp := b.resolve(d.request, d.scope), b.resolve(d.response, d.scope)
+		if d.streamRequest {
+			req = "stream " + req
+		}
+		if d.streamResponse {
+			resp = "stream " + resp
+		}
+		sig := "rpc " + d.path[len(d.path)-1] + "(" + req + ") returns (" + resp + ");"
+		code = append(code, domain.CodeBlock{Language: "protobuf", Code: sig, Line: d.start})
+		sb.WriteString("\n```protobuf\n" + sig + "\n```\n")
+		sb.WriteString("\nService: " + strings.TrimSuffix(d.fqn, "."+d.path[len(d.path)-1]) + "\n")
+		sb.WriteString("Request: " + req + "\nResponse: " + resp + "\n")
+	}
+
+	if doc := strings.TrimSpace(d.doc); doc != "" {
+		sb.WriteString("\n" + doc + "\n")
+	}
+
+	switch d.kind {
+	case "message":
+		if len(d.fields) > 0 {
+			header := []string{"Field", "Number", "Type", "Label", "Description"}
+			rows = b.table(&sb, header, d.start, d.fields, func(f *protoField) []string {
+				return []string{f.name, f.number, b.resolve(f.typ, d.scope), orDash(f.label), orDash(b.describe(f))}
+			})
+		}
+	case "enum":
+		if len(d.fields) > 0 {
+			rows = b.table(&sb, []string{"Value", "Number", "Description"}, d.start, d.fields, func(f *protoField) []string {
+				return []string{f.name, f.number, orDash(b.describe(f))}
+			})
+		}
+	case "service":
+		if len(d.rpcs) > 0 {
+			sb.WriteString("\n| RPC | Request | Response | Description |\n|---|---|---|---|\n")
+			rows = append(rows, domain.TableRow{Cells: []string{"RPC", "Request", "Response", "Description"}, Line: d.start})
+			for _, r := range d.rpcs {
+				req, resp := b.resolve(r.request, r.scope), b.resolve(r.response, r.scope)
+				if r.streamRequest {
+					req = "stream " + req
+				}
+				if r.streamResponse {
+					resp = "stream " + resp
+				}
+				cells := []string{r.path[len(r.path)-1], req, resp, orDash(oneLine(r.doc))}
+				sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+				rows = append(rows, domain.TableRow{Cells: cells, Line: r.start})
+			}
+		}
+	}
+
+	chunk, ok := newChunk(b.docID, b.path, d.fqn, append([]string{b.root}, d.path...), d.start, max(d.start, d.end), []string{sb.String()}, code, rows)
+	if !ok {
+		return
+	}
+	// Several declarations can share a line ("message Empty { int32 x = 1; }")
+	chunk.ChunkID = fmt.Sprintf("%s:%d-%d:%s", b.docID, chunk.StartLine, chunk.EndLine, d.fqn)
+	chunk.Metadata = b.meta
+	b.chunks = append(b.chunks, chunk)
+}
+
+// table writes fields as a markdown table and returns them as table rows.
+func (b *protoBuilder) table(sb *strings.Builder, header []string, line int, fields []*protoField, cells func(*protoField) []string) []domain.TableRow {
+	sb.WriteString("\n| " + strings.Join(header, " | ") + " |\n|" + strings.Repeat("---|", len(header)) + "\n")
+	rows := []domain.TableRow{{Cells: header, Line: line}}
+	for _, f := range fields {
+		c := cells(f)
+		sb.WriteString("| " + strings.Join(c, " | ") + " |\n")
+		rows = append(rows, domain.TableRow{Cells: c, Line: f.line})
+	}
+	return rows
+}
+
+// describe returns a field's comment on one line, noting deprecation.
+func (b *protoBuilder) describe(f *protoField) string {
+	desc := oneLine(f.doc)
+	if f.deprecated {
+		desc = strings.TrimSpace("Deprecated. " + desc)
+	}
+	return desc
+}
+
+// resolve qualifies a type name the way protoc does: relative names are
+// looked up from the innermost enclosing scope outwards. Scalars, map key
+// types and names defined in other files are returned unchanged.
+func (b *protoBuilder) resolve(typ, scope string) string {
+	if inner, ok := strings.CutPrefix(typ, "map<"); ok {
+		key, value, _ := strings.Cut(strings.TrimSuffix(inner, ">"), ", ")
+		return "map<" + key + ", " + b.resolve(value, scope) + ">"
+	}
+	if protoScalars[typ] || typ == "" {
+		return typ
+	}
+	if full, ok := strings.CutPrefix(typ, "."); ok {
+		return full
+	}
+	for s := scope; ; {
+		if name := qualify(s, typ); b.known[name] {
+			return name
+		}
+		if s == "" {
+			return typ
+		}
+		i := strings.LastIndex(s, ".")
+		if i < 0 {
+			s = ""
+		} else {
+			s = s[:i]
+		}
+	}
+}
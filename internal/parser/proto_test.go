@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const consumerProto = `syntax = "proto3";
+
+package pkg.v1;
+
+import "google/protobuf/timestamp.proto";
+
+// Consumer reads messages from a stream.
+// It tracks delivery state.
+message Consumer {
+  // Durable name of the consumer.
+  string durable_name = 1;
+  repeated string subjects = 2 [deprecated = true];
+  DeliverPolicy deliver_policy = 3; // Where to start.
+  google.protobuf.Timestamp created = 4;
+  map<string, State> states = 5;
+
+  message State {
+    uint64 pending = 1;
+  }
+
+  oneof start {
+    uint64 start_sequence = 6;
+  }
+}
+
+/*
+ * DeliverPolicy selects the first message to deliver.
+ */
+enum DeliverPolicy {
+  DELIVER_ALL = 0;
+  DELIVER_LAST = 1; // Only the last message.
+}
+
+// ConsumerService manages consumers.
+service ConsumerService {
+  // Create adds a consumer.
+  rpc Create(Consumer) returns (Consumer);
+  rpc Watch(Consumer) returns (stream Consumer.State) {
+    option idempotency_level = NO_SIDE_EFFECTS;
+  }
+}
+`
+
+func TestProtoParser_Declarations(t *testing.T) {
+	chunks, _ := NewProtoParser().Parse("consumer.proto", consumerProto)
+
+	byName := map[string]int{}
+	var titles []string
+	for i, c := range chunks {
+		byName[c.Title] = i
+		titles = append(titles, c.Title)
+	}
+	want := []string{
+		"pkg.v1.Consumer",
+		"pkg.v1.Consumer.durable_name",
+		"pkg.v1.Consumer.subjects",
+		"pkg.v1.Consumer.deliver_policy",
+		"pkg.v1.Consumer.created",
+		"pkg.v1.Consumer.states",
+		"pkg.v1.Consumer.State",
+		"pkg.v1.Consumer.State.pending",
+		"pkg.v1.Consumer.start_sequence",
+		"pkg.v1.DeliverPolicy",
+		"pkg.v1.ConsumerService",
+		"pkg.v1.ConsumerService.Create",
+		"pkg.v1.ConsumerService.Watch",
+	}
+	if strings.Join(titles, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("chunks:\n%s", strings.Join(titles, "\n"))
+	}
+
+	msg := chunks[0]
+	if got := strings.Join(msg.HeadingPath, " › "); got != "pkg.v1 › Consumer" {
+		t.Errorf("heading path = %q", got)
+	}
+	if msg.StartLine != 7 || msg.EndLine != 24 {
+		t.Errorf("message lines = %d-%d, want 7-24", msg.StartLine, msg.EndLine)
+	}
+	if !strings.Contains(msg.Text, "Consumer reads messages from a stream.\nIt tracks delivery state.") {
+		t.Errorf("message text:\n%s", msg.Text)
+	}
+	rows := map[string]string{}
+	for _, r := range msg.TableRows {
+		rows[r.Cells[0]] = strings.Join(r.Cells, ",")
+	}
+	for name, row := range map[string]string{
+		"Field":          "Field,Number,Type,Label,Description",
+		"durable_name":   "durable_name,1,string,-,Durable name of the consumer.",
+		"subjects":       "subjects,2,string,repeated,Deprecated.",
+		"deliver_policy": "deliver_policy,3,pkg.v1.DeliverPolicy,-,Where to start.",
+		"created":        "created,4,google.protobuf.Timestamp,-,-",
+		"states":         "states,5,map<string, pkg.v1.Consumer.State>,-,-",
+		"start_sequence": "start_sequence,6,uint64,oneof start,-",
+	} {
+		if rows[name] != row {
+			t.Errorf("row %s = %q, want %q", name, rows[name], row)
+		}
+	}
+
+	field := chunks[byName["pkg.v1.Consumer.durable_name"]]
+	if field.StartLine != 10 || field.EndLine != 11 {
+		t.Errorf("field lines = %d-%d, want 10-11", field.StartLine, field.EndLine)
+	}
+	if got := strings.Join(field.HeadingPath, " › "); got != "pkg.v1 › Consumer › durable_name" {
+		t.Errorf("field heading path = %q", got)
+	}
+	for _, want := range []string{"string durable_name = 1;", "Message: pkg.v1.Consumer", "Number: 1", "Durable name of the consumer."} {
+		if !strings.Contains(field.Text, want) {
+			t.Errorf("field text missing %q:\n%s", want, field.Text)
+		}
+	}
+
+	enum := chunks[byName["pkg.v1.DeliverPolicy"]]
+	if enum.StartLine != 26 || !strings.Contains(enum.Text, "DeliverPolicy selects the first message to deliver.") {
+		t.Errorf("enum at %d:\n%s", enum.StartLine, enum.Text)
+	}
+	if len(enum.TableRows) != 3 || strings.Join(enum.TableRows[2].Cells, ",") != "DELIVER_LAST,1,Only the last message." {
+		t.Errorf("enum rows = %+v", enum.TableRows)
+	}
+
+	watch := chunks[byName["pkg.v1.ConsumerService.Watch"]]
+	if watch.StartLine != 38 || watch.EndLine != 40 {
+		t.Errorf("rpc lines = %d-%d, want 38-40", watch.StartLine, watch.EndLine)
+	}
+	if !strings.Contains(watch.Text, "rpc Watch(pkg.v1.Consumer) returns (stream pkg.v1.Consumer.State);") {
+		t.Errorf("rpc text:\n%s", watch.Text)
+	}
+	svc := chunks[byName["pkg.v1.ConsumerService"]]
+	if len(svc.TableRows) != 3 || strings.Join(svc.TableRows[1].Cells, ",") != "Create,pkg.v1.Consumer,pkg.v1.Consumer,Create adds a consumer." {
+		t.Errorf("service rows = %+v", svc.TableRows)
+	}
+	if msg.Metadata["format"] != "protobuf" || msg.Metadata["package"] != "pkg.v1" || msg.Metadata["syntax"] != "proto3" {
+		t.Errorf("metadata = %v", msg.Metadata)
+	}
+
+	ids := map[string]bool{}
+	for _, c := range chunks {
+		if ids[c.ChunkID] {
+			t.Errorf("duplicate chunk ID %s", c.ChunkID)
+		}
+		ids[c.ChunkID] = true
+	}
+}
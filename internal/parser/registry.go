@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// Selector is implemented by parsers that pick a parser per file, so callers
+// can tell unsupported files apart before parsing them.
+type Selector interface {
+	// Detect returns the parser for a recognized file type, or nil.
+	Detect(path, content string) Parser
+
+	// Fallback returns the parser for a text file of unknown type, or nil if
+	// content isn't text.
+	Fallback(content string) Parser
+}
+
+// sniffer recognizes a format by content, for files without a known extension.
+type sniffer struct {
+	match  func(content string) bool
+	parser Parser
+}
+
+// Registry chooses a parser by file extension, then by sniffing the content,
+// then falls back to a default parser for any other text file.
+type Registry struct {
+	byExt    map[string]Parser
+	sniffers []sniffer
+	fallback Parser
+}
+
+// NewRegistry creates an empty registry that falls back to fallback.
+func NewRegistry(fallback Parser) *Registry {
+	return &Registry{
+		byExt:    make(map[string]Parser),
+		fallback: fallback,
+	}
+}
+
+// NewDefaultRegistry registers every built-in format on top of a markdown
+// parser, which also handles .txt, extensionless and unrecognized text files.
+// OpenAPI specs and man pages are recognized by content, whatever their
+// extension.
+func NewDefaultRegistry(markdown Parser) *Registry {
+	r := NewRegistry(markdown)
+	r.Register(markdown, ".md", ".markdown", ".mdown", ".mkd", ".txt", "")
+	r.Register(NewMDXParser(markdown), ".mdx")
+	r.Register(NewRSTParser(), ".rst", ".rest")
+	r.Register(NewAsciiDocParser(), ".adoc", ".asciidoc", ".asc")
+	r.Register(NewProtoParser(), ".proto")
+	r.Register(NewNotebookParser(markdown), ".ipynb")
+	r.Register(NewManParser(), ".1", ".2", ".3", ".4", ".5", ".6", ".7", ".8", ".man")
+	r.RegisterSniffer(looksLikeOpenAPI, NewOpenAPIParser())
+	r.RegisterSniffer(looksLikeMan, NewManParser())
+	r.RegisterSniffer(looksLikeRST, NewRSTParser())
+	r.RegisterSniffer(looksLikeAsciiDoc, NewAsciiDocParser())
+	return r
+}
+
+// Register uses p for files with any of the given extensions ("" for none).
+// Extensions are matched case-insensitively.
+func (r *Registry) Register(p Parser, exts ...string) {
+	for _, ext := range exts {
+		r.byExt[strings.ToLower(ext)] = p
+	}
+}
+
+// RegisterSniffer uses p for files whose extension isn't registered and
+// whose content satisfies match. Sniffers are tried in registration order.
+func (r *Registry) RegisterSniffer(match func(content string) bool, p Parser) {
+	r.sniffers = append(r.sniffers, sniffer{match: match, parser: p})
+}
+
+// Detect returns the parser registered for the file's extension, or the
+// first sniffer that recognizes its content. Binary content is never detected.
+func (r *Registry) Detect(path, content string) Parser {
+	if isBinary(content) {
+		return nil
+	}
+	if p, ok := r.byExt[strings.ToLower(filepath.Ext(path))]; ok {
+		return p
+	}
+	for _, s := range r.sniffers {
+		if s.match(content) {
+			return s.parser
+		}
+	}
+	return nil
+}
+
+// Fallback returns the default parser unless content is binary.
+func (r *Registry) Fallback(content string) Parser {
+	if isBinary(content) {
+		return nil
+	}
+	return r.fallback
+}
+
+// Parse parses with the detected parser, or the fallback. Binary files yield
+// no chunks.
+func (r *Registry) Parse(path, content string) ([]domain.Chunk, map[string]int) {
+	p := r.Detect(path, content)
+	if p == nil {
+		p = r.Fallback(content)
+	}
+	if p == nil {
+		return nil, map[string]int{}
+	}
+	return p.Parse(path, content)
+}
+
+// isBinary reports whether content looks like a binary file: a NUL byte in
+// the first 8KB. Invalid UTF-8 alone isn't binary: Latin-1 and Windows-1252
+// text files have it too.
+func isBinary(content string) bool {
+	head := content
+	if len(head) > 8192 {
+		head = head[:8192]
+	}
+	return strings.IndexByte(head, 0) >= 0
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRegistry_Detect(t *testing.T) {
+	r := NewDefaultRegistry(NewCommonMarkParser())
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+	}{
+		{"markdown", "a.md", "# Hi", "*parser.CommonMarkParser"},
+		{"extension is case-insensitive", "A.MD", "# Hi", "*parser.CommonMarkParser"},
+		{"mdx", "a.mdx", "# Hi", "*parser.MDXParser"},
+		{"rst", "a.rst", "Title\n=====", "*parser.RSTParser"},
+		{"asciidoc", "a.adoc", "= Title", "*parser.AsciiDocParser"},
+		{"proto", "api.proto", "syntax = \"proto3\";", "*parser.ProtoParser"},
+		{"notebook", "runbook.ipynb", `{"cells": []}`, "*parser.NotebookParser"},
+		{"man page", "nats.1", ".TH NATS 1", "*parser.ManParser"},
+		{"sniffed man page", "nats.1p", ".TH NATS 1P", "*parser.ManParser"},
+		{"extensionless is markdown", "README", "Title\n=====\n\n.. note:: hi", "*parser.CommonMarkParser"},
+		{"sniffed rst", "guide.text", "Title\n=====\n\n.. note:: hi", "*parser.RSTParser"},
+		{"sniffed asciidoc", "guide.text", "= Guide\n\n== Part", "*parser.AsciiDocParser"},
+		{"sniffed openapi", "api.yaml", "openapi: 3.0.0\ninfo:\n  title: x", "*parser.OpenAPIParser"},
+		{"unknown", "app.js", "const x = 1", "<nil>"},
+		{"binary", "a.md", "\x89PNG\x00\x00", "<nil>"},
+		{"latin-1 text", "a.md", "# Caf\xe9\n\nR\xe9sum\xe9", "*parser.CommonMarkParser"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf("%T", r.Detect(tt.path, tt.content)); got != tt.want {
+				t.Errorf("Detect = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if r.Fallback("const x = 1") == nil {
+		t.Error("expected text of unknown type to fall back to markdown")
+	}
+	if r.Fallback("\x00") != nil {
+		t.Error("expected no fallback for binary content")
+	}
+}
@@ -0,0 +1,193 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// RSTParser splits reStructuredText by section titles. Heading levels follow
+// the order in which adornment styles first appear, as in docutils; literal
+// blocks (after "::" and code directives) become code blocks and are never
+// split, and grid table rows are extracted like markdown tables.
+type RSTParser struct {
+	// MaxLinesPerChunk is the hard limit before forcing a new chunk (default: 120)
+	MaxLinesPerChunk int
+
+	// MinLinesPerChunk is the minimum before a heading triggers a new chunk (default: 12)
+	MinLinesPerChunk int
+}
+
+// NewRSTParser creates a reStructuredText parser with sensible defaults.
+func NewRSTParser() *RSTParser {
+	return &RSTParser{
+		MaxLinesPerChunk: 120,
+		MinLinesPerChunk: 12,
+	}
+}
+
+// Parse splits a reStructuredText file into chunks.
+func (p *RSTParser) Parse(path, content string) ([]domain.Chunk, map[string]int) {
+	lines := strings.Split(content, "\n")
+	chunks := chunkLines(path, lines, analyzeRST(lines), FrontMatter{}, p.MinLinesPerChunk, p.MaxLinesPerChunk)
+	return chunks, documentFrequency(chunks)
+}
+
+// rstCodeDirectiveRe matches directives whose body is source code.
+var rstCodeDirectiveRe = regexp.MustCompile(`^\.\.\s+(?:code-block|code|sourcecode)::\s*(\S*)`)
+
+// rstDirectiveRe matches any directive or hyperlink target, used for sniffing.
+var rstDirectiveRe = regexp.MustCompile(`(?m)^\.\. (?:[\w-]+::|_[^:]+:)`)
+
+// analyzeRST finds section titles, literal blocks and grid table rows.
+func analyzeRST(lines []string) blockInfo {
+	info := newBlockInfo()
+	var styles []string // Adornment styles in order of first use; index = level-1
+
+	level := func(style string) int {
+		for i, s := range styles {
+			if s == style {
+				return i + 1
+			}
+		}
+		styles = append(styles, style)
+		return len(styles)
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t\r")
+		ln := i + 1
+
+		// Literal blocks: "para::", "::" and code directives introduce an indented body
+		if m := rstCodeDirectiveRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil || strings.HasSuffix(line, "::") {
+			lang := ""
+			if m != nil {
+				lang = m[1]
+			} else if strings.HasPrefix(strings.TrimSpace(line), "..") {
+				continue // Other directives (note, toctree, ...) contain ordinary text
+			}
+			if end, code := rstLiteralBlock(lines, i, m != nil); end > i {
+				info.code[ln] = domain.CodeBlock{Language: lang, Code: code, Line: ln}
+				info.protect(ln+1, end+1)
+				i = end
+			}
+			continue
+		}
+
+		// Over- and underlined title
+		if isRSTAdornment(line) && i+2 < len(lines) {
+			title := strings.TrimSpace(lines[i+1])
+			under := strings.TrimRight(lines[i+2], " \t\r")
+			if title != "" && under == line && utf8.RuneCountInString(line) >= utf8.RuneCountInString(title) {
+				info.headings[ln] = headingInfo{level: level("over" + line[:1]), title: title, endLine: ln + 2}
+				i += 2
+				continue
+			}
+		}
+
+		// Underlined title
+		if i+1 < len(lines) && line != "" && !isIndented(line) && !isRSTAdornment(line) && (i == 0 || strings.TrimSpace(lines[i-1]) == "") {
+			under := strings.TrimRight(lines[i+1], " \t\r")
+			if isRSTAdornment(under) && utf8.RuneCountInString(under) >= utf8.RuneCountInString(line) {
+				info.headings[ln] = headingInfo{level: level(under[:1]), title: strings.TrimSpace(line), endLine: ln + 1}
+				i++
+				continue
+			}
+		}
+
+		// Grid table row: | cell | cell |
+		if t := strings.TrimSpace(line); strings.HasPrefix(t, "|") && strings.HasSuffix(t, "|") && len(t) > 1 {
+			if cells := splitCells(t); len(cells) > 0 {
+				info.rows[ln] = domain.TableRow{Cells: cells, Line: ln}
+			}
+		}
+	}
+	return info
+}
+
+// rstLiteralBlock reads the indented block after line i. Directive options
+// (":linenos:") are skipped when directive is true. It returns the last line
+// index of the block (i if there is none) and the dedented code.
+func rstLiteralBlock(lines []string, i int, directive bool) (int, string) {
+	base := len(lines[i]) - len(strings.TrimLeft(lines[i], " "))
+	end := i
+	var body []string
+	inOptions := directive
+	for j := i + 1; j < len(lines); j++ {
+		l := strings.TrimRight(lines[j], " \t\r")
+		if l == "" {
+			if len(body) > 0 {
+				body = append(body, "")
+			}
+			inOptions = false
+			continue
+		}
+		if !isIndented(l[min(base, len(l)):]) {
+			break
+		}
+		end = j
+		if inOptions && strings.HasPrefix(strings.TrimSpace(l), ":") {
+			continue
+		}
+		inOptions = false
+		body = append(body, l)
+	}
+	for len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+	}
+	return end, dedentLines(body)
+}
+
+// isRSTAdornment reports whether a line is a section adornment: one
+// punctuation character repeated at least twice.
+func isRSTAdornment(line string) bool {
+	if len(line) < 2 || !strings.ContainsRune("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", rune(line[0])) {
+		return false
+	}
+	return strings.Trim(line, line[:1]) == ""
+}
+
+// isIndented reports whether a line starts with whitespace.
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// splitCells splits a "| a | b |" row into trimmed, non-empty cells.
+func splitCells(row string) []string {
+	parts := strings.Split(strings.Trim(row, "|"), "|")
+	cells := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if cell := strings.TrimSpace(part); cell != "" && !isSeparatorCell(cell) {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// dedentLines removes the common leading indentation and joins the lines.
+func dedentLines(lines []string) string {
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		if n := len(l) - len(strings.TrimLeft(l, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			l = l[indent:]
+		}
+		out[i] = l
+	}
+	return strings.Join(out, "\n")
+}
+
+// looksLikeRST reports whether content uses reStructuredText directives or targets.
+func looksLikeRST(content string) bool {
+	return rstDirectiveRe.MatchString(content)
+}
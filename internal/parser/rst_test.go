@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRSTParser_SectionsAndLiteralBlocks(t *testing.T) {
+	content := strings.Join([]string{
+		"==========",
+		"User Guide",
+		"==========",
+		"",
+		"Intro text.",
+		"",
+		"Install",
+		"=======",
+		"",
+		"Run this::",
+		"",
+		"    pip install nats",
+		"",
+		"    Not a Title",
+		"    -----------",
+		"",
+		"Options",
+		"-------",
+		"",
+		".. code-block:: python",
+		"   :linenos:",
+		"",
+		"   nc = NATS()",
+		"",
+		"+------+------+",
+		"| Name | Type |",
+		"+======+======+",
+		"| id   | int  |",
+		"+------+------+",
+		"",
+		"Usage",
+		"=====",
+		"",
+		"Text.",
+	}, "\n")
+
+	p := NewRSTParser()
+	p.MinLinesPerChunk = 1
+	chunks, _ := p.Parse("guide.rst", content)
+
+	var paths []string
+	for _, c := range chunks {
+		paths = append(paths, strings.Join(c.HeadingPath, " > "))
+	}
+	want := []string{"User Guide", "User Guide > Install", "User Guide > Install > Options", "User Guide > Usage"}
+	if strings.Join(paths, "|") != strings.Join(want, "|") {
+		t.Fatalf("heading paths = %v, want %v", paths, want)
+	}
+
+	install := chunks[1]
+	if install.StartLine != 7 || install.EndLine != 16 {
+		t.Errorf("install lines = %d-%d, want 7-16", install.StartLine, install.EndLine)
+	}
+	if len(install.CodeBlocks) != 1 || install.CodeBlocks[0].Line != 10 || !strings.HasPrefix(install.CodeBlocks[0].Code, "pip install nats\n\nNot a Title") {
+		t.Errorf("literal block: %+v", install.CodeBlocks)
+	}
+
+	options := chunks[2]
+	if len(options.CodeBlocks) != 1 || options.CodeBlocks[0].Language != "python" || options.CodeBlocks[0].Code != "nc = NATS()" {
+		t.Errorf("code-block directive: %+v", options.CodeBlocks)
+	}
+	if len(options.TableRows) != 2 || strings.Join(options.TableRows[1].Cells, ",") != "id,int" {
+		t.Errorf("grid table rows: %+v", options.TableRows)
+	}
+}
@@ -0,0 +1,450 @@
+// Package queue provides a small durable job queue for background work such as
+// embedding generation. Jobs are keyed by document ID, retried with exponential
+// backoff, report per-document progress, and survive restarts via a JSON file.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// State is the lifecycle state of a job.
+type State string
+
+const (
+	StatePending State = "pending" // Waiting for a worker (or for its retry time)
+	StateRunning State = "running" // Being processed right now
+	StateFailed  State = "failed"  // Gave up after MaxAttempts (or permanent error)
+	StateReady   State = "ready"   // Completed successfully
+)
+
+// Job is the persisted record for one document.
+type Job struct {
+	DocID       string    `json:"doc_id"`
+	Path        string    `json:"path,omitempty"` // For display only
+	State       State     `json:"state"`
+	Done        int       `json:"done"`  // Units of work completed (e.g. chunks embedded)
+	Total       int       `json:"total"` // Units of work in total (0 = unknown yet)
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ProgressFunc reports how much of a job is done.
+type ProgressFunc func(done, total int)
+
+// Processor performs the work for one document.
+// Returning an error schedules a retry unless the error is Permanent.
+type Processor func(ctx context.Context, docID string, progress ProgressFunc) error
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job fails immediately instead of retrying.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Config holds queue settings.
+type Config struct {
+	Workers     int           // Concurrent jobs (default: 1)
+	MaxAttempts int           // Attempts before a job is marked failed (default: 8)
+	BaseBackoff time.Duration // Delay after the first failure (default: 2s)
+	MaxBackoff  time.Duration // Upper bound for retry delays (default: 5m)
+	StatePath   string        // JSON file for persistence (empty = in-memory only)
+}
+
+// DefaultConfig returns sensible defaults for embedding jobs.
+func DefaultConfig() Config {
+	return Config{
+		Workers:     1,
+		MaxAttempts: 8,
+		BaseBackoff: 2 * time.Second,
+		MaxBackoff:  5 * time.Minute,
+	}
+}
+
+// Queue schedules jobs onto a fixed pool of workers.
+type Queue struct {
+	cfg    Config
+	proc   Processor
+	logger *slog.Logger
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+	wake chan struct{} // Signals idle workers that work may be available
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	now    func() time.Time
+}
+
+// New creates a queue and restores persisted jobs from cfg.StatePath.
+// Jobs that were running when the process stopped are put back to pending.
+// Call Start to begin processing.
+func New(cfg Config, proc Processor, logger *slog.Logger) (*Queue, error) {
+	def := DefaultConfig()
+	if cfg.Workers <= 0 {
+		cfg.Workers = def.Workers
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = def.MaxAttempts
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = def.BaseBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = def.MaxBackoff
+	}
+
+	q := &Queue{
+		cfg:    cfg,
+		proc:   proc,
+		logger: logger,
+		jobs:   make(map[string]*Job),
+		wake:   make(chan struct{}, 1),
+		now:    time.Now,
+	}
+
+	if err := q.restore(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Start launches the workers. They stop when ctx is cancelled or Close is called.
+func (q *Queue) Start(ctx context.Context) {
+	ctx, q.cancel = context.WithCancel(ctx)
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker(ctx)
+	}
+}
+
+// Close stops the workers and waits for running jobs to return.
+func (q *Queue) Close() {
+	if q.cancel != nil {
+		q.cancel()
+	}
+	q.wg.Wait()
+}
+
+// Enqueue schedules (or reschedules) a job for docID.
+// An existing job is reset to pending with a fresh attempt budget.
+func (q *Queue) Enqueue(docID, path string) {
+	q.mu.Lock()
+	q.jobs[docID] = &Job{
+		DocID:     docID,
+		Path:      path,
+		State:     StatePending,
+		UpdatedAt: q.now(),
+	}
+	q.persistLocked()
+	q.mu.Unlock()
+
+	q.signal()
+}
+
+// MarkReady records a job as completed without running it.
+// Used when a document's results were restored from cache.
+func (q *Queue) MarkReady(docID, path string, total int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if job, ok := q.jobs[docID]; ok && job.State == StateReady {
+		return
+	}
+	q.jobs[docID] = &Job{
+		DocID:     docID,
+		Path:      path,
+		State:     StateReady,
+		Done:      total,
+		Total:     total,
+		UpdatedAt: q.now(),
+	}
+	q.persistLocked()
+}
+
+// RetryFailed moves all failed jobs back to pending and returns how many moved.
+func (q *Queue) RetryFailed() int {
+	q.mu.Lock()
+	n := 0
+	for _, job := range q.jobs {
+		if job.State == StateFailed {
+			job.State = StatePending
+			job.Attempts = 0
+			job.NextAttempt = time.Time{}
+			job.UpdatedAt = q.now()
+			n++
+		}
+	}
+	if n > 0 {
+		q.persistLocked()
+	}
+	q.mu.Unlock()
+
+	if n > 0 {
+		q.signal()
+	}
+	return n
+}
+
+// Get returns a copy of the job for docID.
+func (q *Queue) Get(docID string) (Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[docID]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Snapshot returns copies of all jobs, ordered by state then doc ID.
+func (q *Queue) Snapshot() []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	out := make([]Job, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		out = append(out, *job)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].State != out[j].State {
+			return stateOrder(out[i].State) < stateOrder(out[j].State)
+		}
+		return out[i].DocID < out[j].DocID
+	})
+	return out
+}
+
+// stateOrder sorts active states before finished ones.
+func stateOrder(s State) int {
+	switch s {
+	case StateRunning:
+		return 0
+	case StatePending:
+		return 1
+	case StateFailed:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// signal wakes one idle worker without blocking.
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// worker runs jobs until ctx is cancelled.
+func (q *Queue) worker(ctx context.Context) {
+	defer q.wg.Done()
+
+	for {
+		job, wait := q.claim()
+		if job == nil {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-q.wake:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+
+		// More work may be waiting; let another idle worker look
+		q.signal()
+		q.run(ctx, job)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// idleWait is how long a worker sleeps when no job is scheduled at all.
+const idleWait = time.Minute
+
+// claim picks the next due pending job and marks it running, returning its
+// record. If none is due, it returns how long to wait before the earliest one is.
+func (q *Queue) claim() (*Job, time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	var next *Job
+	wait := idleWait
+
+	for _, job := range q.jobs {
+		if job.State != StatePending {
+			continue
+		}
+		if !job.NextAttempt.After(now) {
+			if next == nil || job.UpdatedAt.Before(next.UpdatedAt) {
+				next = job
+			}
+			continue
+		}
+		if d := job.NextAttempt.Sub(now); d < wait {
+			wait = d
+		}
+	}
+
+	if next == nil {
+		return nil, wait
+	}
+
+	next.State = StateRunning
+	next.Attempts++
+	next.UpdatedAt = now
+	q.persistLocked()
+	return next, 0
+}
+
+// run executes the processor for a claimed job and records the outcome.
+// Once the job is re-enqueued or replaced, the run no longer updates it: the
+// newer record belongs to whichever worker claims it.
+func (q *Queue) run(ctx context.Context, job *Job) {
+	docID := job.DocID
+	// Progress stays in memory: writing every job per update would make a
+	// long job rewrite the state file once per batch. It is persisted with
+	// the job's next state change.
+	progress := func(done, total int) {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if q.jobs[docID] == job {
+			job.Done, job.Total = done, total
+			job.UpdatedAt = q.now()
+		}
+	}
+
+	err := q.proc(ctx, docID, progress)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.jobs[docID] != job {
+		// Re-enqueued or replaced while running; the newer record wins
+		return
+	}
+	job.UpdatedAt = q.now()
+
+	switch {
+	case err == nil:
+		job.State = StateReady
+		job.LastError = ""
+		job.NextAttempt = time.Time{}
+	case ctx.Err() != nil:
+		// Shutting down: leave it pending so the next run resumes it
+		job.State = StatePending
+		job.Attempts--
+	default:
+		job.LastError = err.Error()
+		var perm permanentError
+		if errors.As(err, &perm) || job.Attempts >= q.cfg.MaxAttempts {
+			job.State = StateFailed
+			job.NextAttempt = time.Time{}
+		} else {
+			job.State = StatePending
+			job.NextAttempt = job.UpdatedAt.Add(q.backoff(job.Attempts))
+		}
+		if q.logger != nil {
+			q.logger.Warn("background job failed",
+				"doc_id", docID,
+				"attempt", job.Attempts,
+				"state", job.State,
+				"error", err)
+		}
+	}
+	q.persistLocked()
+}
+
+// backoff returns the retry delay after the given number of attempts:
+// BaseBackoff doubled per attempt, capped at MaxBackoff, with ±20% jitter.
+func (q *Queue) backoff(attempts int) time.Duration {
+	d := q.cfg.BaseBackoff
+	for i := 1; i < attempts && d < q.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, q.cfg.MaxBackoff)
+
+	jitter := (rand.Float64()*0.4 - 0.2) * float64(d)
+	return d + time.Duration(jitter)
+}
+
+// persistedState is the on-disk format of the queue.
+type persistedState struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// restore loads jobs from StatePath, resetting running jobs to pending.
+func (q *Queue) restore() error {
+	if q.cfg.StatePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(q.cfg.StatePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read queue state: %w", err)
+	}
+
+	var state persistedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("parse queue state: %w", err)
+	}
+
+	for _, job := range state.Jobs {
+		if job.State == StateRunning {
+			job.State = StatePending
+		}
+		q.jobs[job.DocID] = &job
+	}
+	return nil
+}
+
+// persistLocked writes all jobs to StatePath. Caller must hold q.mu.
+// Failures are logged, not returned: persistence is best-effort.
+func (q *Queue) persistLocked() {
+	if q.cfg.StatePath == "" {
+		return
+	}
+
+	state := persistedState{Jobs: make([]Job, 0, len(q.jobs))}
+	for _, job := range q.jobs {
+		state.Jobs = append(state.Jobs, *job)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		// Write then rename so a crash never leaves a truncated file
+		tmp := q.cfg.StatePath + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o644); err == nil {
+			err = os.Rename(tmp, q.cfg.StatePath)
+		}
+	}
+	if err != nil && q.logger != nil {
+		q.logger.Warn("failed to persist queue state", "path", q.cfg.StatePath, "error", err)
+	}
+}
@@ -0,0 +1,244 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForState polls until the job reaches want or the deadline passes.
+func waitForState(t *testing.T, q *Queue, docID string, want State) Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if job, ok := q.Get(docID); ok && job.State == want {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	job, _ := q.Get(docID)
+	t.Fatalf("job %s: state = %q, want %q", docID, job.State, want)
+	return job
+}
+
+func TestQueue_RunsJobAndReportsProgress(t *testing.T) {
+	proc := func(ctx context.Context, docID string, progress ProgressFunc) error {
+		progress(1, 2)
+		progress(2, 2)
+		return nil
+	}
+
+	q, err := New(Config{}, proc, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q.Start(context.Background())
+	defer q.Close()
+
+	q.Enqueue("doc1", "docs/a.md")
+	job := waitForState(t, q, "doc1", StateReady)
+
+	if job.Done != 2 || job.Total != 2 {
+		t.Errorf("progress = %d/%d, want 2/2", job.Done, job.Total)
+	}
+	if job.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", job.Attempts)
+	}
+}
+
+func TestQueue_PersistsProgressOnStateChange(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "jobs.json")
+	reported, finish := make(chan struct{}), make(chan struct{})
+	proc := func(ctx context.Context, docID string, progress ProgressFunc) error {
+		progress(1, 2)
+		close(reported)
+		<-finish
+		progress(2, 2)
+		return nil
+	}
+
+	q, err := New(Config{StatePath: statePath}, proc, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q.Start(context.Background())
+	defer q.Close()
+	q.Enqueue("doc1", "docs/a.md")
+
+	// persisted reads the job from the state file
+	persisted := func() Job {
+		var state persistedState
+		data, err := os.ReadFile(statePath)
+		if err == nil {
+			err = json.Unmarshal(data, &state)
+		}
+		if err != nil || len(state.Jobs) != 1 {
+			t.Fatalf("state file: %s, %v", data, err)
+		}
+		return state.Jobs[0]
+	}
+
+	<-reported
+	if job := persisted(); job.State != StateRunning || job.Done != 0 {
+		t.Errorf("persisted while running = %+v, want no progress written", job)
+	}
+	close(finish)
+	waitForState(t, q, "doc1", StateReady)
+	if job := persisted(); job.State != StateReady || job.Done != 2 || job.Total != 2 {
+		t.Errorf("persisted when ready = %+v", job)
+	}
+}
+
+func TestQueue_ReenqueueWhileRunning(t *testing.T) {
+	started := make(chan int, 3)
+	release := []chan struct{}{make(chan struct{}), make(chan struct{}), nil}
+	var calls atomic.Int32
+	proc := func(ctx context.Context, docID string, progress ProgressFunc) error {
+		n := int(calls.Add(1)) - 1
+		started <- n
+		switch n {
+		case 0: // The run the re-enqueue made stale
+			<-release[0]
+			progress(9, 9)
+			return errors.New("stale run")
+		case 1:
+			<-release[1]
+			progress(2, 2)
+		}
+		return nil
+	}
+
+	q, err := New(Config{Workers: 2, BaseBackoff: time.Hour}, proc, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q.Start(context.Background())
+	defer q.Close()
+
+	q.Enqueue("doc1", "docs/a.md")
+	<-started
+	q.Enqueue("doc1", "docs/a.md") // The document changed while it was processed
+	<-started
+	q.Enqueue("doc2", "docs/b.md")
+
+	// The stale run ends first; its worker then picks up doc2
+	close(release[0])
+	<-started
+	if job, _ := q.Get("doc1"); job.State != StateRunning || job.Done != 0 || job.LastError != "" {
+		t.Errorf("after the stale run: job = %+v, want the newer run untouched", job)
+	}
+
+	close(release[1])
+	job := waitForState(t, q, "doc1", StateReady)
+	if job.Done != 2 || job.Attempts != 1 {
+		t.Errorf("job = %+v, want the newer run's outcome", job)
+	}
+}
+
+func TestQueue_RetriesWithBackoff(t *testing.T) {
+	var calls atomic.Int32
+	proc := func(ctx context.Context, docID string, progress ProgressFunc) error {
+		if calls.Add(1) < 3 {
+			return errors.New("service down")
+		}
+		return nil
+	}
+
+	q, err := New(Config{BaseBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}, proc, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q.Start(context.Background())
+	defer q.Close()
+
+	q.Enqueue("doc1", "")
+	job := waitForState(t, q, "doc1", StateReady)
+
+	if job.Attempts != 3 {
+		t.Errorf("attempts = %d, want 3", job.Attempts)
+	}
+}
+
+func TestQueue_FailsAfterMaxAttempts(t *testing.T) {
+	proc := func(ctx context.Context, docID string, progress ProgressFunc) error {
+		return errors.New("always broken")
+	}
+
+	q, err := New(Config{MaxAttempts: 2, BaseBackoff: time.Millisecond}, proc, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q.Start(context.Background())
+	defer q.Close()
+
+	q.Enqueue("doc1", "")
+	job := waitForState(t, q, "doc1", StateFailed)
+
+	if job.LastError != "always broken" {
+		t.Errorf("last error = %q", job.LastError)
+	}
+	if n := q.RetryFailed(); n != 1 {
+		t.Errorf("RetryFailed = %d, want 1", n)
+	}
+}
+
+func TestQueue_PermanentErrorSkipsRetries(t *testing.T) {
+	proc := func(ctx context.Context, docID string, progress ProgressFunc) error {
+		return Permanent(errors.New("document gone"))
+	}
+
+	q, err := New(Config{BaseBackoff: time.Millisecond}, proc, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q.Start(context.Background())
+	defer q.Close()
+
+	q.Enqueue("doc1", "")
+	job := waitForState(t, q, "doc1", StateFailed)
+	if job.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", job.Attempts)
+	}
+}
+
+func TestQueue_PersistsAcrossRestarts(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "jobs.json")
+	block := func(ctx context.Context, docID string, progress ProgressFunc) error {
+		return errors.New("not yet")
+	}
+
+	// First "process": job never succeeds and stays pending
+	q1, err := New(Config{StatePath: statePath, BaseBackoff: time.Hour}, block, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	q1.Enqueue("doc1", "docs/a.md")
+	q1.MarkReady("doc2", "docs/b.md", 7)
+
+	// Second "process": restores state and completes the pending job
+	var ran atomic.Bool
+	ok := func(ctx context.Context, docID string, progress ProgressFunc) error {
+		ran.Store(true)
+		return nil
+	}
+	q2, err := New(Config{StatePath: statePath}, ok, nil)
+	if err != nil {
+		t.Fatalf("New (restore): %v", err)
+	}
+
+	if job, found := q2.Get("doc2"); !found || job.State != StateReady || job.Total != 7 {
+		t.Errorf("restored doc2 = %+v", job)
+	}
+
+	q2.Start(context.Background())
+	defer q2.Close()
+	waitForState(t, q2, "doc1", StateReady)
+	if !ran.Load() {
+		t.Error("expected restored job to run")
+	}
+}
@@ -3,10 +3,10 @@ package search
 import (
 	"context"
 	"math"
-	"sort"
 
 	"github.com/bad33ndj3/mcp-md-index/internal/domain"
 	"github.com/bad33ndj3/mcp-md-index/internal/embedding"
+	"github.com/bad33ndj3/mcp-md-index/internal/vector"
 )
 
 const (
@@ -15,12 +15,20 @@ const (
 	DefaultRRFK          = 60
 )
 
+// semanticCandidateLimit is how many nearest neighbours a cross-document
+// query takes from the vector index before fusion.
+const semanticCandidateLimit = 100
+
 // HybridSearcher combines BM25 keyword search with embedding cosine similarity.
 // Uses BM25 until embeddings are ready, then combines both scores.
 type HybridSearcher struct {
 	embedder embedding.Embedder
 	status   *embedding.Status
 	bm25     *BM25Searcher
+	vectors  *vector.HNSW // optional ANN index for cross-document queries
+
+	// loadVectors lazily reads chunk embeddings from disk (optional)
+	loadVectors func(idx *domain.Index) error
 
 	// Configuration
 	fusionMethod string
@@ -51,21 +59,60 @@ func (s *HybridSearcher) WithFusionMethod(method string, bm25Weight, embedWeight
 	return s
 }
 
+// WithReranker rescores the top n results of every search (see BM25Searcher.WithReranker).
+func (s *HybridSearcher) WithReranker(r Reranker, n int) *HybridSearcher {
+	s.bm25.WithReranker(r, n)
+	return s
+}
+
+// WithVectorIndex makes cross-document queries take semantic candidates
+// from an approximate nearest-neighbour index instead of scanning every chunk.
+func (s *HybridSearcher) WithVectorIndex(v *vector.HNSW) *HybridSearcher {
+	s.vectors = v
+	return s
+}
+
+// WithVectorLoader sets the function used to load chunk embeddings the first
+// time a document is searched semantically (see cache.Cache.LoadVectors).
+func (s *HybridSearcher) WithVectorLoader(load func(idx *domain.Index) error) *HybridSearcher {
+	s.loadVectors = load
+	return s
+}
+
+// ensureVectors loads a document's embeddings if a loader is configured.
+func (s *HybridSearcher) ensureVectors(idx *domain.Index) error {
+	if s.loadVectors == nil {
+		return nil
+	}
+	return s.loadVectors(idx)
+}
+
 // Search uses hybrid scoring if embeddings ready, else BM25 only.
 func (s *HybridSearcher) Search(idx *domain.Index, query string, maxTokens int) string {
+	return s.SearchWithOptions(idx, query, maxTokens, Options{})
+}
+
+// SearchWithOptions is Search with per-query overrides of mode and fusion.
+// Semantic and hybrid modes fall back to BM25 while embeddings aren't ready.
+func (s *HybridSearcher) SearchWithOptions(idx *domain.Index, query string, maxTokens int, opts Options) string {
 	if maxTokens <= 0 {
 		maxTokens = domain.DefaultMaxTokens
 	}
+	cfg := s.queryConfig(opts)
+
+	// Keyword-only queries, and docs whose embeddings aren't ready, use BM25
+	if cfg.mode == ModeBM25 || !s.status.IsReady(idx.DocID) {
+		return s.bm25.Search(idx, query, maxTokens)
+	}
 
-	// If embeddings not ready for this doc, use BM25 only
-	if !s.status.IsReady(idx.DocID) {
+	if err := s.ensureVectors(idx); err != nil {
 		return s.bm25.Search(idx, query, maxTokens)
 	}
 
 	// Check if any chunks have embeddings
 	hasEmbeddings := false
 	for _, c := range idx.Chunks {
-		if c.Embedding != nil {
+		if hasVector(c) {
 			hasEmbeddings = true
 			break
 		}
@@ -77,140 +124,249 @@ func (s *HybridSearcher) Search(idx *domain.Index, query string, maxTokens int)
 	// Generate query embedding
 	ctx := context.Background()
 	queryEmbed, err := s.embedder.Embed(ctx, query)
-	if err != nil {
-		// Fallback to BM25 on error
+	if err != nil || !dimensionsMatch(idx, queryEmbed) {
+		// Fallback to BM25 on error or vectors from another embedder
 		return s.bm25.Search(idx, query, maxTokens)
 	}
 
-	// Score all chunks with hybrid approach
-	scored := s.scoreHybrid(idx, query, queryEmbed)
+	// Score all chunks with the requested rankings
+	scored := s.combine(cfg, s.bm25.scoreChunks(idx, query), rankBySimilarity(idx.Chunks, queryEmbed))
 	if len(scored) == 0 {
 		return "No relevant excerpts found in the indexed document."
 	}
 
-	return s.bm25.buildResponse(scored, maxTokens)
+	return s.bm25.buildResponse(s.bm25.rerank(query, scored), maxTokens)
 }
 
-// scoreHybrid selects the configured fusion method.
-func (s *HybridSearcher) scoreHybrid(idx *domain.Index, query string, queryEmbed []float32) []scoredChunk {
-	if s.fusionMethod == FusionMethodWeighted {
-		return s.scoreWeighted(idx, query, queryEmbed)
-	}
-	return s.scoreRRF(idx, query, queryEmbed)
+// SearchAll ranks chunks from all given documents in one list.
+// The query is embedded once; semantic candidates come from the vector index
+// (any loaded, ready document) and are fused with a BM25 ranking that treats
+// all documents as one corpus.
+func (s *HybridSearcher) SearchAll(indexes []*domain.Index, query string, maxTokens int) string {
+	return s.SearchAllWithOptions(indexes, query, maxTokens, Options{})
 }
 
-// scoreWeighted combines BM25 and cosine similarity scores using weighted average.
-func (s *HybridSearcher) scoreWeighted(idx *domain.Index, query string, queryEmbed []float32) []scoredChunk {
-	// Get BM25 scores
-	bm25Scores := s.bm25.scoreChunks(idx, query)
-
-	// Build map of BM25 scores and find max for normalization
-	maxBM25 := 0.0
-	for _, sc := range bm25Scores {
-		if sc.score > maxBM25 {
-			maxBM25 = sc.score
-		}
+// SearchAllWithOptions is SearchAll with per-query overrides of mode and fusion.
+func (s *HybridSearcher) SearchAllWithOptions(indexes []*domain.Index, query string, maxTokens int, opts Options) string {
+	if maxTokens <= 0 {
+		maxTokens = domain.DefaultMaxTokens
 	}
-
-	bm25Map := make(map[string]float64)
-	for _, sc := range bm25Scores {
-		normalized := 0.0
-		if maxBM25 > 0 {
-			normalized = sc.score / maxBM25
+	cfg := s.queryConfig(opts)
+
+	// BM25 over all documents as one corpus
+	bm25Scores := s.bm25.scoreCorpus(indexes, query)
+	ready := make(map[string]*domain.Index)
+	for _, idx := range indexes {
+		if cfg.mode != ModeBM25 && s.status.IsReady(idx.DocID) {
+			ready[idx.DocID] = idx
 		}
-		bm25Map[sc.chunk.ChunkID] = normalized
 	}
 
-	// Calculate hybrid scores
-	results := make([]scoredChunk, 0, len(idx.Chunks))
-	for _, chunk := range idx.Chunks {
-		if chunk.Embedding == nil {
-			// No embedding for this chunk, use BM25 only if it has a score
-			if bm25Score, ok := bm25Map[chunk.ChunkID]; ok && bm25Score > 0 {
-				results = append(results, scoredChunk{chunk: chunk, score: bm25Score * s.bm25Weight})
+	var semantic []scoredChunk
+	if len(ready) > 0 {
+		queryEmbed, err := s.embedder.Embed(context.Background(), query)
+		if err == nil {
+			for docID, idx := range ready {
+				if !dimensionsMatch(idx, queryEmbed) {
+					delete(ready, docID)
+				}
 			}
-			continue
+			semantic = s.semanticCandidates(ready, queryEmbed)
 		}
+	}
 
-		// Cosine similarity (already normalized to [-1, 1], shift to [0, 1])
-		cosineSim := cosineSimilarity(queryEmbed, chunk.Embedding)
-		embedScore := (cosineSim + 1) / 2 // Normalize to [0, 1]
+	scored := s.combine(cfg, bm25Scores, semantic)
+	if len(scored) == 0 {
+		return noResultsAll
+	}
+	return s.bm25.buildResponse(s.bm25.rerank(query, scored), maxTokens)
+}
 
-		bm25Score := bm25Map[chunk.ChunkID]
+// hybridConfig holds the mode and fusion settings for one query.
+type hybridConfig struct {
+	mode        string
+	fusion      string
+	bm25Weight  float64
+	embedWeight float64
+	rrfK        int
+}
 
-		// Weighted combination
-		hybridScore := s.bm25Weight*bm25Score + s.embedWeight*embedScore
+// queryConfig applies per-query overrides to the searcher's defaults.
+// Options are expected to be validated (see Options.Validate).
+func (s *HybridSearcher) queryConfig(opts Options) hybridConfig {
+	cfg := hybridConfig{
+		mode:        ModeHybrid,
+		fusion:      s.fusionMethod,
+		bm25Weight:  s.bm25Weight,
+		embedWeight: s.embedWeight,
+		rrfK:        s.rrfK,
+	}
+	if opts.Mode != "" {
+		cfg.mode = opts.Mode
+	}
+	if opts.Fusion != "" {
+		cfg.fusion = opts.Fusion
+	}
+	if opts.BM25Weight != nil {
+		cfg.bm25Weight = *opts.BM25Weight
+		cfg.embedWeight = 1 - *opts.BM25Weight
+	}
+	if opts.RRFK > 0 {
+		cfg.rrfK = opts.RRFK
+	}
+	return cfg
+}
 
-		if hybridScore > 0 {
-			results = append(results, scoredChunk{chunk: chunk, score: hybridScore})
+// combine produces the final ranking for the query mode. Semantic mode keeps
+// only positively similar chunks and falls back to BM25 without any vectors.
+func (s *HybridSearcher) combine(cfg hybridConfig, bm25Scores, semantic []scoredChunk) []scoredChunk {
+	switch {
+	case cfg.mode == ModeBM25 || len(semantic) == 0:
+		return bm25Scores
+	case cfg.mode == ModeSemantic:
+		out := semantic[:0:0]
+		for _, sc := range semantic {
+			if sc.score > 0 {
+				out = append(out, sc)
+			}
 		}
+		return out
+	default:
+		return fuse(cfg, bm25Scores, semantic)
 	}
-
-	// Sort by score descending
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].score > results[j].score
-	})
-
-	return results
 }
 
-// scoreRRF combines scores using Reciprocal Rank Fusion.
-func (s *HybridSearcher) scoreRRF(idx *domain.Index, query string, queryEmbed []float32) []scoredChunk {
-	// 1. Get BM25 ranks
-	bm25Scores := s.bm25.scoreChunks(idx, query)
-	bm25Ranks := make(map[string]int)
-	for i, sc := range bm25Scores {
-		bm25Ranks[sc.chunk.ChunkID] = i + 1
+// semanticCandidates returns the chunks most similar to queryEmbed across the
+// ready documents. Documents covered by the vector index are searched through
+// it; any others (e.g. not yet indexed) are scanned directly.
+func (s *HybridSearcher) semanticCandidates(ready map[string]*domain.Index, queryEmbed []float32) []scoredChunk {
+	var out []scoredChunk
+	covered := make(map[string]bool)
+
+	if s.vectors != nil && s.vectors.Len() > 0 {
+		for docID := range ready {
+			if s.vectors.HasDoc(docID) {
+				covered[docID] = true
+			}
+		}
+
+		if len(covered) > 0 {
+			hits := s.vectors.Search(queryEmbed, semanticCandidateLimit, func(docID string) bool {
+				return covered[docID]
+			})
+
+			// Resolve hit IDs back to chunks, one lookup table per hit document
+			byID := make(map[string]map[string]domain.Chunk)
+			for _, hit := range hits {
+				chunks, ok := byID[hit.DocID]
+				if !ok {
+					chunks = make(map[string]domain.Chunk)
+					for _, c := range ready[hit.DocID].Chunks {
+						chunks[c.ChunkID] = c
+					}
+					byID[hit.DocID] = chunks
+				}
+				if c, ok := chunks[hit.ID]; ok {
+					out = append(out, scoredChunk{chunk: c, score: hit.Score})
+				}
+			}
+		}
 	}
 
-	// 2. Get Embedding ranks
-	type embedRank struct {
-		chunkID string
-		score   float64
+	for docID, idx := range ready {
+		if covered[docID] || s.ensureVectors(idx) != nil {
+			continue
+		}
+		out = append(out, rankBySimilarity(idx.Chunks, queryEmbed)...)
 	}
-	embedScores := make([]embedRank, 0, len(idx.Chunks))
-	for _, chunk := range idx.Chunks {
-		if chunk.Embedding != nil {
-			sim := cosineSimilarity(queryEmbed, chunk.Embedding)
-			embedScores = append(embedScores, embedRank{chunkID: chunk.ChunkID, score: sim})
+	sortByScore(out)
+	return out
+}
+
+// rankBySimilarity scores every chunk that has an embedding by cosine similarity.
+func rankBySimilarity(chunks []domain.Chunk, queryEmbed []float32) []scoredChunk {
+	out := make([]scoredChunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		switch {
+		case chunk.Embedding != nil:
+			out = append(out, scoredChunk{chunk: chunk, score: cosineSimilarity(queryEmbed, chunk.Embedding)})
+		case chunk.Quantized != nil:
+			out = append(out, scoredChunk{chunk: chunk, score: cosineSimilarityInt8(queryEmbed, chunk.Quantized)})
 		}
 	}
-	sort.Slice(embedScores, func(i, j int) bool {
-		return embedScores[i].score > embedScores[j].score
-	})
+	sortByScore(out)
+	return out
+}
 
-	embedRanks := make(map[string]int)
-	for i, es := range embedScores {
-		embedRanks[es.chunkID] = i + 1
+// fuse combines a BM25 ranking and a cosine-similarity ranking
+// (both sorted best first) using the configured fusion method.
+func fuse(cfg hybridConfig, bm25Scores, semantic []scoredChunk) []scoredChunk {
+	if cfg.fusion == FusionMethodWeighted {
+		return fuseWeighted(cfg.bm25Weight, cfg.embedWeight, bm25Scores, semantic)
 	}
+	return fuseRRF(cfg.rrfK, bm25Scores, semantic)
+}
 
-	// 3. Combine using RRF formula: 1 / (k + rank)
-	rrfScores := make(map[string]float64)
-	k := float64(s.rrfK)
+// fuseWeighted combines BM25 and cosine similarity scores using weighted average.
+func fuseWeighted(bm25Weight, embedWeight float64, bm25Scores, semantic []scoredChunk) []scoredChunk {
+	// Find max BM25 for normalization
+	maxBM25 := 0.0
+	for _, sc := range bm25Scores {
+		if sc.score > maxBM25 {
+			maxBM25 = sc.score
+		}
+	}
 
-	// Add BM25 contribution
-	for chunkID, rank := range bm25Ranks {
-		rrfScores[chunkID] += 1.0 / (k + float64(rank))
+	combined := make(map[string]*scoredChunk, len(bm25Scores)+len(semantic))
+	for _, sc := range bm25Scores {
+		normalized := 0.0
+		if maxBM25 > 0 {
+			normalized = sc.score / maxBM25
+		}
+		combined[sc.chunk.ChunkID] = &scoredChunk{chunk: sc.chunk, score: bm25Weight * normalized}
 	}
 
-	// Add Embedding contribution
-	for chunkID, rank := range embedRanks {
-		rrfScores[chunkID] += 1.0 / (k + float64(rank))
+	for _, sc := range semantic {
+		// Cosine similarity is in [-1, 1], shift to [0, 1]
+		embedScore := (sc.score + 1) / 2
+		if entry, ok := combined[sc.chunk.ChunkID]; ok {
+			entry.score += embedWeight * embedScore
+			continue
+		}
+		combined[sc.chunk.ChunkID] = &scoredChunk{chunk: sc.chunk, score: embedWeight * embedScore}
 	}
 
-	// 4. Convert back to scoredChunks
-	results := make([]scoredChunk, 0, len(rrfScores))
-	for _, chunk := range idx.Chunks {
-		if score, ok := rrfScores[chunk.ChunkID]; ok {
-			results = append(results, scoredChunk{chunk: chunk, score: score})
+	return collectScored(combined)
+}
+
+// fuseRRF combines rankings using Reciprocal Rank Fusion: sum of 1 / (k + rank).
+func fuseRRF(rrfK int, bm25Scores, semantic []scoredChunk) []scoredChunk {
+	k := float64(rrfK)
+	combined := make(map[string]*scoredChunk, len(bm25Scores)+len(semantic))
+
+	for _, ranking := range [][]scoredChunk{bm25Scores, semantic} {
+		for i, sc := range ranking {
+			contribution := 1.0 / (k + float64(i+1))
+			if entry, ok := combined[sc.chunk.ChunkID]; ok {
+				entry.score += contribution
+				continue
+			}
+			combined[sc.chunk.ChunkID] = &scoredChunk{chunk: sc.chunk, score: contribution}
 		}
 	}
 
-	// Sort by score descending
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].score > results[j].score
-	})
+	return collectScored(combined)
+}
 
+// collectScored flattens fused scores into a ranking, dropping non-positive scores.
+func collectScored(combined map[string]*scoredChunk) []scoredChunk {
+	results := make([]scoredChunk, 0, len(combined))
+	for _, sc := range combined {
+		if sc.score > 0 {
+			results = append(results, *sc)
+		}
+	}
+	sortByScore(results)
 	return results
 }
 
@@ -234,3 +390,37 @@ func cosineSimilarity(a, b []float32) float64 {
 
 	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
 }
+
+// cosineSimilarityInt8 is cosineSimilarity against an int8-quantized vector.
+// The per-vector scale cancels out in the cosine, so it is not needed.
+func cosineSimilarityInt8(a []float32, b []int8) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		bi := float64(b[i])
+		dot += float64(a[i]) * bi
+		normA += float64(a[i]) * float64(a[i])
+		normB += bi * bi
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// dimensionsMatch reports whether a query embedding is comparable with the
+// document's stored vectors. Indexes without recorded state are assumed to match.
+func dimensionsMatch(idx *domain.Index, queryEmbed []float32) bool {
+	state := idx.EmbeddingState
+	return state == nil || state.Dimensions == 0 || state.Dimensions == len(queryEmbed)
+}
+
+// hasVector reports whether a chunk has a float or quantized embedding.
+func hasVector(c domain.Chunk) bool {
+	return len(c.Embedding) > 0 || len(c.Quantized) > 0
+}
@@ -2,10 +2,13 @@ package search
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/bad33ndj3/mcp-md-index/internal/domain"
 	"github.com/bad33ndj3/mcp-md-index/internal/embedding"
+	"github.com/bad33ndj3/mcp-md-index/internal/vector"
 )
 
 type mockEmbedder struct {
@@ -28,6 +31,18 @@ func (m *mockEmbedder) Available(ctx context.Context) bool {
 	return m.available
 }
 
+func (m *mockEmbedder) Provider() string {
+	return "mock"
+}
+
+func (m *mockEmbedder) Model() string {
+	return "mock"
+}
+
+func (m *mockEmbedder) Dimensions() int {
+	return 2
+}
+
 func TestHybridSearcher(t *testing.T) {
 	status := embedding.NewStatus()
 	embedder := &mockEmbedder{available: true}
@@ -81,6 +96,128 @@ func TestHybridSearcher(t *testing.T) {
 	})
 }
 
+func TestHybridSearcher_SearchAllUsesVectorIndex(t *testing.T) {
+	status := embedding.NewStatus()
+	embedder := &mockEmbedder{available: true}
+
+	docA := &domain.Index{
+		DocID: "doc-a",
+		Chunks: []domain.Chunk{
+			{ChunkID: "a1", DocID: "doc-a", Text: "apple pie", Terms: []string{"apple", "pie"}, Embedding: []float32{0, 1}},
+		},
+		DocFreq:   map[string]int{"apple": 1, "pie": 1},
+		NumChunks: 1,
+	}
+	docB := &domain.Index{
+		DocID: "doc-b",
+		Chunks: []domain.Chunk{
+			{ChunkID: "b1", DocID: "doc-b", Text: "orchard fruit harvest", Terms: []string{"orchard", "fruit", "harvest"}, Embedding: []float32{1, 0}},
+		},
+		DocFreq:   map[string]int{"orchard": 1, "fruit": 1, "harvest": 1},
+		NumChunks: 1,
+	}
+	status.SetReady("doc-a")
+	status.SetReady("doc-b")
+
+	vectors := vector.New(vector.DefaultConfig())
+	for _, idx := range []*domain.Index{docA, docB} {
+		for _, c := range idx.Chunks {
+			if err := vectors.Add(c.ChunkID, idx.DocID, c.Embedding); err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+		}
+	}
+
+	searcher := NewHybridSearcher(embedder, status).WithVectorIndex(vectors)
+	res := searcher.SearchAll([]*domain.Index{docA, docB}, "apple", 500)
+
+	// BM25 only matches doc A; the semantic candidate must come from doc B
+	if !contains(res, "apple pie") || !contains(res, "orchard fruit") {
+		t.Errorf("expected results from both documents, got:\n%s", res)
+	}
+}
+
+func TestHybridSearcher_SearchAllRanksAcrossDocumentSizes(t *testing.T) {
+	// "retention" is rare in the small doc but in every chunk of the large
+	// one, where "retention policy" is the better match for the query
+	small := &domain.Index{DocID: "small", DocFreq: map[string]int{"retention": 1, "kafka": 1, "filler": 9}, NumChunks: 10}
+	small.Chunks = append(small.Chunks, domain.Chunk{ChunkID: "s0", DocID: "small", Text: "kafka retention", Terms: []string{"kafka", "retention"}})
+	for i := 1; i < 10; i++ {
+		small.Chunks = append(small.Chunks, domain.Chunk{ChunkID: fmt.Sprintf("s%d", i), DocID: "small", Text: "filler", Terms: []string{"filler", "text"}})
+	}
+	large := &domain.Index{DocID: "large", DocFreq: map[string]int{"retention": 40, "policy": 20, "other": 20}, NumChunks: 40}
+	for i := 0; i < 40; i++ {
+		c := domain.Chunk{ChunkID: fmt.Sprintf("l%d", i), DocID: "large", Text: "retention policy", Terms: []string{"retention", "policy"}}
+		if i >= 20 {
+			c.Text, c.Terms = "retention other", []string{"retention", "other"}
+		}
+		large.Chunks = append(large.Chunks, c)
+	}
+
+	searcher := NewHybridSearcher(&mockEmbedder{available: true}, embedding.NewStatus())
+	res := searcher.SearchAllWithOptions([]*domain.Index{small, large}, "retention policy", 500, Options{Mode: ModeBM25})
+	if i, j := strings.Index(res, "retention policy"), strings.Index(res, "kafka retention"); i < 0 || (j >= 0 && j < i) {
+		t.Errorf("expected the large doc's full match first, got:\n%s", res)
+	}
+}
+
+func TestHybridSearcher_SearchWithOptionsModes(t *testing.T) {
+	status := embedding.NewStatus()
+	embedder := &mockEmbedder{available: true}
+
+	// "apple" matches c1 by keyword; the query embedding [1 0] matches c2
+	idx := &domain.Index{
+		DocID: "test-doc",
+		Chunks: []domain.Chunk{
+			{ChunkID: "c1", Text: "apple", Terms: []string{"apple"}, Embedding: []float32{0, 1}},
+			{ChunkID: "c2", Text: "banana", Terms: []string{"banana"}, Embedding: []float32{1, 0}},
+		},
+		DocFreq:   map[string]int{"apple": 1, "banana": 1},
+		NumChunks: 2,
+	}
+	status.SetReady("test-doc")
+	searcher := NewHybridSearcher(embedder, status)
+
+	res := searcher.SearchWithOptions(idx, "apple", 100, Options{Mode: ModeBM25})
+	if !contains(res, "apple") || contains(res, "banana") {
+		t.Errorf("bm25 mode: expected only the keyword match, got:\n%s", res)
+	}
+
+	res = searcher.SearchWithOptions(idx, "apple", 100, Options{Mode: ModeSemantic})
+	if !contains(res, "banana") || contains(res, "apple") {
+		t.Errorf("semantic mode: expected only the similar chunk, got:\n%s", res)
+	}
+
+	weight := 1.0
+	res = searcher.SearchWithOptions(idx, "apple", 100, Options{Fusion: FusionMethodWeighted, BM25Weight: &weight})
+	if !contains(res, "apple") || contains(res, "banana") {
+		t.Errorf("weighted fusion with bm25_weight=1: expected only the keyword match, got:\n%s", res)
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	bad := 1.5
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{"zero value", Options{}, false},
+		{"all set", Options{Mode: ModeHybrid, Fusion: FusionMethodRRF, RRFK: 10}, false},
+		{"unknown mode", Options{Mode: "fuzzy"}, true},
+		{"unknown fusion", Options{Fusion: "max"}, true},
+		{"weight out of range", Options{BM25Weight: &bad}, true},
+		{"negative k", Options{RRFK: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s[:len(substr)] == substr || contains(s[1:], substr))
 }
@@ -105,6 +242,18 @@ func TestCosineSimilarity(t *testing.T) {
 	}
 }
 
+func TestCosineSimilarityInt8(t *testing.T) {
+	a := []float32{0.5, -1, 0.25}
+	b := []int8{64, -127, 32} // ≈ a scaled by 127
+
+	if got := cosineSimilarityInt8(a, b); mathAbs(got-1) > 1e-3 {
+		t.Errorf("cosineSimilarityInt8 = %f; want ≈ 1", got)
+	}
+	if got := cosineSimilarityInt8(a, []int8{1, 2}); got != 0 {
+		t.Errorf("length mismatch should return 0, got %f", got)
+	}
+}
+
 func mathAbs(f float64) float64 {
 	if f < 0 {
 		return -f
@@ -0,0 +1,204 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+	"github.com/bad33ndj3/mcp-md-index/internal/text"
+)
+
+// DefaultRerankTopN is how many first-stage results are rescored by a Reranker.
+const DefaultRerankTopN = 20
+
+// Candidate is a first-stage result offered to a Reranker.
+type Candidate struct {
+	Chunk domain.Chunk
+	Score float64 // BM25 or fusion score; higher is better
+}
+
+// Reranker rescores the top results of first-stage retrieval.
+// It returns one score per candidate, in the same order; higher is better.
+// Scores only need to be comparable within a single call.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, candidates []Candidate) ([]float64, error)
+}
+
+// WithReranker rescores the top n results of every search before the
+// response is built (n <= 0 uses DefaultRerankTopN). Results below the top n
+// keep their first-stage order.
+func (s *BM25Searcher) WithReranker(r Reranker, n int) *BM25Searcher {
+	if n <= 0 {
+		n = DefaultRerankTopN
+	}
+	s.reranker = r
+	s.rerankTopN = n
+	return s
+}
+
+// rerank reorders the head of a ranking with the configured Reranker.
+// On error the first-stage order is kept: reranking is an optional refinement.
+func (s *BM25Searcher) rerank(query string, scored []scoredChunk) []scoredChunk {
+	if s.reranker == nil || len(scored) < 2 {
+		return scored
+	}
+
+	n := min(s.rerankTopN, len(scored))
+	candidates := make([]Candidate, n)
+	for i, sc := range scored[:n] {
+		candidates[i] = Candidate{Chunk: sc.chunk, Score: sc.score}
+	}
+
+	scores, err := s.reranker.Rerank(context.Background(), query, candidates)
+	if err != nil || len(scores) != n {
+		return scored
+	}
+
+	head := make([]scoredChunk, n)
+	for i := range head {
+		head[i] = scoredChunk{chunk: scored[i].chunk, score: scores[i]}
+	}
+	// Stable: ties keep the first-stage order
+	sort.SliceStable(head, func(i, j int) bool { return head[i].score > head[j].score })
+
+	return append(head, scored[n:]...)
+}
+
+// ─────────────────────────────────────────────────────────────────────────────
+// Heuristic Reranker
+// ─────────────────────────────────────────────────────────────────────────────
+
+// HeuristicReranker rescores candidates without a model, using signals that
+// bag-of-words ranking ignores: the query appearing as an exact phrase, query
+// terms in the chunk's headings, and how close together the terms occur.
+type HeuristicReranker struct {
+	PhraseBoost    float64 // Added when the whole query appears verbatim (default: 1.0)
+	HeadingBoost   float64 // Scaled by the share of query terms in the headings (default: 0.5)
+	ProximityBoost float64 // Scaled by how tightly the terms cluster (default: 0.5)
+}
+
+// NewHeuristicReranker returns a HeuristicReranker with default weights.
+func NewHeuristicReranker() *HeuristicReranker {
+	return &HeuristicReranker{
+		PhraseBoost:    1.0,
+		HeadingBoost:   0.5,
+		ProximityBoost: 0.5,
+	}
+}
+
+// Rerank scores each candidate as its normalized first-stage score plus boosts.
+func (h *HeuristicReranker) Rerank(ctx context.Context, query string, candidates []Candidate) ([]float64, error) {
+	terms := uniqueTerms(text.NormalizeTerms(query))
+	phrase := strings.ToLower(strings.Join(strings.Fields(query), " "))
+
+	maxScore := 0.0
+	for _, c := range candidates {
+		maxScore = max(maxScore, c.Score)
+	}
+
+	scores := make([]float64, len(candidates))
+	for i, c := range candidates {
+		score := 0.0
+		if maxScore > 0 {
+			score = c.Score / maxScore
+		}
+		if len(terms) == 0 {
+			scores[i] = score
+			continue
+		}
+
+		// A single word is already covered by term matching
+		if len(terms) > 1 && strings.Contains(strings.ToLower(strings.Join(strings.Fields(c.Chunk.Text), " ")), phrase) {
+			score += h.PhraseBoost
+		}
+
+		score += h.HeadingBoost * headingCoverage(c.Chunk, terms)
+
+		if len(terms) > 1 {
+			score += h.ProximityBoost * proximity(text.NormalizeTerms(c.Chunk.Text), terms)
+		}
+		scores[i] = score
+	}
+	return scores, nil
+}
+
+// uniqueTerms drops repeated terms, keeping the first occurrence.
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := terms[:0:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// headingCoverage returns the share of query terms found in the chunk's
+// title and heading path.
+func headingCoverage(c domain.Chunk, terms []string) float64 {
+	headingTerms := make(map[string]bool)
+	for _, t := range text.NormalizeTerms(c.Title + " " + strings.Join(c.HeadingPath, " ")) {
+		headingTerms[t] = true
+	}
+	found := 0
+	for _, t := range terms {
+		if headingTerms[t] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(terms))
+}
+
+// proximity rates how closely the query terms occur in tokens: the number of
+// distinct terms in the smallest window containing all terms that occur at
+// all, divided by that window's length. 1.0 means adjacent; 0 means fewer than
+// two terms occur.
+func proximity(tokens, terms []string) float64 {
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[t] = true
+	}
+
+	present := make(map[string]bool)
+	for _, tok := range tokens {
+		if want[tok] {
+			present[tok] = true
+		}
+	}
+	if len(present) < 2 {
+		return 0
+	}
+
+	// Sliding window over token positions
+	counts := make(map[string]int, len(present))
+	covered, best := 0, len(tokens)+1
+	left := 0
+	for right, tok := range tokens {
+		if !present[tok] {
+			continue
+		}
+		if counts[tok] == 0 {
+			covered++
+		}
+		counts[tok]++
+
+		for covered == len(present) {
+			if w := right - left + 1; w < best {
+				best = w
+			}
+			if lt := tokens[left]; present[lt] {
+				counts[lt]--
+				if counts[lt] == 0 {
+					covered--
+				}
+			}
+			left++
+		}
+	}
+
+	coverage := float64(len(present)) / float64(len(terms))
+	return coverage * float64(len(present)) / float64(best)
+}
@@ -0,0 +1,191 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Rerank API flavours for HTTPRerankerConfig.API.
+const (
+	RerankAPILlama = "llama" // llama.cpp /rerank (also Jina/Cohere-style "documents" APIs)
+	RerankAPITEI   = "tei"   // Hugging Face text-embeddings-inference /rerank
+)
+
+// HTTPRerankerConfig holds settings for a cross-encoder rerank server.
+type HTTPRerankerConfig struct {
+	URL      string        // Full endpoint URL (e.g. "http://localhost:8080/rerank")
+	API      string        // RerankAPILlama (default) or RerankAPITEI
+	Model    string        // Model name sent in the request (optional for local servers)
+	APIKey   string        // Optional bearer token
+	MaxChars int           // Per-document text limit in bytes (default: 4000)
+	Timeout  time.Duration // Request timeout (default: 10s)
+}
+
+// HTTPReranker scores candidates with a cross-encoder served over HTTP.
+type HTTPReranker struct {
+	client *http.Client
+	cfg    HTTPRerankerConfig
+}
+
+// NewHTTPReranker creates a reranker for a llama.cpp or TEI rerank endpoint.
+func NewHTTPReranker(cfg HTTPRerankerConfig) (*HTTPReranker, error) {
+	if strings.TrimSpace(cfg.URL) == "" {
+		return nil, fmt.Errorf("rerank url is required")
+	}
+	switch cfg.API {
+	case "":
+		cfg.API = RerankAPILlama
+	case RerankAPILlama, RerankAPITEI:
+	default:
+		return nil, fmt.Errorf("unknown rerank api %q (want %s or %s)", cfg.API, RerankAPILlama, RerankAPITEI)
+	}
+	if cfg.MaxChars <= 0 {
+		cfg.MaxChars = 4000
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &HTTPReranker{
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+	}, nil
+}
+
+// llamaRerankRequest is the JSON body for llama.cpp's POST /rerank.
+type llamaRerankRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n"`
+}
+
+// teiRerankRequest is the JSON body for TEI's POST /rerank.
+type teiRerankRequest struct {
+	Query    string   `json:"query"`
+	Texts    []string `json:"texts"`
+	Truncate bool     `json:"truncate"`
+}
+
+// rerankResult is one scored document; llama.cpp uses relevance_score, TEI score.
+type rerankResult struct {
+	Index          int      `json:"index"`
+	RelevanceScore *float64 `json:"relevance_score"`
+	Score          *float64 `json:"score"`
+}
+
+// Rerank sends the query and candidate texts to the rerank endpoint.
+func (r *HTTPReranker) Rerank(ctx context.Context, query string, candidates []Candidate) ([]float64, error) {
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	docs := make([]string, len(candidates))
+	for i, c := range candidates {
+		docs[i] = r.documentText(c)
+	}
+
+	var payload any
+	if r.cfg.API == RerankAPITEI {
+		payload = teiRerankRequest{Query: query, Texts: docs, Truncate: true}
+	} else {
+		payload = llamaRerankRequest{Model: r.cfg.Model, Query: query, Documents: docs, TopN: len(docs)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.cfg.APIKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("rerank: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	results, err := parseRerankResults(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Results may be sorted by score; "index" identifies the document
+	scores := make([]float64, len(candidates))
+	seen := make([]bool, len(candidates))
+	for _, res := range results {
+		if res.Index < 0 || res.Index >= len(scores) {
+			return nil, fmt.Errorf("rerank returned out-of-range index %d", res.Index)
+		}
+		switch {
+		case res.RelevanceScore != nil:
+			scores[res.Index] = *res.RelevanceScore
+		case res.Score != nil:
+			scores[res.Index] = *res.Score
+		default:
+			return nil, fmt.Errorf("rerank result %d has no score", res.Index)
+		}
+		seen[res.Index] = true
+	}
+	for i, ok := range seen {
+		if !ok {
+			return nil, fmt.Errorf("rerank returned no score for document %d", i)
+		}
+	}
+	return scores, nil
+}
+
+// parseRerankResults accepts both response shapes: {"results": [...]}
+// (llama.cpp, Jina, Cohere) and a bare array (TEI).
+func parseRerankResults(data []byte) ([]rerankResult, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var results []rerankResult
+		if err := json.Unmarshal(data, &results); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		return results, nil
+	}
+
+	var wrapped struct {
+		Results []rerankResult `json:"results"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return wrapped.Results, nil
+}
+
+// documentText is the text scored for a candidate: headings for context,
+// then the chunk body, cut to MaxChars.
+func (r *HTTPReranker) documentText(c Candidate) string {
+	s := c.Chunk.Text
+	if len(c.Chunk.HeadingPath) > 0 {
+		s = strings.Join(c.Chunk.HeadingPath, " > ") + ": " + s
+	}
+	if len(s) > r.cfg.MaxChars {
+		s = strings.ToValidUTF8(s[:r.cfg.MaxChars], "")
+	}
+	return s
+}
@@ -0,0 +1,157 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bad33ndj3/mcp-md-index/internal/domain"
+)
+
+// fixedReranker returns preset scores, or an error.
+type fixedReranker struct {
+	scores []float64
+	err    error
+}
+
+func (r fixedReranker) Rerank(ctx context.Context, query string, candidates []Candidate) ([]float64, error) {
+	return r.scores, r.err
+}
+
+func rerankTestIndex() *domain.Index {
+	return &domain.Index{
+		DocID: "doc",
+		Path:  "api.md",
+		Chunks: []domain.Chunk{
+			{ChunkID: "1", Title: "Alpha", Text: "consumer consumer consumer", Terms: []string{"consumer", "consumer", "consumer"}},
+			{ChunkID: "2", Title: "Beta", Text: "consumer", Terms: []string{"consumer"}},
+		},
+		DocFreq:   map[string]int{"consumer": 2},
+		NumChunks: 2,
+	}
+}
+
+func TestBM25Searcher_RerankerReordersTopResults(t *testing.T) {
+	idx := rerankTestIndex()
+
+	plain := NewBM25Searcher().Search(idx, "consumer", 1000)
+	if strings.Index(plain, "Alpha") > strings.Index(plain, "Beta") {
+		t.Fatalf("expected BM25 to rank Alpha first, got:\n%s", plain)
+	}
+
+	reranked := NewBM25Searcher().WithReranker(fixedReranker{scores: []float64{0.1, 0.9}}, 10).Search(idx, "consumer", 1000)
+	if strings.Index(reranked, "Beta") > strings.Index(reranked, "Alpha") {
+		t.Errorf("expected reranker to move Beta first, got:\n%s", reranked)
+	}
+
+	failing := NewBM25Searcher().WithReranker(fixedReranker{err: errors.New("down")}, 10).Search(idx, "consumer", 1000)
+	if failing != plain {
+		t.Errorf("expected first-stage order when the reranker fails")
+	}
+}
+
+func TestHeuristicReranker_PrefersPhraseHeadingAndProximity(t *testing.T) {
+	candidates := []Candidate{
+		{Chunk: domain.Chunk{Title: "Overview", Text: "The ack policy is set per stream; wait for the ack in your consumer."}, Score: 1.0},
+		{Chunk: domain.Chunk{Title: "Ack Wait", HeadingPath: []string{"Consumers", "Ack Wait"}, Text: "Configure ack wait to control redelivery."}, Score: 0.8},
+	}
+
+	scores, err := NewHeuristicReranker().Rerank(context.Background(), "ack wait", candidates)
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+	if scores[1] <= scores[0] {
+		t.Errorf("expected exact phrase + heading match to win, got scores %v", scores)
+	}
+}
+
+func TestProximity(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []string
+		want   float64
+	}{
+		{"adjacent", []string{"ack", "wait", "time"}, 1.0},
+		{"gap", []string{"ack", "x", "x", "wait"}, 0.5},
+		{"one present", []string{"ack", "x"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := proximity(tt.tokens, []string{"ack", "wait"}); got != tt.want {
+				t.Errorf("proximity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPReranker_Llama(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llamaRerankRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req.Query != "ack wait" || len(req.Documents) != 2 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		if !strings.HasPrefix(req.Documents[1], "Consumers > Ack Wait: ") {
+			t.Errorf("expected heading context, got %q", req.Documents[1])
+		}
+		// Sorted by relevance, as llama.cpp returns them
+		w.Write([]byte(`{"results":[{"index":1,"relevance_score":0.95},{"index":0,"relevance_score":0.12}]}`))
+	}))
+	defer srv.Close()
+
+	r, err := NewHTTPReranker(HTTPRerankerConfig{URL: srv.URL + "/rerank"})
+	if err != nil {
+		t.Fatalf("NewHTTPReranker: %v", err)
+	}
+	scores, err := r.Rerank(context.Background(), "ack wait", []Candidate{
+		{Chunk: domain.Chunk{Text: "stream limits"}},
+		{Chunk: domain.Chunk{Text: "ack wait", HeadingPath: []string{"Consumers", "Ack Wait"}}},
+	})
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+	if scores[0] != 0.12 || scores[1] != 0.95 {
+		t.Errorf("scores = %v, want [0.12 0.95]", scores)
+	}
+}
+
+func TestHTTPReranker_TEI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req teiRerankRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Texts) != 2 {
+			t.Errorf("unexpected request: %+v (%v)", req, err)
+		}
+		w.Write([]byte(`[{"index":0,"score":0.3},{"index":1,"score":0.7}]`))
+	}))
+	defer srv.Close()
+
+	r, err := NewHTTPReranker(HTTPRerankerConfig{URL: srv.URL, API: RerankAPITEI})
+	if err != nil {
+		t.Fatalf("NewHTTPReranker: %v", err)
+	}
+	scores, err := r.Rerank(context.Background(), "q", []Candidate{{}, {}})
+	if err != nil {
+		t.Fatalf("Rerank: %v", err)
+	}
+	if scores[0] != 0.3 || scores[1] != 0.7 {
+		t.Errorf("scores = %v, want [0.3 0.7]", scores)
+	}
+}
+
+func TestHTTPReranker_ErrorsOnMissingScores(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"index":0,"relevance_score":0.5}]}`))
+	}))
+	defer srv.Close()
+
+	r, _ := NewHTTPReranker(HTTPRerankerConfig{URL: srv.URL})
+	if _, err := r.Rerank(context.Background(), "q", []Candidate{{}, {}}); err == nil {
+		t.Error("expected error when a document has no score")
+	}
+}
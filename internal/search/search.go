@@ -22,6 +22,62 @@ type Searcher interface {
 	Search(idx *domain.Index, query string, maxTokens int) string
 }
 
+// MultiSearcher is implemented by searchers that can rank chunks from many
+// documents in a single list (instead of searching one document at a time).
+type MultiSearcher interface {
+	SearchAll(indexes []*domain.Index, query string, maxTokens int) string
+}
+
+// OptionSearcher is implemented by searchers that accept per-query Options.
+type OptionSearcher interface {
+	SearchWithOptions(idx *domain.Index, query string, maxTokens int, opts Options) string
+}
+
+// MultiOptionSearcher is the per-query Options variant of MultiSearcher.
+type MultiOptionSearcher interface {
+	SearchAllWithOptions(indexes []*domain.Index, query string, maxTokens int, opts Options) string
+}
+
+// Retrieval modes for Options.Mode.
+const (
+	ModeBM25     = "bm25"     // Keyword ranking only
+	ModeSemantic = "semantic" // Embedding similarity only
+	ModeHybrid   = "hybrid"   // Both, fused (default)
+)
+
+// Options overrides retrieval settings for a single query.
+// Zero values keep the searcher's configured defaults.
+type Options struct {
+	Mode       string   // ModeBM25, ModeSemantic or ModeHybrid
+	Fusion     string   // FusionMethodRRF or FusionMethodWeighted
+	BM25Weight *float64 // Weighted fusion: BM25 share in [0, 1]; embeddings get the rest
+	RRFK       int      // RRF constant (must be positive when set)
+}
+
+// Validate reports invalid option values.
+func (o Options) Validate() error {
+	switch o.Mode {
+	case "", ModeBM25, ModeSemantic, ModeHybrid:
+	default:
+		return fmt.Errorf("invalid mode %q (want %s, %s or %s)", o.Mode, ModeBM25, ModeSemantic, ModeHybrid)
+	}
+	switch o.Fusion {
+	case "", FusionMethodRRF, FusionMethodWeighted:
+	default:
+		return fmt.Errorf("invalid fusion %q (want %s or %s)", o.Fusion, FusionMethodRRF, FusionMethodWeighted)
+	}
+	if o.BM25Weight != nil && (*o.BM25Weight < 0 || *o.BM25Weight > 1) {
+		return fmt.Errorf("bm25_weight must be between 0 and 1, got %g", *o.BM25Weight)
+	}
+	if o.RRFK < 0 {
+		return fmt.Errorf("rrf_k must be positive, got %d", o.RRFK)
+	}
+	return nil
+}
+
+// noResultsAll is returned when a cross-document search finds nothing.
+const noResultsAll = "No relevant excerpts found in any loaded document."
+
 // BM25Config holds the tuning parameters for BM25 scoring.
 type BM25Config struct {
 	K1        float64 // Term frequency saturation (default: 1.2)
@@ -41,6 +97,10 @@ func DefaultBM25Config() BM25Config {
 // BM25Searcher uses the BM25 algorithm for ranking chunks.
 type BM25Searcher struct {
 	config BM25Config
+
+	// Optional second stage (see WithReranker)
+	reranker   Reranker
+	rerankTopN int
 }
 
 // NewBM25Searcher creates a searcher with standard BM25 parameters.
@@ -170,6 +230,13 @@ func (s *BM25Searcher) scoreChunk(
 
 // scoreChunks ranks all chunks against the query using BM25.
 func (s *BM25Searcher) scoreChunks(idx *domain.Index, query string) []scoredChunk {
+	return s.scoreCorpus([]*domain.Index{idx}, query)
+}
+
+// scoreCorpus ranks the chunks of several documents against the query as
+// one corpus: IDF and average chunk length are computed over all of them, so
+// scores are comparable across documents.
+func (s *BM25Searcher) scoreCorpus(indexes []*domain.Index, query string) []scoredChunk {
 	queryTerms := text.NormalizeTerms(query)
 	if len(queryTerms) == 0 {
 		return nil
@@ -181,35 +248,48 @@ func (s *BM25Searcher) scoreChunks(idx *domain.Index, query string) []scoredChun
 		queryTermCounts[t]++
 	}
 
-	numChunks := float64(idx.NumChunks)
+	// Corpus statistics: chunk count, query term document frequencies and
+	// average chunk length (needed for length normalization)
+	numChunks, avgLen := 0.0, 0.0
+	docFreq := make(map[string]int, len(queryTermCounts))
+	for _, idx := range indexes {
+		numChunks += float64(idx.NumChunks)
+		for term := range queryTermCounts {
+			docFreq[term] += idx.DocFreq[term]
+		}
+		for _, c := range idx.Chunks {
+			avgLen += float64(len(c.Terms))
+		}
+	}
 	if numChunks == 0 {
 		return nil
 	}
-
-	// Calculate average chunk length (needed for length normalization)
-	avgLen := 0.0
-	for _, c := range idx.Chunks {
-		avgLen += float64(len(c.Terms))
-	}
 	avgLen /= numChunks
 
 	// Score all chunks
-	results := make([]scoredChunk, 0, len(idx.Chunks))
-	for _, chunk := range idx.Chunks {
-		score := s.scoreChunk(chunk, queryTermCounts, idx.DocFreq, numChunks, avgLen)
-		if score > 0 {
-			results = append(results, scoredChunk{chunk: chunk, score: score})
+	var results []scoredChunk
+	for _, idx := range indexes {
+		for _, chunk := range idx.Chunks {
+			score := s.scoreChunk(chunk, queryTermCounts, docFreq, numChunks, avgLen)
+			if score > 0 {
+				results = append(results, scoredChunk{chunk: chunk, score: score})
+			}
 		}
 	}
 
 	// Sort by score (best first)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].score > results[j].score
-	})
+	sortByScore(results)
 
 	return results
 }
 
+// sortByScore orders scored chunks best first.
+func sortByScore(scored []scoredChunk) {
+	sort.Slice(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+}
+
 // ─────────────────────────────────────────────────────────────────────────────
 // Search (public API)
 // ─────────────────────────────────────────────────────────────────────────────
@@ -225,7 +305,7 @@ func (s *BM25Searcher) Search(idx *domain.Index, query string, maxTokens int) st
 		return "No relevant excerpts found in the indexed document."
 	}
 
-	return s.buildResponse(scored, maxTokens)
+	return s.buildResponse(s.rerank(query, scored), maxTokens)
 }
 
 // buildResponse assembles excerpts into a formatted response.
@@ -3,7 +3,9 @@
 package testutil
 
 import (
+	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/bad33ndj3/mcp-md-index/internal/domain"
@@ -14,9 +16,12 @@ var ErrNotFound = errors.New("not found")
 
 // MockCache is a simple in-memory cache for testing.
 // It separates memory and disk caches to test caching behavior.
+// Its methods are safe for concurrent use; the maps are not.
 type MockCache struct {
 	Mem  map[string]*domain.Index
 	Disk map[string]*domain.Index
+
+	mu sync.Mutex
 }
 
 // NewMockCache creates a new MockCache with initialized maps.
@@ -28,6 +33,8 @@ func NewMockCache() *MockCache {
 }
 
 func (m *MockCache) Get(docID string) (*domain.Index, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if idx, ok := m.Mem[docID]; ok {
 		return idx, nil
 	}
@@ -35,10 +42,14 @@ func (m *MockCache) Get(docID string) (*domain.Index, error) {
 }
 
 func (m *MockCache) Set(docID string, idx *domain.Index) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Mem[docID] = idx
 }
 
 func (m *MockCache) LoadFromDisk(docID string) (*domain.Index, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if idx, ok := m.Disk[docID]; ok {
 		return idx, nil
 	}
@@ -46,10 +57,16 @@ func (m *MockCache) LoadFromDisk(docID string) (*domain.Index, error) {
 }
 
 func (m *MockCache) SaveToDisk(idx *domain.Index) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Disk[idx.DocID] = idx
 	return nil
 }
 
+func (m *MockCache) LoadVectors(idx *domain.Index) error {
+	return nil // Vectors stay on the in-memory chunks
+}
+
 func (m *MockCache) MarkdownPath(docID string) string {
 	return "/mock/cache/" + docID + ".md"
 }
@@ -59,6 +76,8 @@ func (m *MockCache) SaveMarkdown(docID string, content string) (string, error) {
 }
 
 func (m *MockCache) List() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	docIDs := make([]string, 0, len(m.Mem))
 	for docID := range m.Mem {
 		docIDs = append(docIDs, docID)
@@ -130,3 +149,48 @@ func NewMockClock(t time.Time) MockClock {
 }
 
 func (m MockClock) Now() time.Time { return m.Time }
+
+// MockEmbedder returns a fixed 2-dimensional vector for every text.
+// Calls counts EmbedBatch invocations so tests can assert re-embedding.
+type MockEmbedder struct {
+	ModelName string
+
+	mu    sync.Mutex
+	Calls int
+}
+
+func (m *MockEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{1, 0}, nil
+}
+
+func (m *MockEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	m.mu.Lock()
+	m.Calls++
+	m.mu.Unlock()
+
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = []float32{1, 0}
+	}
+	return out, nil
+}
+
+func (m *MockEmbedder) Available(ctx context.Context) bool { return true }
+
+func (m *MockEmbedder) Provider() string { return "mock" }
+
+func (m *MockEmbedder) Dimensions() int { return 2 }
+
+func (m *MockEmbedder) Model() string {
+	if m.ModelName == "" {
+		return "mock"
+	}
+	return m.ModelName
+}
+
+// CallCount returns the number of EmbedBatch calls so far.
+func (m *MockEmbedder) CallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Calls
+}
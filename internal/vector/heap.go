@@ -0,0 +1,45 @@
+package vector
+
+import "sort"
+
+// candidate pairs a node index with its similarity to the query.
+type candidate struct {
+	id  int32
+	sim float64
+}
+
+// candidates is a list of scored nodes.
+type candidates []candidate
+
+// sortDesc orders candidates by similarity, most similar first.
+func sortDesc(c candidates) {
+	sort.Slice(c, func(i, j int) bool { return c[i].sim > c[j].sim })
+}
+
+// maxHeap pops the most similar candidate first.
+type maxHeap candidates
+
+func (h maxHeap) Len() int           { return len(h) }
+func (h maxHeap) Less(i, j int) bool { return h[i].sim > h[j].sim }
+func (h maxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// minHeap pops the least similar candidate first.
+type minHeap candidates
+
+func (h minHeap) Len() int           { return len(h) }
+func (h minHeap) Less(i, j int) bool { return h[i].sim < h[j].sim }
+func (h minHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+func (h *minHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,508 @@
+// Package vector provides an in-process approximate nearest-neighbour (ANN)
+// index over chunk embeddings, so semantic search does not have to compare the
+// query against every chunk of every document.
+//
+// The index is an HNSW graph (Hierarchical Navigable Small World, Malkov &
+// Yashunin 2016): each vector is linked to its closest neighbours on several
+// layers, upper layers are sparse "express lanes", and a query greedily walks
+// from the top layer down. Search cost grows roughly logarithmically with the
+// number of vectors.
+package vector
+
+import (
+	"container/heap"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"sync"
+)
+
+// ErrDimensionMismatch is returned when a vector's length differs from the index.
+var ErrDimensionMismatch = errors.New("vector dimension mismatch")
+
+// Config holds HNSW tuning parameters.
+type Config struct {
+	M              int // Max links per node on upper layers (layer 0 allows 2*M)
+	EfConstruction int // Candidate list size while inserting (higher = better graph)
+	EfSearch       int // Candidate list size while querying (higher = better recall)
+}
+
+// DefaultConfig returns parameters that work well for a few hundred thousand chunks.
+func DefaultConfig() Config {
+	return Config{
+		M:              16,
+		EfConstruction: 200,
+		EfSearch:       64,
+	}
+}
+
+// Hit is one search result.
+type Hit struct {
+	ID    string  // Chunk ID
+	DocID string  // Document the chunk belongs to
+	Score float64 // Cosine similarity in [-1, 1]
+}
+
+// node is one vector in the graph.
+type node struct {
+	ID      string
+	DocID   string
+	Vec     []float32 // L2-normalized copy of the embedding
+	Links   [][]int32 // Links[layer] = neighbour node indexes
+	Deleted bool      // Tombstone: still routable, never returned
+}
+
+// HNSW is a thread-safe approximate nearest-neighbour index.
+// Vectors are grouped by document so a re-indexed doc can be replaced wholesale.
+type HNSW struct {
+	mu   sync.RWMutex
+	cfg  Config
+	path string // Persistence file ("" = memory only)
+
+	nodes    []*node
+	ids      map[string]int32 // chunk ID -> live node index
+	docs     map[string]int   // doc ID -> live vector count
+	entry    int32            // entry point node (-1 when empty)
+	maxLevel int
+	dims     int
+	deleted  int
+	levelMul float64
+}
+
+// New creates an empty in-memory index.
+func New(cfg Config) *HNSW {
+	def := DefaultConfig()
+	if cfg.M <= 1 {
+		cfg.M = def.M
+	}
+	if cfg.EfConstruction <= 0 {
+		cfg.EfConstruction = def.EfConstruction
+	}
+	if cfg.EfSearch <= 0 {
+		cfg.EfSearch = def.EfSearch
+	}
+	return &HNSW{
+		cfg:      cfg,
+		ids:      make(map[string]int32),
+		docs:     make(map[string]int),
+		entry:    -1,
+		levelMul: 1 / math.Log(float64(cfg.M)),
+	}
+}
+
+// NewFile creates an empty index bound to path, replacing whatever is there
+// on the next Save.
+func NewFile(path string, cfg Config) *HNSW {
+	h := New(cfg)
+	h.path = path
+	return h
+}
+
+// Open loads the index persisted at path, or creates an empty one bound to path
+// if the file does not exist. Save writes back to the same path.
+func Open(path string, cfg Config) (*HNSW, error) {
+	h := NewFile(path, cfg)
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, fmt.Errorf("open vector index: %w", err)
+	}
+	defer f.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("decode vector index: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		// Incompatible format: start over, vectors get re-added on load
+		return h, nil
+	}
+
+	h.nodes = snap.Nodes
+	h.entry = snap.Entry
+	h.maxLevel = snap.MaxLevel
+	h.dims = snap.Dims
+	for i, n := range h.nodes {
+		if n.Deleted {
+			h.deleted++
+			continue
+		}
+		h.ids[n.ID] = int32(i)
+		h.docs[n.DocID]++
+	}
+	return h, nil
+}
+
+// snapshotVersion is bumped when the on-disk format changes.
+const snapshotVersion = 1
+
+// snapshot is the gob-encoded on-disk format.
+type snapshot struct {
+	Version  int
+	Nodes    []*node
+	Entry    int32
+	MaxLevel int
+	Dims     int
+}
+
+// Save persists the index to the path given to Open. No-op for memory-only indexes.
+func (h *HNSW) Save() error {
+	if h.path == "" {
+		return nil
+	}
+
+	h.mu.RLock()
+	snap := snapshot{
+		Version:  snapshotVersion,
+		Nodes:    h.nodes,
+		Entry:    h.entry,
+		MaxLevel: h.maxLevel,
+		Dims:     h.dims,
+	}
+	tmp := h.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err == nil {
+		err = gob.NewEncoder(f).Encode(&snap)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	h.mu.RUnlock()
+
+	if err != nil {
+		return fmt.Errorf("write vector index: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return fmt.Errorf("write vector index: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of live vectors.
+func (h *HNSW) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.ids)
+}
+
+// Dims returns the vector dimension (0 while empty).
+func (h *HNSW) Dims() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.dims
+}
+
+// HasDoc reports whether any live vectors belong to docID.
+func (h *HNSW) HasDoc(docID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.docs[docID] > 0
+}
+
+// Reset removes every vector, e.g. when the embedding model changes.
+func (h *HNSW) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.resetLocked()
+}
+
+func (h *HNSW) resetLocked() {
+	h.nodes = nil
+	h.ids = make(map[string]int32)
+	h.docs = make(map[string]int)
+	h.entry = -1
+	h.maxLevel = 0
+	h.dims = 0
+	h.deleted = 0
+}
+
+// Add inserts a vector. An existing vector with the same ID is replaced.
+func (h *HNSW) Add(id, docID string, vec []float32) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.addLocked(id, docID, vec)
+}
+
+// RemoveDoc tombstones every vector belonging to docID.
+// The graph is rebuilt once tombstones outnumber live vectors.
+func (h *HNSW) RemoveDoc(docID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.docs[docID] == 0 {
+		return
+	}
+	for id, i := range h.ids {
+		if h.nodes[i].DocID == docID {
+			h.nodes[i].Deleted = true
+			delete(h.ids, id)
+			h.deleted++
+		}
+	}
+	delete(h.docs, docID)
+
+	if h.deleted > len(h.ids) {
+		h.compactLocked()
+	}
+}
+
+// Search returns up to k vectors most similar to query, best first.
+// If keep is non-nil, only vectors whose doc ID passes keep are returned.
+func (h *HNSW) Search(query []float32, k int, keep func(docID string) bool) []Hit {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.entry < 0 || k <= 0 || len(query) != h.dims {
+		return nil
+	}
+	q := normalize(query)
+
+	ep := h.entry
+	for l := h.maxLevel; l > 0; l-- {
+		ep = h.greedyLocked(q, ep, l)
+	}
+
+	// Filters and tombstones shrink the result set, so widen the beam until
+	// it yields k hits or holds the whole graph
+	ef := max(h.cfg.EfSearch, k)
+	if keep != nil || h.deleted > 0 {
+		ef = max(ef, 4*k)
+	}
+	for {
+		hits := make([]Hit, 0, k)
+		for _, c := range h.searchLayerLocked(q, []int32{ep}, ef, 0) {
+			n := h.nodes[c.id]
+			if n.Deleted || (keep != nil && !keep(n.DocID)) {
+				continue
+			}
+			hits = append(hits, Hit{ID: n.ID, DocID: n.DocID, Score: c.sim})
+			if len(hits) == k {
+				break
+			}
+		}
+		if len(hits) == k || ef >= len(h.nodes) {
+			return hits
+		}
+		ef *= 4
+	}
+}
+
+// addLocked inserts one vector. Caller must hold h.mu.
+func (h *HNSW) addLocked(id, docID string, vec []float32) error {
+	if len(vec) == 0 {
+		return fmt.Errorf("empty vector for %s", id)
+	}
+	if h.dims != 0 && len(vec) != h.dims {
+		return fmt.Errorf("%w: got %d, index has %d", ErrDimensionMismatch, len(vec), h.dims)
+	}
+	h.dims = len(vec)
+
+	if old, ok := h.ids[id]; ok {
+		h.nodes[old].Deleted = true
+		h.docs[h.nodes[old].DocID]--
+		h.deleted++
+	}
+
+	level := h.randomLevel()
+	n := &node{ID: id, DocID: docID, Vec: normalize(vec), Links: make([][]int32, level+1)}
+	idx := int32(len(h.nodes))
+	h.nodes = append(h.nodes, n)
+	h.ids[id] = idx
+	h.docs[docID]++
+
+	if h.entry < 0 {
+		h.entry = idx
+		h.maxLevel = level
+		return nil
+	}
+
+	ep := h.entry
+	for l := h.maxLevel; l > level; l-- {
+		ep = h.greedyLocked(n.Vec, ep, l)
+	}
+
+	eps := []int32{ep}
+	for l := min(level, h.maxLevel); l >= 0; l-- {
+		cands := h.searchLayerLocked(n.Vec, eps, h.cfg.EfConstruction, l)
+		maxLinks := h.maxLinks(l)
+
+		neighbours := make([]int32, 0, maxLinks)
+		for _, c := range cands {
+			if c.id == idx {
+				continue
+			}
+			neighbours = append(neighbours, c.id)
+			if len(neighbours) == maxLinks {
+				break
+			}
+		}
+		n.Links[l] = neighbours
+
+		for _, nb := range neighbours {
+			h.linkLocked(nb, idx, l)
+		}
+
+		eps = eps[:0]
+		for _, c := range cands {
+			eps = append(eps, c.id)
+		}
+	}
+
+	if level > h.maxLevel {
+		h.maxLevel = level
+		h.entry = idx
+	}
+	return nil
+}
+
+// linkLocked adds a link from -> to on layer l, pruning to the closest neighbours.
+func (h *HNSW) linkLocked(from, to int32, l int) {
+	n := h.nodes[from]
+	if l >= len(n.Links) {
+		return
+	}
+	n.Links[l] = append(n.Links[l], to)
+
+	maxLinks := h.maxLinks(l)
+	if len(n.Links[l]) <= maxLinks {
+		return
+	}
+
+	// Keep the maxLinks most similar neighbours
+	scored := make(candidates, len(n.Links[l]))
+	for i, nb := range n.Links[l] {
+		scored[i] = candidate{id: nb, sim: dot(n.Vec, h.nodes[nb].Vec)}
+	}
+	sortDesc(scored)
+	links := make([]int32, maxLinks)
+	for i := range links {
+		links[i] = scored[i].id
+	}
+	n.Links[l] = links
+}
+
+// greedyLocked walks layer l towards q, returning the closest node found.
+func (h *HNSW) greedyLocked(q []float32, ep int32, l int) int32 {
+	best := ep
+	bestSim := dot(q, h.nodes[ep].Vec)
+	for changed := true; changed; {
+		changed = false
+		for _, nb := range h.nodes[best].Links[l] {
+			if sim := dot(q, h.nodes[nb].Vec); sim > bestSim {
+				best, bestSim, changed = nb, sim, true
+			}
+		}
+	}
+	return best
+}
+
+// searchLayerLocked runs a beam search of width ef on layer l.
+// It returns the candidates found, most similar first.
+func (h *HNSW) searchLayerLocked(q []float32, eps []int32, ef, l int) candidates {
+	visited := make(map[int32]struct{}, ef*4)
+	frontier := &maxHeap{} // best candidate to expand next
+	results := &minHeap{}  // current top-ef, worst on top
+
+	for _, ep := range eps {
+		if _, seen := visited[ep]; seen {
+			continue
+		}
+		visited[ep] = struct{}{}
+		c := candidate{id: ep, sim: dot(q, h.nodes[ep].Vec)}
+		heap.Push(frontier, c)
+		heap.Push(results, c)
+	}
+	for results.Len() > ef {
+		heap.Pop(results)
+	}
+
+	for frontier.Len() > 0 {
+		c := heap.Pop(frontier).(candidate)
+		if results.Len() >= ef && c.sim < (*results)[0].sim {
+			break
+		}
+		n := h.nodes[c.id]
+		if l >= len(n.Links) {
+			continue
+		}
+		for _, nb := range n.Links[l] {
+			if _, seen := visited[nb]; seen {
+				continue
+			}
+			visited[nb] = struct{}{}
+
+			sim := dot(q, h.nodes[nb].Vec)
+			if results.Len() < ef || sim > (*results)[0].sim {
+				heap.Push(frontier, candidate{id: nb, sim: sim})
+				heap.Push(results, candidate{id: nb, sim: sim})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	out := make(candidates, results.Len())
+	copy(out, *results)
+	sortDesc(out)
+	return out
+}
+
+// compactLocked rebuilds the graph from live vectors, dropping tombstones.
+func (h *HNSW) compactLocked() {
+	live := make([]*node, 0, len(h.ids))
+	for _, n := range h.nodes {
+		if !n.Deleted {
+			live = append(live, n)
+		}
+	}
+	h.resetLocked()
+	for _, n := range live {
+		_ = h.addLocked(n.ID, n.DocID, n.Vec) // Same dims, cannot fail
+	}
+}
+
+// maxLinks returns the link budget for layer l.
+func (h *HNSW) maxLinks(l int) int {
+	if l == 0 {
+		return 2 * h.cfg.M
+	}
+	return h.cfg.M
+}
+
+// randomLevel draws a layer with exponentially decaying probability.
+func (h *HNSW) randomLevel() int {
+	return int(math.Floor(-math.Log(1-rand.Float64()) * h.levelMul))
+}
+
+// normalize returns an L2-normalized copy of v, so dot product == cosine.
+func normalize(v []float32) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if norm == 0 {
+		return out
+	}
+	inv := 1 / math.Sqrt(norm)
+	for i, x := range v {
+		out[i] = float32(float64(x) * inv)
+	}
+	return out
+}
+
+// dot computes the dot product of two equal-length vectors.
+func dot(a, b []float32) float64 {
+	var s float64
+	for i := range a {
+		s += float64(a[i]) * float64(b[i])
+	}
+	return s
+}
@@ -0,0 +1,152 @@
+package vector
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// randomVectors returns n deterministic random vectors of the given dimension.
+func randomVectors(n, dims int) [][]float32 {
+	r := rand.New(rand.NewPCG(1, 2))
+	out := make([][]float32, n)
+	for i := range out {
+		v := make([]float32, dims)
+		for j := range v {
+			v[j] = float32(r.NormFloat64())
+		}
+		out[i] = v
+	}
+	return out
+}
+
+// bruteForce returns the IDs of the k vectors most similar to q.
+func bruteForce(vecs [][]float32, q []float32, k int) []string {
+	type scored struct {
+		id  string
+		sim float64
+	}
+	qn := normalize(q)
+	all := make([]scored, len(vecs))
+	for i, v := range vecs {
+		all[i] = scored{id: fmt.Sprint(i), sim: dot(qn, normalize(v))}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].sim > all[j].sim })
+	ids := make([]string, k)
+	for i := range ids {
+		ids[i] = all[i].id
+	}
+	return ids
+}
+
+func TestHNSW_RecallAgainstBruteForce(t *testing.T) {
+	vecs := randomVectors(2000, 32)
+	h := New(DefaultConfig())
+	for i, v := range vecs {
+		if err := h.Add(fmt.Sprint(i), fmt.Sprintf("doc%d", i%10), v); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	queries := randomVectors(50, 32)
+	const k = 10
+	found, total := 0, 0
+	for _, q := range queries {
+		want := make(map[string]bool)
+		for _, id := range bruteForce(vecs, q, k) {
+			want[id] = true
+		}
+		for _, hit := range h.Search(q, k, nil) {
+			if want[hit.ID] {
+				found++
+			}
+		}
+		total += k
+	}
+
+	if recall := float64(found) / float64(total); recall < 0.9 {
+		t.Errorf("recall@%d = %.2f, want >= 0.90", k, recall)
+	}
+}
+
+func TestHNSW_RemoveDocAndFilter(t *testing.T) {
+	vecs := randomVectors(200, 8)
+	h := New(DefaultConfig())
+	for i, v := range vecs {
+		_ = h.Add(fmt.Sprint(i), fmt.Sprintf("doc%d", i%2), v)
+	}
+
+	h.RemoveDoc("doc0")
+	if h.HasDoc("doc0") {
+		t.Error("doc0 should be gone after RemoveDoc")
+	}
+	for _, hit := range h.Search(vecs[0], 20, nil) {
+		if hit.DocID == "doc0" {
+			t.Fatalf("search returned removed vector %s", hit.ID)
+		}
+	}
+
+	onlyDoc1 := func(docID string) bool { return docID == "doc1" }
+	if hits := h.Search(vecs[1], 5, onlyDoc1); len(hits) != 5 {
+		t.Errorf("expected 5 filtered hits, got %d", len(hits))
+	}
+}
+
+func TestHNSW_SelectiveFilterRecall(t *testing.T) {
+	// 100 docs of 20 chunks; a filter on one doc must still find all of it
+	vecs := randomVectors(2000, 16)
+	h := New(DefaultConfig())
+	for i, v := range vecs {
+		_ = h.Add(fmt.Sprint(i), fmt.Sprintf("doc%d", i/20), v)
+	}
+
+	onlyDoc7 := func(docID string) bool { return docID == "doc7" }
+	for _, q := range randomVectors(5, 16) {
+		hits := h.Search(q, 50, onlyDoc7)
+		if len(hits) != 20 {
+			t.Fatalf("filtered search returned %d of doc7's 20 vectors", len(hits))
+		}
+		for i := 1; i < len(hits); i++ {
+			if hits[i].Score > hits[i-1].Score {
+				t.Fatalf("hits not ordered by score: %+v", hits)
+			}
+		}
+	}
+}
+
+func TestHNSW_DimensionMismatch(t *testing.T) {
+	h := New(DefaultConfig())
+	_ = h.Add("a", "doc", []float32{1, 0})
+	if err := h.Add("b", "doc", []float32{1, 0, 0}); err == nil {
+		t.Error("expected dimension mismatch error")
+	}
+}
+
+func TestHNSW_SaveAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vectors.hnsw")
+	h, err := Open(path, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	vecs := randomVectors(100, 8)
+	for i, v := range vecs {
+		_ = h.Add(fmt.Sprint(i), "doc", v)
+	}
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reopened, err := Open(path, DefaultConfig())
+	if err != nil {
+		t.Fatalf("Open (reload): %v", err)
+	}
+	if reopened.Len() != 100 || !reopened.HasDoc("doc") {
+		t.Fatalf("reloaded index has %d vectors", reopened.Len())
+	}
+	hits := reopened.Search(vecs[42], 1, nil)
+	if len(hits) != 1 || hits[0].ID != "42" {
+		t.Errorf("expected exact match for vector 42, got %+v", hits)
+	}
+}
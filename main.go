@@ -21,6 +21,7 @@ import (
 	mcphandlers "github.com/bad33ndj3/mcp-md-index/internal/mcp"
 	"github.com/bad33ndj3/mcp-md-index/internal/parser"
 	"github.com/bad33ndj3/mcp-md-index/internal/search"
+	"github.com/bad33ndj3/mcp-md-index/internal/vector"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -28,6 +29,20 @@ const (
 	serverName      = "mcp-md-index"
 	serverVersion   = "v0.2.0"
 	defaultCacheDir = ".mcp-cache"
+
+	// Embedding providers selectable with --embedding-provider
+	providerOllama  = "ollama"
+	providerOpenAI  = "openai"
+	providerBuiltin = "builtin"
+
+	// Markdown parsers selectable with --markdown-parser
+	parserCommonMark = "commonmark"
+	parserLegacy     = "legacy"
+
+	// Rerankers selectable with --rerank
+	rerankNone      = "none"
+	rerankHeuristic = "heuristic"
+	rerankHTTP      = "http"
 )
 
 // setupLogger creates an slog logger that writes to a debug file in the cache directory.
@@ -63,11 +78,27 @@ func main() {
 	// --- 0. Parse flags ---
 	cacheDir := flag.String("cache-dir", defaultCacheDir, "Directory for cache and log files")
 	experimentalEmbeddings := flag.Bool("experimental-embeddings", false,
-		"Enable Ollama-based semantic search (experimental, non-blocking)")
+		"Enable embedding-based semantic search (experimental, non-blocking)")
 	ollamaHost := flag.String("ollama-host", "http://localhost:11434",
 		"Ollama server URL for embeddings")
 	ollamaModel := flag.String("ollama-model", "nomic-embed-text",
 		"Ollama embedding model to use")
+	embeddingProvider := flag.String("embedding-provider", providerOllama,
+		"Embedding backend: 'ollama', 'openai' (any OpenAI-compatible /v1/embeddings server) or 'builtin' (in-process, no service needed)")
+	openaiBaseURL := flag.String("openai-base-url", "http://localhost:8080/v1",
+		"Base URL of the OpenAI-compatible API (llama.cpp, LM Studio, vLLM, LocalAI)")
+	openaiModel := flag.String("openai-model", "nomic-embed-text",
+		"Embedding model name for the OpenAI-compatible API")
+	openaiDimensions := flag.Int("openai-dimensions", 0,
+		"Requested embedding dimensions (0 = server default)")
+	openaiAPIKey := flag.String("openai-api-key", os.Getenv("OPENAI_API_KEY"),
+		"API key for the OpenAI-compatible API (default: $OPENAI_API_KEY)")
+	openaiAPIKeyHeader := flag.String("openai-api-key-header", "Authorization",
+		"Header used to send the API key ('Authorization' sends a Bearer token)")
+	embeddingBatchSize := flag.Int("embedding-batch-size", 32,
+		"Maximum chunks per embeddings request")
+	builtinDimensions := flag.Int("builtin-dimensions", embedding.DefaultHashDimensions,
+		"Vector size for the builtin hashing embedder")
 
 	// Hybrid search flags
 	fusionMethod := flag.String("hybrid-fusion-method", search.FusionMethodRRF,
@@ -80,6 +111,40 @@ func main() {
 		"K constant for Reciprocal Rank Fusion")
 	maxConcurrent := flag.Int("max-concurrent-embeddings", 2,
 		"Maximum number of concurrent embedding tasks")
+	vectorEncoding := flag.String("vector-encoding", "float32",
+		"On-disk embedding format: 'float32', 'float16' or 'int8' (quantized)")
+	markdownParser := flag.String("markdown-parser", parserCommonMark,
+		"Markdown chunker: 'commonmark' (CommonMark/GFM syntax tree) or 'legacy' (line regexes)")
+	notebookOutputs := flag.Bool("notebook-outputs", false,
+		"Index text outputs of Jupyter notebook code cells as quoted blocks")
+	rerankMode := flag.String("rerank", rerankNone,
+		"Second-stage reranker: 'none', 'heuristic' (phrase/heading/proximity) or 'http' (cross-encoder server)")
+	rerankURL := flag.String("rerank-url", "http://localhost:8080/rerank",
+		"Rerank endpoint for -rerank http")
+	rerankAPI := flag.String("rerank-api", search.RerankAPILlama,
+		"Rerank API flavour for -rerank http: 'llama' (llama.cpp) or 'tei' (text-embeddings-inference)")
+	rerankModel := flag.String("rerank-model", "",
+		"Model name sent to the rerank server (optional)")
+	rerankTopN := flag.Int("rerank-top-n", search.DefaultRerankTopN,
+		"Number of first-stage results to rerank")
+	queryCacheSize := flag.Int("query-cache-size", embedding.DefaultQueryCacheSize,
+		"Number of query embeddings to keep in memory (0 disables the cache)")
+	siteTTL := flag.Duration("site-ttl", indexer.DefaultSiteTTL,
+		"How long fetched website documents stay fresh before they are revalidated (0: never stale)")
+	siteConfigPath := flag.String("site-config", "",
+		"YAML file with per-site settings, such as CSS selectors for main-content extraction")
+	extractMainContent := flag.Bool("extract-main-content", true,
+		"Reduce fetched HTML pages to their main content, dropping navigation, sidebars and footers")
+	fetchTimeout := flag.Duration("fetch-timeout", fetcher.DefaultFetchTimeout,
+		"Timeout for each website request, including reading the response")
+	fetchAttempts := flag.Int("fetch-attempts", fetcher.DefaultMaxAttempts,
+		"Attempts per website request; timeouts, dropped connections and 429/502/503/504 responses are retried with backoff")
+	fetchHostConcurrency := flag.Int("fetch-host-concurrency", fetcher.DefaultHostConcurrency,
+		"Maximum concurrent requests to one website host")
+	fetchHostRate := flag.Float64("fetch-host-rate", fetcher.DefaultHostRate,
+		"Maximum requests per second to one website host (negative: unlimited)")
+	fetchMaxBody := flag.Int64("fetch-max-body", fetcher.DefaultMaxBodySize,
+		"Largest website response accepted, in bytes")
 
 	flag.Parse()
 
@@ -103,45 +168,124 @@ func main() {
 	// --- 1. Create all dependencies ---
 
 	// Cache: stores parsed indexes in memory and on disk
-	fileCache, err := cache.NewFileCache(*cacheDir)
+	encoding, err := cache.ParseVectorEncoding(*vectorEncoding)
+	if err != nil {
+		log.Fatalf("Invalid -vector-encoding: %v", err)
+	}
+	fileCache, err := cache.NewFileCache(*cacheDir, cache.WithVectorEncoding(encoding))
 	if err != nil {
 		logger.Error("failed to create cache", "error", err)
 		log.Fatalf("Failed to create cache: %v", err)
 	}
 
-	// Parser: splits markdown into searchable chunks
-	mdParser := parser.NewMarkdownParser()
+	// Parser: splits documents into searchable chunks, chosen by file type
+	var mdParser parser.Parser
+	switch *markdownParser {
+	case parserLegacy:
+		mdParser = parser.NewMarkdownParser()
+	case parserCommonMark:
+		mdParser = parser.NewCommonMarkParser()
+	default:
+		log.Fatalf("Invalid -markdown-parser %q (want %s or %s)", *markdownParser, parserCommonMark, parserLegacy)
+	}
 
 	// --- 2. Setup Searcher (BM25 or Hybrid) ---
 	var searcher search.Searcher
 	var embedder embedding.Embedder
 	var embedStatus *embedding.Status
+	var vectors *vector.HNSW
 
 	if *experimentalEmbeddings {
-		embedCfg := embedding.Config{
-			Host:  *ollamaHost,
-			Model: *ollamaModel,
-		}
 		var err error
-		embedder, err = embedding.NewOllamaEmbedder(embedCfg)
+		var model, host string
+		switch *embeddingProvider {
+		case providerOllama:
+			model, host = *ollamaModel, *ollamaHost
+			embedder, err = embedding.NewOllamaEmbedder(embedding.Config{
+				Host:  *ollamaHost,
+				Model: *ollamaModel,
+			})
+		case providerOpenAI:
+			model, host = *openaiModel, *openaiBaseURL
+			embedder, err = embedding.NewOpenAIEmbedder(embedding.OpenAIConfig{
+				BaseURL:      *openaiBaseURL,
+				Model:        *openaiModel,
+				Dimensions:   *openaiDimensions,
+				APIKey:       *openaiAPIKey,
+				APIKeyHeader: *openaiAPIKeyHeader,
+				BatchSize:    *embeddingBatchSize,
+			})
+		case providerBuiltin:
+			model, host = "feature-hashing", "in-process"
+			embedder = embedding.NewHashEmbedder(*builtinDimensions)
+		default:
+			err = fmt.Errorf("unknown embedding provider %q", *embeddingProvider)
+		}
 		if err != nil {
 			logger.Warn("failed to create embedder, using BM25 only", "error", err)
+			embedder = nil
 			searcher = search.NewBM25Searcher()
 		} else {
+			if *queryCacheSize > 0 {
+				embedder = embedding.NewCachedEmbedder(embedder, *queryCacheSize)
+			}
 			embedStatus = embedding.NewStatus()
+
+			// Vector index: persisted next to the JSON indexes
+			vectorPath := filepath.Join(*cacheDir, "vectors.hnsw")
+			vectors, err = vector.Open(vectorPath, vector.DefaultConfig())
+			if err != nil {
+				// Rebuilt from the cached embeddings, and saved over the bad file
+				logger.Warn("failed to open vector index, starting empty", "error", err)
+				vectors = vector.NewFile(vectorPath, vector.DefaultConfig())
+			}
+
 			hybrid := search.NewHybridSearcher(embedder, embedStatus)
 			hybrid.WithFusionMethod(*fusionMethod, *bm25Weight, *embedWeight, *rrfK)
+			hybrid.WithVectorIndex(vectors)
+			hybrid.WithVectorLoader(fileCache.LoadVectors)
 			searcher = hybrid
 
 			logger.Info("experimental embeddings enabled (async)",
-				"model", *ollamaModel,
-				"host", *ollamaHost,
+				"provider", *embeddingProvider,
+				"model", model,
+				"host", host,
 				"fusion", *fusionMethod)
 		}
 	} else {
 		searcher = search.NewBM25Searcher()
 	}
 
+	// Reranker: optional second stage over the top results
+	var reranker search.Reranker
+	switch *rerankMode {
+	case rerankNone, "":
+	case rerankHeuristic:
+		reranker = search.NewHeuristicReranker()
+	case rerankHTTP:
+		r, err := search.NewHTTPReranker(search.HTTPRerankerConfig{
+			URL:   *rerankURL,
+			API:   *rerankAPI,
+			Model: *rerankModel,
+		})
+		if err != nil {
+			logger.Warn("failed to create reranker, reranking disabled", "error", err)
+		} else {
+			reranker = r
+		}
+	default:
+		logger.Warn("unknown reranker, reranking disabled", "rerank", *rerankMode)
+	}
+	if reranker != nil {
+		switch s := searcher.(type) {
+		case *search.HybridSearcher:
+			s.WithReranker(reranker, *rerankTopN)
+		case *search.BM25Searcher:
+			s.WithReranker(reranker, *rerankTopN)
+		}
+		logger.Info("reranking enabled", "rerank", *rerankMode, "top_n", *rerankTopN)
+	}
+
 	// File reader: reads from the actual filesystem
 	fileReader := indexer.OSFileReader{}
 
@@ -149,18 +293,44 @@ func main() {
 	clock := indexer.RealClock{}
 
 	// Site fetcher: converts websites to markdown
-	siteFetcher := fetcher.NewHTTPFetcher()
+	fetcherOpts := []fetcher.Option{
+		fetcher.WithContentExtraction(*extractMainContent),
+		fetcher.WithPolicy(fetcher.Policy{
+			Timeout:         *fetchTimeout,
+			MaxAttempts:     *fetchAttempts,
+			HostConcurrency: *fetchHostConcurrency,
+			HostRate:        *fetchHostRate,
+			MaxBodySize:     *fetchMaxBody,
+		}),
+	}
+	if *siteConfigPath != "" {
+		siteConfig, err := fetcher.LoadSiteConfig(*siteConfigPath)
+		if err != nil {
+			log.Fatalf("Invalid -site-config: %v", err)
+		}
+		fetcherOpts = append(fetcherOpts, fetcher.WithSiteConfig(siteConfig))
+	}
+	siteFetcher := fetcher.NewHTTPFetcher(fetcherOpts...)
 
 	// --- 3. Wire up the indexer (orchestrator) ---
 
 	var idxOpts []indexer.Option
 	idxOpts = append(idxOpts, indexer.WithLogger(logger))
+	idxOpts = append(idxOpts, indexer.WithSiteTTL(*siteTTL))
 	if embedder != nil {
 		idxOpts = append(idxOpts, indexer.WithEmbedder(embedder, embedStatus))
 		idxOpts = append(idxOpts, indexer.WithMaxConcurrentEmbeddings(*maxConcurrent))
+		idxOpts = append(idxOpts, indexer.WithEmbeddingBatchSize(*embeddingBatchSize))
+		idxOpts = append(idxOpts, indexer.WithEmbeddingJobFile(filepath.Join(*cacheDir, "embed-jobs.json")))
+		idxOpts = append(idxOpts, indexer.WithVectorIndex(vectors))
 	}
 
-	idx := indexer.New(fileCache, mdParser, searcher, fileReader, clock, siteFetcher, idxOpts...)
+	registry := parser.NewDefaultRegistry(mdParser)
+	if *notebookOutputs {
+		registry.Register(&parser.NotebookParser{Markdown: mdParser, Outputs: true}, ".ipynb")
+	}
+	idx := indexer.New(fileCache, registry, searcher, fileReader, clock, siteFetcher, idxOpts...)
+	defer idx.Close()
 
 	// --- 3. Create MCP handlers ---
 
@@ -183,9 +353,19 @@ func main() {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "docs_load_glob",
-		Description: "Load multiple markdown files matching a glob pattern (e.g. 'docs/**/*.md'). Faster than calling docs_load repeatedly.",
+		Description: "Load documentation files matching a glob pattern (e.g. 'docs/**/*'). Markdown, MDX, reStructuredText, AsciiDoc, OpenAPI, protobuf, Jupyter notebook and man page files are detected by extension or content; other files are skipped. Faster than calling docs_load repeatedly.",
 	}, handlers.DocsLoadGlob)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "docs_load_go",
+		Description: "Index Go doc comments from a local module directory: each package overview and exported type, func and method becomes a searchable excerpt with its signature, examples and file:line source link. No network needed.",
+	}, handlers.DocsLoadGo)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "docs_load_man",
+		Description: "Index installed man pages by name (e.g. 'nats' or 'git-commit(1)') from MANPATH or the system man directories. Sections (NAME, OPTIONS, EXAMPLES, ...) become excerpts with heading paths like 'nats(1) › OPTIONS'; gzipped pages are supported.",
+	}, handlers.DocsLoadMan)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "docs_query",
 		Description: "Query indexed documents. If doc_id/path omitted, searches ALL loaded docs. Returns token-bounded, source-linked excerpts.",
@@ -193,14 +373,34 @@ func main() {
 
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "site_loads",
-		Description: "Fetch multiple website URLs, convert HTML to markdown, and cache them for querying.",
+		Description: "Fetch multiple website URLs concurrently, convert HTML to markdown (markdown and plain text are kept as they are; GitHub/GitLab/Gitea file URLs are fetched raw), and cache them for querying. Failures are reported by kind (timeout, rate_limited, not_found, ...).",
 	}, handlers.SiteLoads)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "site_refresh",
+		Description: "Revalidate loaded website documents whose TTL has passed (all site documents with all=true) using conditional GETs (ETag/Last-Modified), re-indexing only pages whose content changed. docs_list shows which documents are stale.",
+	}, handlers.SiteRefresh)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "site_crawl",
+		Description: "Crawl a documentation website from a start URL, following same-site links up to max_depth/max_pages (respecting robots.txt and crawl-delay), and index each page as its own document. Pages share 'crawl' metadata, so docs_query/docs_list can target them with filters: [\"crawl:<url>\"].",
+	}, handlers.SiteCrawl)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "site_load_list",
+		Description: "Load every page listed by a sitemap.xml (or sitemap index), llms.txt or llms-full.txt URL, each as its own document; more reliable than crawling when a site publishes one. Cached pages are reused unless their sitemap lastmod is newer. Pages share 'sitemap' or 'llms' metadata (llms.txt pages also 'section'), so docs_query/docs_list can target them with filters: [\"llms:<url>\"].",
+	}, handlers.SiteLoadList)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "docs_list",
-		Description: "List all currently cached documents (from docs_load or site_load). Returns doc_id, path, and chunk count.",
+		Description: "List all currently cached documents (from docs_load or site_load). Returns doc_id, path, chunk count, front matter metadata and, for websites, whether they are stale (see site_refresh); filters (key:value) narrow the list.",
 	}, handlers.DocsList)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "docs_status",
+		Description: "Show background embedding progress: pending, running, failed and ready documents. Set retry_failed to re-queue failed jobs.",
+	}, handlers.DocsStatus)
+
 	logger.Info("server ready, waiting for requests")
 
 	// --- 5. Run the server ---